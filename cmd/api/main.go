@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/tahaburak/would-watch-backend/internal/api"
 	"github.com/tahaburak/would-watch-backend/internal/config"
 	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
 	"github.com/tahaburak/would-watch-backend/internal/openai"
 	"github.com/tahaburak/would-watch-backend/internal/service"
@@ -16,19 +23,45 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 )
 
+// newTransport builds the pooled transport shared by the TMDB and OpenAI clients
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 20
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
+// shutdownTimeout bounds how long in-flight requests get to finish after SIGINT/SIGTERM
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	cfg := config.LoadConfig()
+	appLogger := logger.Init(cfg.LogLevel)
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if cfg.OpenAIAPIKey == "" {
+		log.Printf("OPENAI_API_KEY not set, recommendations are disabled")
+	}
 
 	// Initialize Database Client
 	dbClient, err := database.NewClient(cfg.DatabaseURL)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	defer dbClient.Close()
 	log.Printf("Database client initialized")
 
+	// Request and upstream call counters served at /metrics
+	metricsRegistry := metrics.NewRegistry()
+
+	// Shared transport so outbound API calls reuse pooled connections; each API keeps its own timeout
+	transport := newTransport()
+
 	// Initialize TMDB Client
-	tmdbClient := tmdb.NewClient(cfg.TMDBAPIKey)
+	tmdbHTTPClient := &http.Client{Transport: transport, Timeout: cfg.TMDBTimeout}
+	tmdbClient := tmdb.NewClientWithHTTP(cfg.TMDBAPIKey, tmdbHTTPClient, tmdb.WithBaseURL(cfg.TMDBBaseURL), tmdb.WithImageBaseURL(cfg.TMDBImageBaseURL), tmdb.WithMetrics(metricsRegistry), tmdb.WithSearchCache(cfg.TMDBSearchCacheTTL, cfg.TMDBSearchCacheSize))
 	log.Printf("TMDB client initialized")
 
 	// Initialize Repositories
@@ -36,29 +69,51 @@ func main() {
 	sessionRepo := database.NewSessionRepository(dbClient.DB)
 	voteRepo := database.NewVoteRepository(dbClient.DB)
 	socialRepo := database.NewSocialRepository(dbClient.DB)
+	socialRepo.SearchByEmail = cfg.UserSearchByEmail
 	roomRepo := database.NewRoomRepository(dbClient.DB)
+	rewindRepo := database.NewRewindRepository(dbClient.DB)
+	idempotencyRepo := database.NewIdempotencyRepository(dbClient.DB)
+	guestTokenRepo := database.NewGuestTokenRepository(dbClient.DB)
+	recommendationRepo := database.NewRecommendationRepository(dbClient.DB)
+
+	// Live room updates are fanned out in-process
+	roomHub := api.NewRoomHub()
 
 	// Initialize Handlers
 	// Initialize Handlers
-	mediaHandler := api.NewMediaHandler(tmdbClient, mediaRepo)
-	sessionHandler := api.NewSessionHandler(sessionRepo)
-	voteHandler := api.NewVoteHandler(voteRepo, sessionRepo)
-	matchHandler := api.NewMatchHandler(voteRepo)
+	mediaHandler := api.NewMediaHandler(tmdbClient, mediaRepo, socialRepo)
+	sessionHandler := api.NewSessionHandler(sessionRepo, voteRepo, idempotencyRepo)
+	voteHandler := api.NewVoteHandler(voteRepo, sessionRepo, mediaRepo, roomHub)
+	matchHandler := api.NewMatchHandler(voteRepo, sessionRepo)
+	rewindHandler := api.NewRewindHandler(rewindRepo, tmdbClient)
+	guestHandler := api.NewGuestHandler(guestTokenRepo, sessionRepo)
 
 	// Initialize AI & Recommendations
-	openAIClient := openai.NewClient(cfg.OpenAIAPIKey)
-	recService := service.NewRecommendationService(openAIClient, tmdbClient, voteRepo, mediaRepo)
+	openAIHTTPClient := &http.Client{Transport: transport, Timeout: cfg.OpenAITimeout}
+	openAIClient := openai.NewClientWithHTTP(cfg.OpenAIAPIKey, openAIHTTPClient)
+	if cfg.OpenAIModel != "" {
+		openAIClient.Model = cfg.OpenAIModel
+	}
+	openAIClient.Metrics = metricsRegistry
+	recService := service.NewRecommendationService(openAIClient, tmdbClient, voteRepo, mediaRepo, recommendationRepo, cfg.RecommendationMinLikes)
 	recHandler := api.NewRecommendationHandler(recService)
 
+	// Initialize Admin Handler
+	adminHandler := api.NewAdminHandler(tmdbClient, voteRepo, mediaRepo)
+	adminHandler.MediaMaxAge = cfg.MediaCleanupMaxAge
+
 	// Initialize Social & Room Handlers
 	socialHandler := api.NewSocialHandler(socialRepo)
-	roomHandler := api.NewRoomHandler(roomRepo, socialRepo)
+	roomHandler := api.NewRoomHandler(roomRepo, socialRepo, idempotencyRepo, roomHub)
+	if cfg.RoomMaxInitialMembers > 0 {
+		roomHandler.MaxInitialMembers = cfg.RoomMaxInitialMembers
+	}
 
 	// Initialize Router
 	mux := http.NewServeMux()
 
-	// Apply CORS
-	handler := middleware.CORSMiddleware(mux)
+	// Apply request IDs, request logging, CORS and metrics; metrics wraps the mux so it sees the matched route
+	handler := middleware.RequestIDMiddleware(middleware.RequestLogger(appLogger)(middleware.CORSMiddlewareWithConfig(cfg.CORSAllowedOrigins, false)(middleware.MetricsMiddleware(metricsRegistry)(mux))))
 
 	// Public endpoints
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -66,21 +121,73 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 
+	// Metrics stay unauthenticated; METRICS_PORT moves them to an internal listener
+	var metricsServer *http.Server
+	if cfg.MetricsPort == "" {
+		mux.Handle("/metrics", metricsRegistry.Handler())
+	} else {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", metricsRegistry.Handler())
+		metricsServer = &http.Server{
+			Addr:    ":" + cfg.MetricsPort,
+			Handler: metricsMux,
+		}
+	}
+
 	// Auth middleware
-	authMiddleware := middleware.AuthMiddleware(cfg.SupabaseURL, cfg.SupabaseJWTSecret)
+	authMiddleware := middleware.AuthMiddleware(cfg.SupabaseURL, cfg.SupabaseJWTSecret, cfg.JWTLeeway, cfg.JWTAudience)
+	adminMiddleware := middleware.AdminMiddleware(cfg.AdminUserIDs)
+	// Voting also accepts a guest link token in place of a JWT
+	voterMiddleware := middleware.GuestTokenMiddleware(guestHandler.ResolveGuestUserID, authMiddleware)
 
 	// Protected endpoints - Media
+	// Public so <img> tags can load posters without a bearer token
+	mux.HandleFunc("/api/media/image", mediaHandler.GetImage)
 	mux.Handle("/api/media/search", authMiddleware(http.HandlerFunc(mediaHandler.SearchMovies)))
+	mux.Handle("/api/media/search/multi", authMiddleware(http.HandlerFunc(mediaHandler.SearchMulti)))
+	mux.Handle("/api/media/search/options", authMiddleware(http.HandlerFunc(mediaHandler.GetSearchOptions)))
+	mux.Handle("/api/media/discover", authMiddleware(http.HandlerFunc(mediaHandler.DiscoverByGenre)))
+	mux.Handle("/api/media/{tmdb_id}", authMiddleware(http.HandlerFunc(mediaHandler.GetMovieDetails)))
+	// /api/media/id/{uuid} and /api/media/{tmdb_id}/providers both match /api/media/id/providers, which ServeMux
+	// refuses to register, so they share one pattern and are told apart here
+	mux.Handle("/api/media/{tmdb_id}/{resource}", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("tmdb_id") == "id" {
+			mediaHandler.GetMediaByID(w, r)
+		} else if r.PathValue("resource") == "providers" {
+			mediaHandler.GetWatchProviders(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+	})))
 
 	// Protected endpoints - Sessions
 	mux.Handle("/api/sessions", authMiddleware(http.HandlerFunc(sessionHandler.CreateSession)))
-	mux.Handle("/api/sessions/", authMiddleware(http.HandlerFunc(sessionHandler.GetSession)))
+	mux.Handle("/api/sessions/", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			sessionHandler.GetSession(w, r)
+		} else if r.Method == http.MethodDelete {
+			sessionHandler.DeleteSession(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
 
 	// Protected endpoints - Voting
-	mux.Handle("/api/sessions/{id}/vote", authMiddleware(http.HandlerFunc(voteHandler.CastVote)))
+	mux.Handle("/api/sessions/{id}/vote", voterMiddleware(http.HandlerFunc(voteHandler.CastVote)))
+	mux.Handle("/api/sessions/{id}/votes", voterMiddleware(http.HandlerFunc(voteHandler.CastVotes)))
+	mux.Handle("/api/sessions/{id}/guest-link", authMiddleware(http.HandlerFunc(guestHandler.CreateGuestLink)))
+	mux.Handle("/api/sessions/{id}/participants", authMiddleware(http.HandlerFunc(sessionHandler.AddParticipant)))
 	mux.Handle("/api/sessions/{id}/complete", authMiddleware(http.HandlerFunc(sessionHandler.CompleteSession)))
+	mux.Handle("/api/sessions/{id}/snapshot", authMiddleware(http.HandlerFunc(sessionHandler.GetSnapshot)))
 	mux.Handle("/api/sessions/{id}/matches", authMiddleware(http.HandlerFunc(matchHandler.GetMatches)))
+	mux.Handle("/api/sessions/{id}/matches/unseen", authMiddleware(http.HandlerFunc(matchHandler.GetUnseenMatchCount)))
+	mux.Handle("/api/sessions/{id}/matches/check", authMiddleware(http.HandlerFunc(matchHandler.CheckMatches)))
+	mux.Handle("/api/sessions/{id}/matches/{media_id}/voters", authMiddleware(http.HandlerFunc(matchHandler.GetMatchVoters)))
+	mux.Handle("/api/sessions/{id}/intersect/{otherId}", authMiddleware(http.HandlerFunc(matchHandler.GetMatchIntersection)))
+	mux.Handle("/api/sessions/{id}/summary", authMiddleware(http.HandlerFunc(matchHandler.GetSessionSummary)))
+	mux.Handle("/api/sessions/{id}/liked", authMiddleware(http.HandlerFunc(matchHandler.GetLikedMovies)))
 	mux.Handle("/api/sessions/{id}/recommendations", authMiddleware(http.HandlerFunc(recHandler.GetRecommendations)))
+	mux.Handle("/api/sessions/{id}/recommendations/reroll", authMiddleware(http.HandlerFunc(recHandler.RerollRecommendations)))
 
 	// Protected endpoints - Social
 	mux.Handle("/api/follows/", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -92,7 +199,19 @@ func main() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})))
+	mux.Handle("/api/follows/{id}/restore", authMiddleware(http.HandlerFunc(socialHandler.RestoreFollow)))
 	mux.Handle("/api/me/following", authMiddleware(http.HandlerFunc(socialHandler.GetFollowing)))
+	mux.Handle("/api/me/friends", authMiddleware(http.HandlerFunc(socialHandler.GetFriends)))
+	mux.Handle("/api/me/following/bulk-unfollow", authMiddleware(http.HandlerFunc(socialHandler.BulkUnfollow)))
+	mux.Handle("/api/me/disliked", authMiddleware(http.HandlerFunc(matchHandler.GetDislikedMedia)))
+	mux.Handle("/api/me/likes", authMiddleware(http.HandlerFunc(matchHandler.GetUserLikes)))
+	mux.Handle("/api/me/match-count", authMiddleware(http.HandlerFunc(matchHandler.GetUserMatchCount)))
+	mux.Handle("/api/me/social-matches", authMiddleware(http.HandlerFunc(matchHandler.GetSocialMatches)))
+	mux.Handle("/api/me/rewind", authMiddleware(http.HandlerFunc(rewindHandler.GetRewind)))
+	mux.Handle("/api/me/genre-agreement", authMiddleware(http.HandlerFunc(rewindHandler.GetGenreAgreement)))
+	mux.Handle("/api/me/stats", authMiddleware(http.HandlerFunc(rewindHandler.GetStats)))
+	mux.Handle("/api/me/unfinished", authMiddleware(http.HandlerFunc(sessionHandler.GetUnfinishedSessions)))
+	mux.Handle("/api/me/invites", authMiddleware(http.HandlerFunc(roomHandler.GetInvites)))
 	mux.Handle("/api/me/profile", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			socialHandler.GetProfile(w, r)
@@ -103,6 +222,7 @@ func main() {
 		}
 	})))
 	mux.Handle("/api/users/search", authMiddleware(http.HandlerFunc(socialHandler.SearchUsers)))
+	mux.Handle("/api/users/batch", authMiddleware(http.HandlerFunc(socialHandler.GetProfilesBatch)))
 
 	// Protected endpoints - Rooms
 	mux.Handle("/api/rooms", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -114,7 +234,26 @@ func main() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})))
+	mux.Handle("/api/rooms/{id}", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			roomHandler.GetRoom(w, r)
+		} else if r.Method == http.MethodPatch {
+			roomHandler.UpdateRoom(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
 	mux.Handle("/api/rooms/{id}/invite", authMiddleware(http.HandlerFunc(roomHandler.InviteToRoom)))
+	mux.Handle("/api/rooms/{id}/invite/respond", authMiddleware(http.HandlerFunc(roomHandler.RespondToInvite)))
+	mux.Handle("/api/rooms/{id}/close", authMiddleware(http.HandlerFunc(roomHandler.CloseRoom)))
+	mux.Handle("/api/rooms/{id}/join", authMiddleware(http.HandlerFunc(roomHandler.JoinRoom)))
+	mux.Handle("/api/rooms/{id}/participants/{uid}/role", authMiddleware(http.HandlerFunc(roomHandler.SetParticipantRole)))
+	mux.Handle("/api/rooms/{id}/ws", authMiddleware(http.HandlerFunc(roomHandler.LiveUpdates)))
+
+	// Admin endpoints
+	mux.Handle("/api/admin/tmdb/refresh", authMiddleware(adminMiddleware(http.HandlerFunc(adminHandler.RefreshTMDBCaches))))
+	mux.Handle("/api/admin/orphaned-votes", authMiddleware(adminMiddleware(http.HandlerFunc(adminHandler.GetOrphanedVotes))))
+	mux.Handle("/api/admin/cleanup-media", authMiddleware(adminMiddleware(http.HandlerFunc(adminHandler.CleanupMedia))))
 
 	// Protected endpoints - User info (example)
 	mux.Handle("/api/me", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -134,23 +273,139 @@ func main() {
 	log.Printf("Server starting on port %s", cfg.Port)
 	log.Printf("Registered routes:")
 	log.Printf("  GET  /health")
+	if metricsServer == nil {
+		log.Printf("  GET  /metrics")
+	} else {
+		log.Printf("  GET  /metrics (port %s)", cfg.MetricsPort)
+	}
 	log.Printf("  GET  /api/me (protected)")
+	log.Printf("  GET  /api/media/image")
 	log.Printf("  GET  /api/media/search (protected)")
+	log.Printf("  GET  /api/media/search/multi (protected)")
+	log.Printf("  GET  /api/media/search/options (protected)")
+	log.Printf("  GET  /api/media/discover (protected)")
+	log.Printf("  GET  /api/media/{tmdb_id} (protected)")
+	log.Printf("  GET  /api/media/{tmdb_id}/providers (protected)")
+	log.Printf("  GET  /api/media/id/{uuid} (protected)")
 	log.Printf("  POST /api/sessions (protected)")
 	log.Printf("  GET  /api/sessions/{id} (protected)")
-	log.Printf("  POST /api/sessions/{id}/vote (protected)")
+	log.Printf("  DELETE /api/sessions/{id} (protected)")
+	log.Printf("  POST /api/sessions/{id}/participants (protected)")
+	log.Printf("  POST /api/sessions/{id}/vote (protected, or ?guest_token=)")
+	log.Printf("  POST /api/sessions/{id}/votes (protected, or ?guest_token=)")
+	log.Printf("  POST /api/sessions/{id}/guest-link (protected)")
 	log.Printf("  POST /api/sessions/{id}/complete (protected)")
+	log.Printf("  GET  /api/sessions/{id}/snapshot (protected)")
 	log.Printf("  GET  /api/sessions/{id}/matches (protected)")
+	log.Printf("  GET  /api/sessions/{id}/matches/unseen (protected)")
+	log.Printf("  POST /api/sessions/{id}/matches/check (protected)")
+	log.Printf("  GET  /api/sessions/{id}/matches/{media_id}/voters (protected)")
+	log.Printf("  GET  /api/sessions/{id}/intersect/{otherId} (protected)")
+	log.Printf("  GET  /api/sessions/{id}/summary (protected)")
+	log.Printf("  GET  /api/sessions/{id}/liked (protected)")
 	log.Printf("  GET  /api/sessions/{id}/recommendations (protected)")
+	log.Printf("  POST /api/sessions/{id}/recommendations/reroll (protected)")
 	log.Printf("  POST /api/follows/{id} (protected)")
 	log.Printf("  DELETE /api/follows/{id} (protected)")
+	log.Printf("  POST /api/follows/{id}/restore (protected)")
 	log.Printf("  GET  /api/me/following (protected)")
+	log.Printf("  GET  /api/me/friends (protected)")
+	log.Printf("  POST /api/me/following/bulk-unfollow (protected)")
+	log.Printf("  GET  /api/me/disliked (protected)")
+	log.Printf("  GET  /api/me/likes (protected)")
+	log.Printf("  GET  /api/me/match-count (protected)")
+	log.Printf("  GET  /api/me/social-matches (protected)")
+	log.Printf("  GET  /api/me/rewind (protected)")
+	log.Printf("  GET  /api/me/genre-agreement (protected)")
+	log.Printf("  GET  /api/me/stats (protected)")
+	log.Printf("  GET  /api/me/unfinished (protected)")
+	log.Printf("  GET  /api/me/invites (protected)")
 	log.Printf("  GET  /api/users/search (protected)")
+	log.Printf("  POST /api/users/batch (protected)")
 	log.Printf("  POST /api/rooms (protected)")
 	log.Printf("  GET  /api/rooms (protected)")
+	log.Printf("  GET  /api/rooms/{id} (protected)")
+	log.Printf("  PATCH /api/rooms/{id} (protected)")
 	log.Printf("  POST /api/rooms/{id}/invite (protected)")
+	log.Printf("  POST /api/rooms/{id}/invite/respond (protected)")
+	log.Printf("  POST /api/rooms/{id}/close (protected)")
+	log.Printf("  POST /api/rooms/{id}/join (protected)")
+	log.Printf("  POST /api/rooms/{id}/participants/{uid}/role (protected)")
+	log.Printf("  GET  /api/rooms/{id}/ws (protected, WebSocket)")
+	log.Printf("  GET  /api/admin/tmdb/refresh (admin)")
+	log.Printf("  GET  /api/admin/orphaned-votes (admin)")
+	log.Printf("  POST /api/admin/cleanup-media (admin)")
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: handler,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	precacheWorker := service.NewPrecacheWorker(tmdbClient, mediaRepo, metricsRegistry, cfg.PrecacheInterval)
+	precacheDone := make(chan struct{})
+	go func() {
+		defer close(precacheDone)
+		precacheWorker.Run(ctx)
+	}()
+	log.Printf("Pre-caching now-playing and trending movies every %s", cfg.PrecacheInterval)
+
+	mediaCleanupWorker := service.NewMediaCleanupWorker(mediaRepo, metricsRegistry, cfg.MediaCleanupInterval, cfg.MediaCleanupMaxAge)
+	mediaCleanupDone := make(chan struct{})
+	go func() {
+		defer close(mediaCleanupDone)
+		mediaCleanupWorker.Run(ctx)
+	}()
+	log.Printf("Purging unvoted media older than %s every %s", cfg.MediaCleanupMaxAge, cfg.MediaCleanupInterval)
+
+	if metricsServer != nil {
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Metrics server failed: %v", err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+	case <-ctx.Done():
+		stop()
+		log.Printf("Shutdown signal received, draining connections (timeout %s)", shutdownTimeout)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Server shutdown did not complete cleanly: %v", err)
+		} else {
+			log.Printf("Server stopped accepting requests")
+		}
+		if metricsServer != nil {
+			if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Metrics server shutdown did not complete cleanly: %v", err)
+			}
+		}
+	}
+
+	// The workers may be mid-run, so wait for them before the database goes away
+	stop()
+	<-precacheDone
+	<-mediaCleanupDone
 
-	if err := http.ListenAndServe(":"+cfg.Port, handler); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	if err := dbClient.Close(); err != nil {
+		log.Printf("Failed to close database client: %v", err)
+	} else {
+		log.Printf("Database client closed")
 	}
+	log.Printf("Shutdown complete")
 }
@@ -0,0 +1,13 @@
+-- Would Watch Backend - Enum Value Upgrade
+-- Run this on its own, before schema.sql, when upgrading an existing database.
+-- Postgres cannot use an enum value in the transaction that added it, so these values
+-- must be committed before schema.sql migrates rows to them. A fresh database can skip it.
+
+DO $$
+BEGIN
+    -- 'admin' and 'viewer' were renamed to 'moderator' and 'member'
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'participant_role') THEN
+        ALTER TYPE participant_role ADD VALUE IF NOT EXISTS 'moderator';
+        ALTER TYPE participant_role ADD VALUE IF NOT EXISTS 'member';
+    END IF;
+END $$;
@@ -13,11 +13,14 @@ EXCEPTION
 END $$;
 
 DO $$ BEGIN
-    CREATE TYPE session_status AS ENUM ('active', 'completed');
+    CREATE TYPE session_status AS ENUM ('active', 'completed', 'closed');
 EXCEPTION
     WHEN duplicate_object THEN null;
 END $$;
 
+-- Added after the initial release; keeps existing databases in sync
+ALTER TYPE session_status ADD VALUE IF NOT EXISTS 'closed';
+
 DO $$ BEGIN
     CREATE TYPE vote_type AS ENUM ('yes', 'no', 'maybe');
 EXCEPTION
@@ -31,7 +34,7 @@ EXCEPTION
 END $$;
 
 DO $$ BEGIN
-    CREATE TYPE participant_role AS ENUM ('owner', 'admin', 'viewer');
+    CREATE TYPE participant_role AS ENUM ('owner', 'moderator', 'member');
 EXCEPTION
     WHEN duplicate_object THEN null;
 END $$;
@@ -52,20 +55,28 @@ CREATE TABLE IF NOT EXISTS profiles (
     username TEXT UNIQUE,
     avatar_url TEXT,
     invite_preference invite_preference NOT NULL DEFAULT 'following',
+    include_adult BOOLEAN NOT NULL DEFAULT false,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Added after the initial release; keeps existing databases in sync
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS include_adult BOOLEAN NOT NULL DEFAULT false;
+
 -- User Follows Table (Social Graph)
 CREATE TABLE IF NOT EXISTS user_follows (
     follower_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
     following_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
     created_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ, -- Set on unfollow; the row can be restored for a short window
 
     PRIMARY KEY (follower_id, following_id),
     CONSTRAINT no_self_follow CHECK (follower_id != following_id)
 );
 
+-- Added after the initial release; keeps existing databases in sync
+ALTER TABLE user_follows ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
 -- Media Items Table
 -- Stores movie and TV show information from TMDB
 CREATE TABLE IF NOT EXISTS media_items (
@@ -91,32 +102,174 @@ CREATE TABLE IF NOT EXISTS watch_sessions (
     is_public BOOLEAN DEFAULT false,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW(),
-    completed_at TIMESTAMPTZ
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ -- Optional voting deadline
 );
 
+-- Added after the initial release; keeps existing databases in sync
+ALTER TABLE watch_sessions ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
 -- Room Participants Table
 CREATE TABLE IF NOT EXISTS room_participants (
     room_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
     user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
-    role participant_role NOT NULL DEFAULT 'viewer',
+    role participant_role NOT NULL DEFAULT 'member',
     status participant_status NOT NULL DEFAULT 'invited',
     joined_at TIMESTAMPTZ,
+    invited_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 
     PRIMARY KEY (room_id, user_id)
 );
 
+-- Added after the initial release; keeps existing databases in sync
+-- Participants added before invites needed accepting were stored as 'invited' but treated as members,
+-- so rows without invited_at are marked joined once, before the column is filled in
+ALTER TABLE room_participants ADD COLUMN IF NOT EXISTS invited_at TIMESTAMPTZ;
+UPDATE room_participants
+SET status = 'joined', joined_at = COALESCE(joined_at, NOW())
+WHERE invited_at IS NULL AND status = 'invited';
+UPDATE room_participants SET invited_at = COALESCE(joined_at, NOW()) WHERE invited_at IS NULL;
+ALTER TABLE room_participants ALTER COLUMN invited_at SET DEFAULT NOW();
+ALTER TABLE room_participants ALTER COLUMN invited_at SET NOT NULL;
+
+-- Added after the initial release; keeps existing databases in sync
+-- 'admin' and 'viewer' were renamed to 'moderator' and 'member'; enum_values.sql must have added those values first
+UPDATE room_participants SET role = 'moderator' WHERE role::text = 'admin';
+UPDATE room_participants SET role = 'member' WHERE role::text = 'viewer';
+ALTER TABLE room_participants ALTER COLUMN role SET DEFAULT 'member';
+
 -- Session Votes Table
 -- Stores user votes for media items within watch sessions
 CREATE TABLE IF NOT EXISTS session_votes (
     session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
-    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL, -- A profile, or a guest_voters row for guest votes
     media_id UUID NOT NULL REFERENCES media_items(id) ON DELETE CASCADE,
+    season_number INTEGER CHECK (season_number >= 0),
     vote vote_type NOT NULL,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW(),
 
-    -- Unique constraint: one vote per user per media per session
-    CONSTRAINT unique_user_media_session UNIQUE (session_id, user_id, media_id)
+    -- Unique constraint: one vote per user per media (and season) per session
+    -- NULLS NOT DISTINCT keeps movie votes, which have no season, at one per user
+    CONSTRAINT unique_user_media_session UNIQUE NULLS NOT DISTINCT (session_id, user_id, media_id, season_number)
+);
+
+-- Added after the initial release; keeps existing databases in sync
+ALTER TABLE session_votes ADD COLUMN IF NOT EXISTS season_number INTEGER CHECK (season_number >= 0);
+
+-- Widen the original (session_id, user_id, media_id) constraint to include the season
+DO $$ BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'session_votes'::regclass
+        AND conname = 'unique_user_media_session'
+        AND cardinality(conkey) = 4
+    ) THEN
+        ALTER TABLE session_votes DROP CONSTRAINT IF EXISTS unique_user_media_session;
+        ALTER TABLE session_votes ADD CONSTRAINT unique_user_media_session
+            UNIQUE NULLS NOT DISTINCT (session_id, user_id, media_id, season_number);
+    END IF;
+END $$;
+
+-- Session Candidates Table
+-- Media queued for voting in a session, e.g. a deck cloned from a prior session
+CREATE TABLE IF NOT EXISTS session_candidates (
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media_items(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, media_id)
+);
+
+-- Idempotency Keys Table
+-- Remembers the room or session a creation request made, so a retried request returns it instead of a duplicate
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    resource_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    status_code INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, scope, key)
+);
+
+-- Guest Voters Table
+-- Identities for people voting in one session through a guest link; guests have no account or profile
+CREATE TABLE IF NOT EXISTS guest_voters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Session Guest Tokens Table
+-- Short-lived links that let someone without an account vote in one session as a guest voter
+CREATE TABLE IF NOT EXISTS session_guest_tokens (
+    token_hash TEXT PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    guest_id UUID NOT NULL REFERENCES guest_voters(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Session Recommendations Table
+-- TMDB IDs already recommended in a session, so a re-roll can ask for different ones
+CREATE TABLE IF NOT EXISTS session_recommendations (
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    tmdb_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, tmdb_id)
+);
+
+-- Vote Events Table
+-- Append-only log of every vote cast, so vote changes can be analysed
+CREATE TABLE IF NOT EXISTS vote_events (
+    id BIGSERIAL PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL, -- A profile, or a guest_voters row for guest votes
+    media_id UUID NOT NULL REFERENCES media_items(id) ON DELETE CASCADE,
+    season_number INTEGER,
+    vote vote_type NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Added after the initial release; keeps existing databases in sync
+ALTER TABLE vote_events ADD COLUMN IF NOT EXISTS season_number INTEGER;
+
+-- Guests vote without a profile, so the voter foreign keys give way to the delete_voter_votes triggers
+ALTER TABLE session_votes DROP CONSTRAINT IF EXISTS session_votes_user_id_fkey;
+ALTER TABLE vote_events DROP CONSTRAINT IF EXISTS vote_events_user_id_fkey;
+
+-- Session Matches Table
+-- One row per media item that reached a match in a session, claimed by the vote that crossed the threshold
+CREATE TABLE IF NOT EXISTS session_matches (
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media_items(id) ON DELETE CASCADE,
+    matched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, media_id)
+);
+
+-- Session Snapshots Table
+-- Who had voted in a session when it was completed, kept so results can say how many people took part
+CREATE TABLE IF NOT EXISTS session_snapshots (
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    snapshot_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, user_id)
+);
+
+-- Session Views Table
+-- When each user last looked at a session's matches, so clients can badge new ones
+CREATE TABLE IF NOT EXISTS session_views (
+    session_id UUID NOT NULL REFERENCES watch_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    last_viewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, user_id)
 );
 
 -- ============================================================================
@@ -167,6 +320,22 @@ CREATE INDEX IF NOT EXISTS idx_session_votes_media
 CREATE INDEX IF NOT EXISTS idx_session_votes_session_user
     ON session_votes(session_id, user_id);
 
+-- Index for counting a user's likes, e.g. GET /api/me/stats
+CREATE INDEX IF NOT EXISTS idx_session_votes_user_vote
+    ON session_votes(user_id, vote);
+
+-- Index for guest tokens by session
+CREATE INDEX IF NOT EXISTS idx_session_guest_tokens_session
+    ON session_guest_tokens(session_id);
+
+-- Index for candidates by media
+CREATE INDEX IF NOT EXISTS idx_session_candidates_media
+    ON session_candidates(media_id);
+
+-- Composite index for a user's vote history in a session
+CREATE INDEX IF NOT EXISTS idx_vote_events_session_user
+    ON vote_events(session_id, user_id);
+
 -- Index for profile username lookups
 CREATE INDEX IF NOT EXISTS idx_profiles_username
     ON profiles(username);
@@ -228,6 +397,29 @@ CREATE TRIGGER update_session_votes_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
 
+-- Function to remove a deleted voter's votes, standing in for the foreign keys guest votes can't satisfy
+CREATE OR REPLACE FUNCTION delete_voter_votes()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM session_votes WHERE user_id = OLD.id;
+    DELETE FROM vote_events WHERE user_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Triggers for profiles and guest_voters
+DROP TRIGGER IF EXISTS delete_profile_votes ON profiles;
+CREATE TRIGGER delete_profile_votes
+    AFTER DELETE ON profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_voter_votes();
+
+DROP TRIGGER IF EXISTS delete_guest_voter_votes ON guest_voters;
+CREATE TRIGGER delete_guest_voter_votes
+    AFTER DELETE ON guest_voters
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_voter_votes();
+
 -- Function and Trigger to create profile on Signup
 CREATE OR REPLACE FUNCTION public.handle_new_user()
 RETURNS TRIGGER AS $$
@@ -258,6 +450,15 @@ ALTER TABLE user_follows ENABLE ROW LEVEL SECURITY;
 ALTER TABLE room_participants ENABLE ROW LEVEL SECURITY;
 ALTER TABLE watch_sessions ENABLE ROW LEVEL SECURITY;
 ALTER TABLE session_votes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_candidates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE guest_voters ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_guest_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_recommendations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE vote_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_views ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_matches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_snapshots ENABLE ROW LEVEL SECURITY;
 ALTER TABLE media_items ENABLE ROW LEVEL SECURITY;
 
 -- Profiles Policies
@@ -305,6 +506,15 @@ CREATE POLICY "Users can unfollow"
     TO authenticated
     USING (auth.uid() = follower_id);
 
+-- Unfollows are soft deletes, so unfollowing and restoring both update the row
+DROP POLICY IF EXISTS "Users can update their follows" ON user_follows;
+CREATE POLICY "Users can update their follows"
+    ON user_follows
+    FOR UPDATE
+    TO authenticated
+    USING (auth.uid() = follower_id)
+    WITH CHECK (auth.uid() = follower_id);
+
 -- Watch Sessions Policies
 DROP POLICY IF EXISTS "Users can read public sessions" ON watch_sessions;
 CREATE POLICY "Users can read public sessions"
@@ -391,6 +601,28 @@ CREATE POLICY "Users can read session votes"
         )
     );
 
+-- Session Candidates Policies
+DROP POLICY IF EXISTS "Users can read session candidates" ON session_candidates;
+CREATE POLICY "Users can read session candidates"
+    ON session_candidates
+    FOR SELECT
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM watch_sessions
+            WHERE watch_sessions.id = session_candidates.session_id
+            AND (
+                watch_sessions.is_public = true OR
+                watch_sessions.creator_id = auth.uid() OR
+                EXISTS (
+                    SELECT 1 FROM room_participants
+                    WHERE room_participants.room_id = watch_sessions.id
+                    AND room_participants.user_id = auth.uid()
+                )
+            )
+        )
+    );
+
 DROP POLICY IF EXISTS "Users can insert session votes" ON session_votes;
 CREATE POLICY "Users can insert session votes"
     ON session_votes
@@ -402,6 +634,7 @@ CREATE POLICY "Users can insert session votes"
             SELECT 1 FROM room_participants
             WHERE room_participants.room_id = session_votes.session_id
             AND room_participants.user_id = auth.uid()
+            AND room_participants.status = 'joined'
         )
     );
 
@@ -432,26 +665,60 @@ COMMENT ON COLUMN media_items.metadata IS 'JSONB field storing poster_path, over
 
 COMMENT ON TABLE watch_sessions IS 'Group watch sessions for collaborative movie selection';
 COMMENT ON COLUMN watch_sessions.creator_id IS 'User ID of the session creator (references auth.users)';
-COMMENT ON COLUMN watch_sessions.status IS 'Session status: active or completed';
+COMMENT ON COLUMN watch_sessions.status IS 'Session status: active, completed, or closed';
 COMMENT ON COLUMN watch_sessions.completed_at IS 'Timestamp when session was marked as completed';
+COMMENT ON COLUMN watch_sessions.expires_at IS 'Voting deadline; votes are rejected once it passes. NULL never expires';
 
 COMMENT ON TABLE session_votes IS 'Stores user votes for media items within watch sessions';
 COMMENT ON COLUMN session_votes.vote IS 'User vote: yes, no, or maybe';
 COMMENT ON COLUMN session_votes.session_id IS 'Watch session this vote belongs to';
-COMMENT ON COLUMN session_votes.user_id IS 'User who cast this vote (references profiles)';
+COMMENT ON COLUMN session_votes.user_id IS 'Profile or guest voter who cast this vote';
 COMMENT ON COLUMN session_votes.media_id IS 'Media item being voted on';
+COMMENT ON COLUMN session_votes.season_number IS 'TV season the vote is for; NULL for movies and whole-show votes';
+
+COMMENT ON TABLE session_candidates IS 'Media queued for voting in a watch session';
+COMMENT ON COLUMN session_candidates.session_id IS 'Watch session the candidate belongs to';
+COMMENT ON COLUMN session_candidates.media_id IS 'Media item queued for voting';
+
+COMMENT ON TABLE idempotency_keys IS 'Idempotency-Key header values seen on room and session creation';
+COMMENT ON COLUMN idempotency_keys.scope IS 'Creation endpoint the key was used on: room or session';
+COMMENT ON COLUMN idempotency_keys.resource_id IS 'Room or session created by the first request with this key';
+COMMENT ON COLUMN idempotency_keys.status_code IS 'HTTP status returned to the first request, replayed on retries';
+COMMENT ON TABLE guest_voters IS 'People voting in one session through a guest link, without an account';
+COMMENT ON COLUMN guest_voters.session_id IS 'The only session the guest may vote in';
+COMMENT ON TABLE session_guest_tokens IS 'Guest voting links minted by session creators';
+COMMENT ON COLUMN session_guest_tokens.token_hash IS 'SHA-256 of the token; the token itself is only shown when minted';
+COMMENT ON COLUMN session_guest_tokens.guest_id IS 'Guest voter created for the link, used as the user ID of guest votes';
+COMMENT ON COLUMN session_guest_tokens.expires_at IS 'Time after which the token is no longer accepted';
+COMMENT ON TABLE session_recommendations IS 'AI recommendations already shown for a session, excluded when re-rolling';
+
+COMMENT ON TABLE vote_events IS 'Append-only history of every vote cast; session_votes keeps only the latest';
+COMMENT ON COLUMN vote_events.vote IS 'Vote value at the time it was cast: yes, no, or maybe';
+COMMENT ON COLUMN vote_events.season_number IS 'TV season the vote was for; NULL for movies and whole-show votes';
+
+COMMENT ON TABLE session_views IS 'When each user last viewed the matches of a session';
+COMMENT ON COLUMN session_views.last_viewed_at IS 'Matches made after this time count as unseen';
+
+COMMENT ON TABLE session_matches IS 'Matches that have been announced, so concurrent votes notify only once';
+COMMENT ON COLUMN session_matches.matched_at IS 'When the match was first claimed; later vote changes leave it in place';
+
+COMMENT ON TABLE session_snapshots IS 'Participants of a session, captured when it was completed';
+COMMENT ON COLUMN session_snapshots.snapshot_at IS 'When the session was completed and the participant recorded';
 
 COMMENT ON TABLE profiles IS 'User profile information and privacy settings';
 COMMENT ON COLUMN profiles.id IS 'User ID (references auth.users)';
 COMMENT ON COLUMN profiles.username IS 'Unique username for the user';
 COMMENT ON COLUMN profiles.invite_preference IS 'Privacy setting for room invitations';
+COMMENT ON COLUMN profiles.include_adult IS 'Whether movie search may return adult titles; off unless the user opts in';
 
 COMMENT ON TABLE user_follows IS 'Social graph adjacency list for follower relationships';
 COMMENT ON COLUMN user_follows.follower_id IS 'User who is following (references profiles)';
 COMMENT ON COLUMN user_follows.following_id IS 'User who is being followed (references profiles)';
+COMMENT ON COLUMN user_follows.deleted_at IS 'When the follow was undone; NULL for active follows. Rows past the restore window are purged';
 
 COMMENT ON TABLE room_participants IS 'Tracks which users are in which rooms';
 COMMENT ON COLUMN room_participants.room_id IS 'Room (watch session) the user is in';
 COMMENT ON COLUMN room_participants.user_id IS 'User participating in the room (references profiles)';
-COMMENT ON COLUMN room_participants.role IS 'Participant role: owner, admin, or viewer';
+COMMENT ON COLUMN room_participants.role IS 'Participant role: owner, moderator, or member';
 COMMENT ON COLUMN room_participants.status IS 'Participant status: invited, joined, or declined';
+COMMENT ON COLUMN room_participants.invited_at IS 'When the user was last invited; orders pending invites';
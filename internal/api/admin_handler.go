@@ -0,0 +1,106 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+// AdminHandler handles operator maintenance endpoints
+type AdminHandler struct {
+	tmdbClient *tmdb.Client
+	voteRepo   *database.VoteRepository
+	mediaRepo  *database.MediaRepository
+	// MediaMaxAge is how long unvoted media is kept before CleanupMedia deletes it
+	MediaMaxAge time.Duration
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(tmdbClient *tmdb.Client, voteRepo *database.VoteRepository, mediaRepo *database.MediaRepository) *AdminHandler {
+	return &AdminHandler{
+		tmdbClient:  tmdbClient,
+		voteRepo:    voteRepo,
+		mediaRepo:   mediaRepo,
+		MediaMaxAge: database.DefaultUnreferencedMediaMaxAge,
+	}
+}
+
+// RefreshTMDBCaches handles GET /api/admin/tmdb/refresh
+func (h *AdminHandler) RefreshTMDBCaches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.tmdbClient.RefreshCaches(r.Context()); err != nil {
+		log.Printf("Error refreshing TMDB caches: %v", err)
+		writeJSONError(w, http.StatusBadGateway, errCodeUpstream, "Failed to refresh TMDB caches")
+		return
+	}
+
+	genres, err := h.tmdbClient.GetGenres(r.Context())
+	if err != nil {
+		log.Printf("Error reading refreshed genres: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to read genres")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"genre_count": len(genres),
+	})
+}
+
+// GetOrphanedVotes handles GET /api/admin/orphaned-votes
+func (h *AdminHandler) GetOrphanedVotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	ctx := r.Context()
+
+	votes, err := h.voteRepo.FindOrphanedVotes(ctx)
+	if err != nil {
+		log.Printf("Error finding orphaned votes: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to find orphaned votes")
+		return
+	}
+
+	if votes == nil {
+		votes = []database.Vote{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"votes": votes,
+		"count": len(votes),
+	})
+}
+
+// CleanupMedia handles POST /api/admin/cleanup-media
+// Runs the stale media purge immediately instead of waiting for the background job
+func (h *AdminHandler) CleanupMedia(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	ctx := r.Context()
+
+	olderThan := time.Now().Add(-h.MediaMaxAge)
+	deleted, err := h.mediaRepo.DeleteUnreferencedMedia(ctx, olderThan)
+	if err != nil {
+		log.Printf("Error cleaning up media: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to clean up media")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"deleted":    deleted,
+		"older_than": olderThan,
+	})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+func TestAdminHandler_RefreshTMDBCaches(t *testing.T) {
+	var genreHits int32
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genre/movie/list":
+			atomic.AddInt32(&genreHits, 1)
+			w.Write([]byte(`{"genres":[{"id":28,"name":"Action"}]}`))
+		case "/configuration":
+			w.Write([]byte(`{"images":{"secure_base_url":"https://image.tmdb.org/t/p/"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer tmdbServer.Close()
+
+	tmdbClient := tmdb.NewClient("test-key")
+	tmdbClient.BaseURL = tmdbServer.URL
+
+	// Warm the cache so the refresh has something to replace
+	if _, err := tmdbClient.GetGenres(context.Background()); err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+
+	handler := NewAdminHandler(tmdbClient, nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.RefreshTMDBCaches(rec, httptest.NewRequest(http.MethodGet, "/api/admin/tmdb/refresh", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if got := atomic.LoadInt32(&genreHits); got != 2 {
+		t.Errorf("Expected genres to be refetched immediately, got %d requests", got)
+	}
+}
+
+func TestAdminHandler_CleanupMedia(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewAdminHandler(nil, nil, database.NewMediaRepository(db))
+
+	t.Run("runs the purge", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CleanupMedia(rec, httptest.NewRequest(http.MethodPost, "/api/admin/cleanup-media", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if connector.queryCount() != 1 {
+			t.Errorf("Expected one delete, got %d queries", connector.queryCount())
+		}
+	})
+
+	t.Run("rejects GET", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CleanupMedia(rec, httptest.NewRequest(http.MethodGet, "/api/admin/cleanup-media", nil))
+
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405, got %d", rec.Code)
+		}
+	})
+}
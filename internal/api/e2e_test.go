@@ -31,13 +31,17 @@ func NewTestServer(t *testing.T) *TestServer {
 	socialRepo := database.NewSocialRepository(testDB.DB)
 	sessionRepo := database.NewSessionRepository(testDB.DB)
 	voteRepo := database.NewVoteRepository(testDB.DB)
+	idempotencyRepo := database.NewIdempotencyRepository(testDB.DB)
+	guestTokenRepo := database.NewGuestTokenRepository(testDB.DB)
 
 	// Initialize Handlers
-	roomHandler := NewRoomHandler(roomRepo, socialRepo)
+	roomHub := NewRoomHub()
+	roomHandler := NewRoomHandler(roomRepo, socialRepo, idempotencyRepo, roomHub)
 	socialHandler := NewSocialHandler(socialRepo)
-	sessionHandler := NewSessionHandler(sessionRepo)
-	voteHandler := NewVoteHandler(voteRepo, sessionRepo)
-	matchHandler := NewMatchHandler(voteRepo)
+	sessionHandler := NewSessionHandler(sessionRepo, voteRepo, idempotencyRepo)
+	voteHandler := NewVoteHandler(voteRepo, sessionRepo, database.NewMediaRepository(testDB.DB), roomHub)
+	matchHandler := NewMatchHandler(voteRepo, sessionRepo)
+	guestHandler := NewGuestHandler(guestTokenRepo, sessionRepo)
 
 	// Create router
 	mux := http.NewServeMux()
@@ -55,6 +59,8 @@ func NewTestServer(t *testing.T) *TestServer {
 		})
 	}
 
+	mockVoterMiddleware := middleware.GuestTokenMiddleware(guestHandler.ResolveGuestUserID, mockAuthMiddleware)
+
 	// Register routes (same as main.go but with mock auth)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -72,6 +78,20 @@ func NewTestServer(t *testing.T) *TestServer {
 		}
 	})))
 	mux.Handle("/api/rooms/", mockAuthMiddleware(http.HandlerFunc(roomHandler.InviteToRoom)))
+	mux.Handle("/api/rooms/{id}", mockAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			roomHandler.GetRoom(w, r)
+		} else if r.Method == http.MethodPatch {
+			roomHandler.UpdateRoom(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	mux.Handle("/api/rooms/{id}/invite/respond", mockAuthMiddleware(http.HandlerFunc(roomHandler.RespondToInvite)))
+	mux.Handle("/api/rooms/{id}/close", mockAuthMiddleware(http.HandlerFunc(roomHandler.CloseRoom)))
+	mux.Handle("/api/rooms/{id}/join", mockAuthMiddleware(http.HandlerFunc(roomHandler.JoinRoom)))
+	mux.Handle("/api/rooms/{id}/participants/{uid}/role", mockAuthMiddleware(http.HandlerFunc(roomHandler.SetParticipantRole)))
+	mux.Handle("/api/rooms/{id}/ws", mockAuthMiddleware(http.HandlerFunc(roomHandler.LiveUpdates)))
 
 	// Protected endpoints - Social
 	mux.Handle("/api/follows/", mockAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -83,16 +103,38 @@ func NewTestServer(t *testing.T) *TestServer {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})))
+	mux.Handle("/api/follows/{id}/restore", mockAuthMiddleware(http.HandlerFunc(socialHandler.RestoreFollow)))
 	mux.Handle("/api/me/following", mockAuthMiddleware(http.HandlerFunc(socialHandler.GetFollowing)))
+	mux.Handle("/api/me/friends", mockAuthMiddleware(http.HandlerFunc(socialHandler.GetFriends)))
+	mux.Handle("/api/me/invites", mockAuthMiddleware(http.HandlerFunc(roomHandler.GetInvites)))
 	mux.Handle("/api/users/search", mockAuthMiddleware(http.HandlerFunc(socialHandler.SearchUsers)))
 
 	// Protected endpoints - Sessions
 	mux.Handle("/api/sessions", mockAuthMiddleware(http.HandlerFunc(sessionHandler.CreateSession)))
-	mux.Handle("/api/sessions/", mockAuthMiddleware(http.HandlerFunc(sessionHandler.GetSession)))
+	mux.Handle("/api/sessions/", mockAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			sessionHandler.GetSession(w, r)
+		} else if r.Method == http.MethodDelete {
+			sessionHandler.DeleteSession(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
 
 	// Protected endpoints - Voting
-	mux.Handle("/api/sessions/{id}/vote", mockAuthMiddleware(http.HandlerFunc(voteHandler.CastVote)))
+	mux.Handle("/api/sessions/{id}/participants", mockAuthMiddleware(http.HandlerFunc(sessionHandler.AddParticipant)))
+	mux.Handle("/api/sessions/{id}/vote", mockVoterMiddleware(http.HandlerFunc(voteHandler.CastVote)))
+	mux.Handle("/api/sessions/{id}/votes", mockVoterMiddleware(http.HandlerFunc(voteHandler.CastVotes)))
+	mux.Handle("/api/sessions/{id}/guest-link", mockAuthMiddleware(http.HandlerFunc(guestHandler.CreateGuestLink)))
+	mux.Handle("/api/sessions/{id}/complete", mockAuthMiddleware(http.HandlerFunc(sessionHandler.CompleteSession)))
+	mux.Handle("/api/sessions/{id}/snapshot", mockAuthMiddleware(http.HandlerFunc(sessionHandler.GetSnapshot)))
 	mux.Handle("/api/sessions/{id}/matches", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetMatches)))
+	mux.Handle("/api/sessions/{id}/matches/unseen", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetUnseenMatchCount)))
+	mux.Handle("/api/sessions/{id}/matches/check", mockAuthMiddleware(http.HandlerFunc(matchHandler.CheckMatches)))
+	mux.Handle("/api/sessions/{id}/matches/{media_id}/voters", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetMatchVoters)))
+	mux.Handle("/api/sessions/{id}/intersect/{otherId}", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetMatchIntersection)))
+	mux.Handle("/api/sessions/{id}/summary", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetSessionSummary)))
+	mux.Handle("/api/sessions/{id}/liked", mockAuthMiddleware(http.HandlerFunc(matchHandler.GetLikedMovies)))
 
 	// Create test server
 	server := httptest.NewServer(mux)
@@ -144,3 +186,8 @@ func (ts *TestServer) PUT(path string) *httpexpect.Request {
 func (ts *TestServer) DELETE(path string) *httpexpect.Request {
 	return ts.Expect.DELETE(path).WithHeader("X-Test-User-ID", ts.MockUserID)
 }
+
+// PATCH creates a PATCH request with the mock user ID header
+func (ts *TestServer) PATCH(path string) *httpexpect.Request {
+	return ts.Expect.PATCH(path).WithHeader("X-Test-User-ID", ts.MockUserID)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_GuestVoting(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	hostID := uuid.New()
+	ts.DB.SeedProfile(t, hostID, "guest_host")
+
+	strangerID := uuid.New()
+	ts.DB.SeedProfile(t, strangerID, "guest_stranger")
+
+	sessionID := ts.DB.SeedWatchSession(t, hostID, "Casual Night", false)
+	mediaID := ts.DB.SeedMediaItem(t, 27205, "movie", "Inception")
+
+	linkPath := "/api/sessions/" + sessionID.String() + "/guest-link"
+	votePath := "/api/sessions/" + sessionID.String() + "/vote"
+	vote := map[string]interface{}{
+		"media_id": mediaID.String(),
+		"vote":     "yes",
+	}
+
+	t.Run("only the creator can mint a link", func(t *testing.T) {
+		ts.SetMockUserID(strangerID.String())
+
+		ts.POST(linkPath).
+			Expect().
+			Status(403)
+	})
+
+	ts.SetMockUserID(hostID.String())
+	link := ts.POST(linkPath).
+		Expect().
+		Status(201).
+		JSON().Object()
+
+	link.ValueEqual("session_id", sessionID.String())
+	token := link.Value("token").String().NotEmpty().Raw()
+	guestID := link.Value("guest_id").String().Raw()
+
+	t.Run("a valid guest token can vote", func(t *testing.T) {
+		ts.POST(votePath).
+			WithQuery("guest_token", token).
+			WithJSON(vote).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+
+		var stored string
+		err := ts.DB.DB.QueryRow(`SELECT vote FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3`, sessionID, guestID, mediaID).Scan(&stored)
+		if err != nil {
+			t.Fatalf("Expected the guest vote to be stored: %v", err)
+		}
+		if stored != "yes" {
+			t.Errorf("Expected stored vote 'yes', got '%s'", stored)
+		}
+	})
+
+	t.Run("the guest matches with the host like any voter", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+
+		ts.POST(votePath).
+			WithJSON(vote).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("is_match", true)
+
+		voters := ts.GET("/api/sessions/" + sessionID.String() + "/matches/" + mediaID.String() + "/voters").
+			Expect().
+			Status(200).
+			JSON().Object()
+		voters.ValueEqual("count", 2)
+		voters.Value("voters").Array().Element(0).Object().ValueEqual("id", guestID).NotContainsKey("username")
+	})
+
+	t.Run("a guest token cannot vote in another session", func(t *testing.T) {
+		otherSession := ts.DB.SeedWatchSession(t, hostID, "Other Night", false)
+
+		ts.POST("/api/sessions/"+otherSession.String()+"/vote").
+			WithQuery("guest_token", token).
+			WithJSON(vote).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("an unknown guest token is rejected", func(t *testing.T) {
+		ts.POST(votePath).
+			WithQuery("guest_token", "not-a-token").
+			WithJSON(vote).
+			Expect().
+			Status(401).
+			JSON().Object().
+			Value("error").Object().
+			ValueEqual("code", "unauthorized")
+	})
+
+	t.Run("an expired guest token is rejected", func(t *testing.T) {
+		if _, err := ts.DB.DB.Exec(`UPDATE session_guest_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE session_id = $1`, sessionID); err != nil {
+			t.Fatalf("Failed to expire guest token: %v", err)
+		}
+
+		ts.POST(votePath).
+			WithQuery("guest_token", token).
+			WithJSON(vote).
+			Expect().
+			Status(401)
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+// GuestHandler handles guest voting link endpoints
+type GuestHandler struct {
+	guestRepo   *database.GuestTokenRepository
+	sessionRepo *database.SessionRepository
+}
+
+// NewGuestHandler creates a new guest handler
+func NewGuestHandler(guestRepo *database.GuestTokenRepository, sessionRepo *database.SessionRepository) *GuestHandler {
+	return &GuestHandler{
+		guestRepo:   guestRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// CreateGuestLink handles POST /api/sessions/{id}/guest-link
+// Only the session creator may mint a link; the token is returned once and passed as ?guest_token= when voting
+func (h *GuestHandler) CreateGuestLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/guest-link
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "guest-link" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	ctx := r.Context()
+
+	session, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting session: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
+		return
+	}
+
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	if session.CreatorID != userID {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only the session creator can create guest links")
+		return
+	}
+
+	if session.Status != "active" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Session is not active")
+		return
+	}
+
+	if database.IsExpired(session) {
+		writeJSONError(w, http.StatusGone, errCodeSessionExpired, "Voting for this session has ended")
+		return
+	}
+
+	guest, err := h.guestRepo.CreateGuestToken(ctx, sessionID, userID)
+	if err != nil {
+		log.Printf("Error creating guest token: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create guest link")
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, guest)
+}
+
+// ResolveGuestUserID is a middleware.GuestTokenResolver backed by the guest token repository
+func (h *GuestHandler) ResolveGuestUserID(ctx context.Context, token string) (string, string, error) {
+	guest, err := h.guestRepo.ResolveGuestToken(ctx, token)
+	if err != nil || guest == nil {
+		return "", "", err
+	}
+	return guest.GuestID.String(), guest.SessionID.String(), nil
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+)
+
+// idempotencyKeyHeader lets clients retry a creation request without creating a duplicate
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the header so keys stay cheap to store and index
+const maxIdempotencyKeyLength = 255
+
+// idempotencyKey returns the request's Idempotency-Key header, or "" when none was sent
+// ok is false when the key is too long to accept
+func idempotencyKey(r *http.Request) (key string, ok bool) {
+	key = strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+	return key, len(key) <= maxIdempotencyKeyLength
+}
+
+// findIdempotentRequest looks up what an earlier request with key created
+// A nil repository or empty key disables idempotency and returns nil
+func findIdempotentRequest(ctx context.Context, repo *database.IdempotencyRepository, userID uuid.UUID, scope, key string) (*database.IdempotencyKey, error) {
+	if repo == nil || key == "" {
+		return nil, nil
+	}
+	return repo.GetIdempotencyKey(ctx, userID, scope, key)
+}
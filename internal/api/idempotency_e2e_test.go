@@ -0,0 +1,100 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_IdempotentCreation(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	userID := uuid.New()
+	ts.DB.SeedProfile(t, userID, "retrier")
+	ts.SetMockUserID(userID.String())
+
+	t.Run("repeated room creation with the same key creates one room", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":            "Flaky Network Night",
+			"is_public":       false,
+			"initial_members": []string{},
+		}
+
+		firstID := ts.POST("/api/rooms").
+			WithHeader("Idempotency-Key", "room-key-1").
+			WithJSON(body).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").String().Raw()
+
+		ts.POST("/api/rooms").
+			WithHeader("Idempotency-Key", "room-key-1").
+			WithJSON(body).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ValueEqual("id", firstID).
+			ValueEqual("name", "Flaky Network Night")
+
+		ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 1)
+	})
+
+	t.Run("a different key creates a new room", func(t *testing.T) {
+		ts.POST("/api/rooms").
+			WithHeader("Idempotency-Key", "room-key-2").
+			WithJSON(map[string]interface{}{"name": "Second Night"}).
+			Expect().
+			Status(201)
+
+		ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 2)
+	})
+
+	t.Run("repeated session creation with the same key returns the same session", func(t *testing.T) {
+		firstID := ts.POST("/api/sessions").
+			WithHeader("Idempotency-Key", "session-key-1").
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").String().Raw()
+
+		ts.POST("/api/sessions").
+			WithHeader("Idempotency-Key", "session-key-1").
+			Expect().
+			Status(201).
+			JSON().Object().
+			ValueEqual("id", firstID)
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		otherID := uuid.New()
+		ts.DB.SeedProfile(t, otherID, "other_retrier")
+		ts.SetMockUserID(otherID.String())
+		defer ts.SetMockUserID(userID.String())
+
+		ts.POST("/api/rooms").
+			WithHeader("Idempotency-Key", "room-key-1").
+			WithJSON(map[string]interface{}{"name": "Someone Else's Night"}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ValueEqual("name", "Someone Else's Night")
+	})
+
+	t.Run("rejects an oversized key", func(t *testing.T) {
+		ts.POST("/api/rooms").
+			WithHeader("Idempotency-Key", strings.Repeat("k", maxIdempotencyKeyLength+1)).
+			WithJSON(map[string]interface{}{"name": "Too Long"}).
+			Expect().
+			Status(400)
+	})
+}
@@ -1,38 +1,60 @@
 package api
 
 import (
-	"context"
-	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"github.com/tahaburak/would-watch-backend/internal/database"
 	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
 )
 
 // MatchHandler handles match-related API endpoints
 type MatchHandler struct {
-	voteRepo *database.VoteRepository
+	voteRepo    *database.VoteRepository
+	sessionRepo *database.SessionRepository
 }
 
 // NewMatchHandler creates a new match handler
-func NewMatchHandler(voteRepo *database.VoteRepository) *MatchHandler {
+func NewMatchHandler(voteRepo *database.VoteRepository, sessionRepo *database.SessionRepository) *MatchHandler {
 	return &MatchHandler{
-		voteRepo: voteRepo,
+		voteRepo:    voteRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
 // MatchesResponse represents the response for the matches endpoint
 type MatchesResponse struct {
-	Matches []database.MediaItem `json:"matches"`
-	Count   int                  `json:"count"`
+	Matches []MatchResponse `json:"matches"`
+	Count   int             `json:"count"`
 }
 
-// GetMatches handles GET /api/sessions/{id}/matches
+// Match modes accepted by GetMatches
+const (
+	// matchModeStrict matches media with at least two "yes" votes
+	matchModeStrict = "strict"
+	// matchModeSoft also counts "maybe" votes, but drops anything with a "no"
+	matchModeSoft = "soft"
+)
+
+// GetMatches handles GET /api/sessions/{id}/matches?mode=strict|soft&sort=
 func (h *MatchHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -41,41 +63,724 @@ func (h *MatchHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 4 || parts[3] != "matches" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	sessionIDStr := parts[2]
 	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		http.Error(w, "Invalid session ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
-	ctx := context.Background()
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = matchModeStrict
+	}
+	if mode != matchModeStrict && mode != matchModeSoft {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid mode")
+		return
+	}
+
+	sortKey, ok := parseSortKey(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid sort key")
+		return
+	}
+
+	ctx := r.Context()
+
+	var canAccess bool
+	err = retryRead(ctx, func() error {
+		var err error
+		canAccess, err = h.sessionRepo.CanAccessSession(ctx, sessionID, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to check session access")
+		return
+	}
+
+	if !canAccess {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to this session")
+		return
+	}
 
 	// Get matches for the session
+	var matches []database.MatchResult
+	err = retryRead(ctx, func() error {
+		var err error
+		if mode == matchModeSoft {
+			matches, err = h.voteRepo.GetSoftMatchesForSession(ctx, sessionID)
+		} else {
+			matches, err = h.voteRepo.GetMatchesForSession(ctx, sessionID)
+		}
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get matches")
+		return
+	}
+
+	// Remember the view so these matches stop counting as unseen; a failure shouldn't hide the matches
+	if err := h.voteRepo.MarkMatchesViewed(ctx, sessionID, userID); err != nil {
+		log.Printf("Error marking matches viewed for session %s: %v", sessionID, err)
+	}
+
+	// Decode each match's metadata; no matches gives an empty array
+	items := make([]MatchResponse, 0, len(matches))
+	for _, match := range matches {
+		items = append(items, MatchResponse{
+			MediaResponse: newMediaResponse(r, match.MediaItem),
+			YesCount:      match.YesCount,
+		})
+	}
+
+	sortMatches(items, sortKey)
+
+	response := MatchesResponse{
+		Matches: items,
+		Count:   len(items),
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetUnseenMatchCount handles GET /api/sessions/{id}/matches/unseen
+// Counts matches made since the current user last fetched the session's matches
+func (h *MatchHandler) GetUnseenMatchCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/matches/unseen
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[3] != "matches" || parts[4] != "unseen" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	ctx := r.Context()
+
+	var canAccess bool
+	err = retryRead(ctx, func() error {
+		var err error
+		canAccess, err = h.sessionRepo.CanAccessSession(ctx, sessionID, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to check session access")
+		return
+	}
+
+	if !canAccess {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to this session")
+		return
+	}
+
+	var count int
+	err = retryRead(ctx, func() error {
+		var err error
+		count, err = h.voteRepo.CountNewMatchesSince(ctx, sessionID, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to count unseen matches")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"session_id":   sessionID,
+		"unseen_count": count,
+	})
+}
+
+// maxMatchCheckIDs caps how many media IDs one CheckMatches request may carry
+const maxMatchCheckIDs = 100
+
+// CheckMatchesRequest lists the media to check for matches
+type CheckMatchesRequest struct {
+	MediaIDs []string `json:"media_ids"`
+}
+
+// CheckMatchesResponse reports, per requested media ID, whether it is a match
+type CheckMatchesResponse struct {
+	SessionID uuid.UUID          `json:"session_id"`
+	Matches   map[uuid.UUID]bool `json:"matches"`
+}
+
+// CheckMatches handles POST /api/sessions/{id}/matches/check
+// Lets clients resync match state for many media, e.g. after reconnecting, without one request per item
+func (h *MatchHandler) CheckMatches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/matches/check
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[3] != "matches" || parts[4] != "check" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	var req CheckMatchesRequest
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(req.MediaIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "At least one media ID is required")
+		return
+	}
+
+	if len(req.MediaIDs) > maxMatchCheckIDs {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Too many media IDs")
+		return
+	}
+
+	mediaIDs := make([]uuid.UUID, 0, len(req.MediaIDs))
+	for _, idStr := range req.MediaIDs {
+		mediaID, err := uuid.Parse(idStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid media ID format")
+			return
+		}
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+
+	ctx := r.Context()
+
+	var canAccess bool
+	err = retryRead(ctx, func() error {
+		var err error
+		canAccess, err = h.sessionRepo.CanAccessSession(ctx, sessionID, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to check session access")
+		return
+	}
+
+	if !canAccess {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to this session")
+		return
+	}
+
+	var matches map[uuid.UUID]bool
+	err = retryRead(ctx, func() error {
+		var err error
+		matches, err = h.voteRepo.CheckMatches(ctx, sessionID, mediaIDs)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to check matches")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, CheckMatchesResponse{
+		SessionID: sessionID,
+		Matches:   matches,
+	})
+}
+
+// MatchVotersResponse lists who voted "yes" on a match
+type MatchVotersResponse struct {
+	SessionID uuid.UUID          `json:"session_id"`
+	MediaID   uuid.UUID          `json:"media_id"`
+	Voters    []database.Profile `json:"voters"`
+	Count     int                `json:"count"`
+}
+
+// GetMatchVoters handles GET /api/sessions/{id}/matches/{media_id}/voters
+// Only users who can access the session may list voters; returns 404 when the media is not a match in the session
+func (h *MatchHandler) GetMatchVoters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session and media IDs from URL path
+	// Expected format: /api/sessions/{id}/matches/{media_id}/voters
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 6 || parts[3] != "matches" || parts[5] != "voters" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	mediaID, err := uuid.Parse(parts[4])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid media ID format")
+		return
+	}
+
+	ctx := r.Context()
+
+	var canAccess bool
+	err = retryRead(ctx, func() error {
+		var err error
+		canAccess, err = h.sessionRepo.CanAccessSession(ctx, sessionID, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to check session access")
+		return
+	}
+
+	if !canAccess {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to this session")
+		return
+	}
+
+	var voters []database.Profile
+	err = retryRead(ctx, func() error {
+		var err error
+		voters, err = h.voteRepo.GetMatchVoters(ctx, sessionID, mediaID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get match voters")
+		return
+	}
+
+	if voters == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Match not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, MatchVotersResponse{
+		SessionID: sessionID,
+		MediaID:   mediaID,
+		Voters:    voters,
+		Count:     len(voters),
+	})
+}
+
+// GetUserMatchCount handles GET /api/me/match-count
+func (h *MatchHandler) GetUserMatchCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	count, err := h.voteRepo.CountUserMatches(ctx, userID)
+	if err != nil {
+		log.Printf("Error counting user matches: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to count matches")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"match_count": count,
+	})
+}
+
+// GetSocialMatches handles GET /api/me/social-matches?limit=
+func (h *MatchHandler) GetSocialMatches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > 50 {
+			limit = 50
+		}
+	}
+
+	ctx := r.Context()
+
+	matches, err := h.voteRepo.GetFollowingMatches(ctx, userID, limit)
+	if err != nil {
+		log.Printf("Error getting social matches: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get social matches")
+		return
+	}
+
+	if matches == nil {
+		matches = []database.SocialMatch{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
+
+// GetLikedMovies handles GET /api/sessions/{id}/liked?limit=&offset=
+func (h *MatchHandler) GetLikedMovies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/liked
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "liked" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	limit := database.DefaultLikedMoviesLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > database.MaxLikedMoviesLimit {
+			limit = database.MaxLikedMoviesLimit
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	items, err := h.voteRepo.GetLikedMediaItems(ctx, sessionID, limit, offset)
+	if err != nil {
+		log.Printf("Error getting liked movies: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get liked movies")
+		return
+	}
+
+	if items == nil {
+		items = []database.MediaItem{}
+	}
+
+	// Titles are kept alongside the full items for older clients
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"items":  items,
+		"titles": titles,
+		"count":  len(titles),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetDislikedMedia handles GET /api/me/disliked?limit=&offset=
+func (h *MatchHandler) GetDislikedMedia(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	limit := database.DefaultDislikedMediaLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > database.MaxDislikedMediaLimit {
+			limit = database.MaxDislikedMediaLimit
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	disliked, err := h.voteRepo.GetUserDislikedMedia(ctx, userID, limit, offset)
+	if err != nil {
+		log.Printf("Error getting disliked media: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get disliked media")
+		return
+	}
+
+	if disliked == nil {
+		disliked = []database.MediaItem{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"media":  disliked,
+		"count":  len(disliked),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetUserLikes handles GET /api/me/likes
+// Returns every title the user liked in any session, most recent first
+func (h *MatchHandler) GetUserLikes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	liked, err := h.voteRepo.GetAllLikedByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting liked media: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get liked media")
+		return
+	}
+
+	if liked == nil {
+		liked = []database.MediaItem{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"media": liked,
+		"count": len(liked),
+	})
+}
+
+// GetSessionSummary handles GET /api/sessions/{id}/summary
+func (h *MatchHandler) GetSessionSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/summary
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "summary" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	ctx := r.Context()
+
 	matches, err := h.voteRepo.GetMatchesForSession(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error getting matches: %v", err)
-		http.Error(w, "Failed to get matches", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session summary")
 		return
 	}
 
-	// If no matches found, return empty array
-	if matches == nil {
-		matches = []database.MediaItem{}
+	score, err := h.voteRepo.GetConsensusScore(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting consensus score: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session summary")
+		return
 	}
 
-	response := MatchesResponse{
-		Matches: matches,
-		Count:   len(matches),
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"session_id":      sessionID,
+		"match_count":     len(matches),
+		"consensus_score": score,
+	})
+}
+
+// GetMatchIntersection handles GET /api/sessions/{id}/intersect/{otherId}
+// The caller must be a member of both sessions
+func (h *MatchHandler) GetMatchIntersection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session IDs from URL path
+	// Expected format: /api/sessions/{id}/intersect/{otherId}
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 5 || parts[3] != "intersect" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	otherSessionID, err := uuid.Parse(parts[4])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	ctx := r.Context()
+
+	for _, id := range []uuid.UUID{sessionID, otherSessionID} {
+		member, err := h.sessionRepo.IsSessionMember(ctx, id, userID)
+		if err != nil {
+			log.Printf("Error checking session membership: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check session membership")
+			return
+		}
+		if !member {
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You must be a participant of both sessions")
+			return
+		}
+	}
+
+	matches, err := h.voteRepo.GetSessionMatchIntersection(ctx, sessionID, otherSessionID)
+	if err != nil {
+		log.Printf("Error getting match intersection: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get match intersection")
 		return
 	}
+
+	if matches == nil {
+		matches = []database.MediaItem{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"matches": matches,
+		"count":   len(matches),
+	})
 }
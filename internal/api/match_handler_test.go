@@ -0,0 +1,267 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+// withUser authenticates req as a fresh user; the faulty DB lets anyone access the session
+func withUser(req *http.Request) *http.Request {
+	return req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+}
+
+func TestMatchHandler_GetMatchesMode(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+	path := "/api/sessions/" + uuid.New().String() + "/matches"
+
+	for _, mode := range []string{"", matchModeStrict, matchModeSoft} {
+		t.Run("accepts mode '"+mode+"'", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.GetMatches(rec, withUser(httptest.NewRequest(http.MethodGet, path+"?mode="+mode, nil)))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var resp MatchesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Matches == nil || resp.Count != 0 {
+				t.Errorf("Expected an empty match list, got %+v", resp)
+			}
+		})
+	}
+
+	t.Run("401 without a user", func(t *testing.T) {
+		before := connector.queryCount()
+
+		rec := httptest.NewRecorder()
+		handler.GetMatches(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rec.Code)
+		}
+		if connector.queryCount() != before {
+			t.Error("Expected no query without a user")
+		}
+	})
+
+	t.Run("rejects an unknown mode", func(t *testing.T) {
+		before := connector.queryCount()
+
+		rec := httptest.NewRecorder()
+		handler.GetMatches(rec, withUser(httptest.NewRequest(http.MethodGet, path+"?mode=loose", nil)))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if connector.queryCount() != before {
+			t.Error("Expected no query for an invalid mode")
+		}
+	})
+}
+
+func TestMatchHandler_GetMatchVoters(t *testing.T) {
+	db, _ := newFaultyDB(t, 0, nil)
+	handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+	sessionPath := "/api/sessions/" + uuid.New().String() + "/matches/"
+
+	request := func(path string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		return req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	}
+
+	t.Run("404 when the media is not a match", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.GetMatchVoters(rec, request(sessionPath+uuid.New().String()+"/voters"))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("400 for an invalid media ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.GetMatchVoters(rec, request(sessionPath+"not-a-uuid/voters"))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("401 without a user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.GetMatchVoters(rec, httptest.NewRequest(http.MethodGet, sessionPath+uuid.New().String()+"/voters", nil))
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMatchHandler_CheckMatches(t *testing.T) {
+	path := "/api/sessions/" + uuid.New().String() + "/matches/check"
+
+	request := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		return req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	}
+
+	t.Run("checks access and answers every media ID with one query", func(t *testing.T) {
+		db, connector := newFaultyDB(t, 0, nil)
+		handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+
+		ids := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+		body, _ := json.Marshal(CheckMatchesRequest{MediaIDs: ids})
+
+		rec := httptest.NewRecorder()
+		handler.CheckMatches(rec, request(string(body)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := connector.queryCount(); got != 2 {
+			t.Errorf("Expected 2 queries, got %d", got)
+		}
+
+		var resp struct {
+			Matches map[string]bool `json:"matches"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(resp.Matches) != len(ids) {
+			t.Fatalf("Expected %d entries, got %v", len(ids), resp.Matches)
+		}
+		for _, id := range ids {
+			if matched, ok := resp.Matches[id]; !ok || matched {
+				t.Errorf("Expected media %s to be reported as not matched, got %v (present=%v)", id, matched, ok)
+			}
+		}
+	})
+
+	t.Run("rejects bad input before querying", func(t *testing.T) {
+		tooMany := make([]string, maxMatchCheckIDs+1)
+		for i := range tooMany {
+			tooMany[i] = uuid.New().String()
+		}
+		tooManyBody, _ := json.Marshal(CheckMatchesRequest{MediaIDs: tooMany})
+
+		bodies := map[string]string{
+			"empty list":    `{"media_ids": []}`,
+			"invalid ID":    `{"media_ids": ["not-a-uuid"]}`,
+			"unknown field": `{"media": []}`,
+			"too many IDs":  string(tooManyBody),
+		}
+
+		for name, body := range bodies {
+			t.Run(name, func(t *testing.T) {
+				db, connector := newFaultyDB(t, 0, nil)
+				handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+
+				rec := httptest.NewRecorder()
+				handler.CheckMatches(rec, request(body))
+
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Expected status 400, got %d", rec.Code)
+				}
+				if got := connector.queryCount(); got != 0 {
+					t.Errorf("Expected no queries, got %d", got)
+				}
+			})
+		}
+	})
+
+	t.Run("401 without a user", func(t *testing.T) {
+		db, connector := newFaultyDB(t, 0, nil)
+		handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+
+		rec := httptest.NewRecorder()
+		handler.CheckMatches(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"media_ids": []}`)))
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rec.Code)
+		}
+		if got := connector.queryCount(); got != 0 {
+			t.Errorf("Expected no queries, got %d", got)
+		}
+	})
+}
+
+func TestSortMatches(t *testing.T) {
+	// Listed in the default most-voted-first order; each sort key orders them differently
+	newMatches := func() []MatchResponse {
+		return []MatchResponse{
+			{MediaResponse: MediaResponse{TMDBID: 1, Title: "Brazil", Metadata: database.MovieMetadata{Popularity: 10, VoteAverage: 8.0, ReleaseDate: "1979-05-25"}}, YesCount: 3},
+			{MediaResponse: MediaResponse{TMDBID: 2, Title: "alien", Metadata: database.MovieMetadata{Popularity: 50, VoteAverage: 7.0, ReleaseDate: "1985-02-20"}}, YesCount: 2},
+			{MediaResponse: MediaResponse{TMDBID: 3, Title: "Cube", Metadata: database.MovieMetadata{Popularity: 30, VoteAverage: 9.0, ReleaseDate: "1997-09-09"}}, YesCount: 2},
+		}
+	}
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{sortRelevance, []int{1, 2, 3}},
+		{sortPopularity, []int{2, 3, 1}},
+		{sortVoteAverage, []int{3, 1, 2}},
+		{sortReleaseDate, []int{3, 2, 1}},
+		{sortTitle, []int{2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			matches := newMatches()
+			sortMatches(matches, tt.sort)
+
+			got := make([]int, 0, len(matches))
+			for _, match := range matches {
+				got = append(got, match.TMDBID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected order %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMatchHandler_GetMatchesSort(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db))
+	path := "/api/sessions/" + uuid.New().String() + "/matches"
+
+	for _, key := range []string{"", sortRelevance, sortPopularity, sortRating, sortReleaseDate, sortTitle} {
+		t.Run("accepts sort '"+key+"'", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.GetMatches(rec, withUser(httptest.NewRequest(http.MethodGet, path+"?sort="+key, nil)))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("rejects an unknown sort", func(t *testing.T) {
+		before := connector.queryCount()
+
+		rec := httptest.NewRecorder()
+		handler.GetMatches(rec, withUser(httptest.NewRequest(http.MethodGet, path+"?sort=random", nil)))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if connector.queryCount() != before {
+			t.Error("Expected no queries for an unknown sort")
+		}
+	})
+}
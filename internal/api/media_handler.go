@@ -1,28 +1,87 @@
 package api
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
 	"github.com/tahaburak/would-watch-backend/internal/tmdb"
-	"github.com/google/uuid"
 )
 
+// Sort keys accepted by SearchMovies and GetMatches; relevance keeps the default order,
+// TMDB's ranking for search and most "yes" votes for matches
+const (
+	sortRelevance   = "relevance"
+	sortPopularity  = "popularity"
+	sortReleaseDate = "release_date"
+	sortVoteAverage = "vote_average"
+	sortTitle       = "title"
+	// sortRating is accepted as an alias of sortVoteAverage but not advertised
+	sortRating = "rating"
+)
+
+// searchSortKeys lists the supported sort keys in the order clients should offer them
+var searchSortKeys = []string{sortRelevance, sortPopularity, sortReleaseDate, sortVoteAverage, sortTitle}
+
+// minSearchYear is the earliest release year accepted by the year filter
+const minSearchYear = 1900
+
+// maxSearchRating is the top of TMDB's vote_average scale, the upper bound for min_rating
+const maxSearchRating = 10.0
+
+// maxDiscoverPage is the last page TMDB's discover endpoint will serve
+const maxDiscoverPage = 500
+
 // MediaHandler handles media-related API endpoints
 type MediaHandler struct {
 	tmdbClient *tmdb.Client
 	mediaRepo  *database.MediaRepository
+	socialRepo *database.SocialRepository
 }
 
 // NewMediaHandler creates a new media handler
-func NewMediaHandler(tmdbClient *tmdb.Client, mediaRepo *database.MediaRepository) *MediaHandler {
+// socialRepo supplies the caller's adult content preference; when nil, adult results are always excluded
+func NewMediaHandler(tmdbClient *tmdb.Client, mediaRepo *database.MediaRepository, socialRepo *database.SocialRepository) *MediaHandler {
 	return &MediaHandler{
 		tmdbClient: tmdbClient,
 		mediaRepo:  mediaRepo,
+		socialRepo: socialRepo,
+	}
+}
+
+// includeAdult reports whether the caller has opted in to adult search results
+// Anything short of a loaded profile with the preference set, including a lookup failure, excludes them
+func (h *MediaHandler) includeAdult(r *http.Request) bool {
+	if h.socialRepo == nil {
+		return false
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		return false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return false
+	}
+
+	profile, err := h.socialRepo.GetProfile(r.Context(), userID)
+	if err != nil {
+		log.Printf("Warning: Failed to get profile for adult content preference: %v", err)
+		return false
 	}
+
+	return profile != nil && profile.IncludeAdult != nil && *profile.IncludeAdult
 }
 
 // MovieSearchResult represents a movie in the search results with local UUID
@@ -34,6 +93,8 @@ type MovieSearchResult struct {
 	Overview         string     `json:"overview"`
 	PosterPath       string     `json:"poster_path"`
 	BackdropPath     string     `json:"backdrop_path"`
+	PosterURL        string     `json:"poster_url"`
+	BackdropURL      string     `json:"backdrop_url"`
 	ReleaseDate      string     `json:"release_date"`
 	VoteAverage      float64    `json:"vote_average"`
 	VoteCount        int        `json:"vote_count"`
@@ -51,32 +112,144 @@ type SearchResponse struct {
 	TotalResults int                 `json:"total_results"`
 }
 
-// SearchMovies handles GET /api/media/search?q=query
+// MultiSearchResult represents a movie or TV show in the multi search results with local UUID
+// TV shows report their name as Title and first air date as ReleaseDate
+type MultiSearchResult struct {
+	ID               *uuid.UUID `json:"id,omitempty"`
+	TMDBID           int        `json:"tmdb_id"`
+	MediaType        string     `json:"media_type"`
+	Title            string     `json:"title"`
+	OriginalTitle    string     `json:"original_title"`
+	Overview         string     `json:"overview"`
+	PosterPath       string     `json:"poster_path"`
+	BackdropPath     string     `json:"backdrop_path"`
+	PosterURL        string     `json:"poster_url"`
+	BackdropURL      string     `json:"backdrop_url"`
+	ReleaseDate      string     `json:"release_date"`
+	VoteAverage      float64    `json:"vote_average"`
+	VoteCount        int        `json:"vote_count"`
+	Popularity       float64    `json:"popularity"`
+	OriginalLanguage string     `json:"original_language"`
+	GenreIDs         []int      `json:"genre_ids"`
+}
+
+// MultiSearchResponse represents the multi search API response
+type MultiSearchResponse struct {
+	Page         int                 `json:"page"`
+	Results      []MultiSearchResult `json:"results"`
+	TotalPages   int                 `json:"total_pages"`
+	TotalResults int                 `json:"total_results"`
+}
+
+// SearchOptionsResponse describes the sort and filter values SearchMovies accepts
+type SearchOptionsResponse struct {
+	SortKeys    []string     `json:"sort_keys"`
+	DefaultSort string       `json:"default_sort"`
+	Genres      []tmdb.Genre `json:"genres"`
+	MinYear     int          `json:"min_year"`
+	MaxYear     int          `json:"max_year"`
+}
+
+// MovieDetailsResponse represents a single movie with runtime, tagline, genres, and top cast
+type MovieDetailsResponse struct {
+	ID           *uuid.UUID        `json:"id,omitempty"`
+	TMDBID       int               `json:"tmdb_id"`
+	Title        string            `json:"title"`
+	Overview     string            `json:"overview"`
+	PosterPath   string            `json:"poster_path"`
+	BackdropPath string            `json:"backdrop_path"`
+	PosterURL    string            `json:"poster_url"`
+	BackdropURL  string            `json:"backdrop_url"`
+	ReleaseDate  string            `json:"release_date"`
+	VoteAverage  float64           `json:"vote_average"`
+	Runtime      int               `json:"runtime"`
+	Tagline      string            `json:"tagline"`
+	Genres       []tmdb.Genre      `json:"genres"`
+	Cast         []tmdb.CastMember `json:"cast"`
+}
+
+// defaultTMDBRetryAfter is the Retry-After sent for TMDB rate limiting when TMDB did not give one
+const defaultTMDBRetryAfter = "10"
+
+// maxSearchYear allows next year's announced releases
+func maxSearchYear() int {
+	return time.Now().Year() + 1
+}
+
+// SearchMovies handles GET /api/media/search?q=query&sort=&genre=&year=&min_rating=&image_size=
 func (h *MediaHandler) SearchMovies(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Query parameter 'q' is required")
 		return
 	}
 
-	// Call TMDB API to search for movies
-	tmdbResp, err := h.tmdbClient.SearchMovie(query)
+	sortKey, ok := parseSortKey(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid sort key")
+		return
+	}
+
+	genreID := 0
+	if genreStr := r.URL.Query().Get("genre"); genreStr != "" {
+		var err error
+		genreID, err = strconv.Atoi(genreStr)
+		if err != nil || genreID < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid genre")
+			return
+		}
+	}
+
+	year := 0
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		var err error
+		year, err = strconv.Atoi(yearStr)
+		if err != nil || len(yearStr) != 4 || year < minSearchYear || year > maxSearchYear() {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid year")
+			return
+		}
+	}
+
+	minRating := 0.0
+	if ratingStr := r.URL.Query().Get("min_rating"); ratingStr != "" {
+		var err error
+		minRating, err = strconv.ParseFloat(ratingStr, 64)
+		if err != nil || math.IsNaN(minRating) || minRating < 0 || minRating > maxSearchRating {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid min_rating")
+			return
+		}
+	}
+
+	imageSize, ok := parseImageSize(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image_size")
+		return
+	}
+
+	// Call TMDB API to search for movies; the year is narrowed upstream so pages aren't mostly filtered out
+	opts := tmdb.SearchOptions{Year: year, IncludeAdult: h.includeAdult(r)}
+	tmdbResp, err := h.tmdbClient.SearchMovieWithOptionsCtx(r.Context(), query, opts)
 	if err != nil {
 		log.Printf("Error searching TMDB: %v", err)
-		http.Error(w, "Failed to search movies", http.StatusInternalServerError)
+		writeTMDBError(w, err, "Failed to search movies")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
+	imageBaseURL := h.tmdbClient.ImageBaseURL(ctx)
 	results := make([]MovieSearchResult, 0, len(tmdbResp.Results))
 
 	// Cache each movie and get local UUID
 	for _, movie := range tmdbResp.Results {
+		if !matchesSearchFilters(movie, genreID, year, minRating) {
+			continue
+		}
+
 		// Cache the movie in our database
 		localID, err := h.mediaRepo.CacheMovie(ctx, movie)
 		if err != nil {
@@ -84,25 +257,195 @@ func (h *MediaHandler) SearchMovies(w http.ResponseWriter, r *http.Request) {
 			// Continue even if caching fails - we can still return TMDB data
 		}
 
-		result := MovieSearchResult{
-			ID:               localID,
-			TMDBID:           movie.ID,
-			Title:            movie.Title,
-			OriginalTitle:    movie.OriginalTitle,
-			Overview:         movie.Overview,
-			PosterPath:       movie.PosterPath,
-			BackdropPath:     movie.BackdropPath,
-			ReleaseDate:      movie.ReleaseDate,
-			VoteAverage:      movie.VoteAverage,
-			VoteCount:        movie.VoteCount,
-			Popularity:       movie.Popularity,
-			Adult:            movie.Adult,
-			OriginalLanguage: movie.OriginalLanguage,
-			GenreIDs:         movie.GenreIDs,
+		results = append(results, newMovieSearchResult(movie, localID, imageBaseURL, imageSize))
+	}
+
+	sortSearchResults(results, sortKey)
+
+	response := SearchResponse{
+		Page:         tmdbResp.Page,
+		Results:      results,
+		TotalPages:   tmdbResp.TotalPages,
+		TotalResults: tmdbResp.TotalResults,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// SearchMulti handles GET /api/media/search/multi?q=query&image_size=
+func (h *MediaHandler) SearchMulti(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Query parameter 'q' is required")
+		return
+	}
+
+	imageSize, ok := parseImageSize(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image_size")
+		return
+	}
+
+	tmdbResp, err := h.tmdbClient.SearchMulti(r.Context(), query, h.includeAdult(r))
+	if err != nil {
+		log.Printf("Error searching TMDB: %v", err)
+		writeTMDBError(w, err, "Failed to search media")
+		return
+	}
+
+	ctx := r.Context()
+	imageBaseURL := h.tmdbClient.ImageBaseURL(ctx)
+	results := make([]MultiSearchResult, 0, len(tmdbResp.Results))
+
+	// Cache each movie or show under its own media type and get local UUID
+	for _, item := range tmdbResp.Results {
+		result := MultiSearchResult{
+			TMDBID:           item.ID,
+			MediaType:        item.MediaType,
+			Overview:         item.Overview,
+			PosterPath:       item.PosterPath,
+			BackdropPath:     item.BackdropPath,
+			PosterURL:        tmdb.ImageURL(imageBaseURL, imageSize, item.PosterPath),
+			BackdropURL:      tmdb.ImageURL(imageBaseURL, imageSize, item.BackdropPath),
+			VoteAverage:      item.VoteAverage,
+			VoteCount:        item.VoteCount,
+			Popularity:       item.Popularity,
+			OriginalLanguage: item.OriginalLanguage,
+			GenreIDs:         item.GenreIDs,
+		}
+
+		var localID *uuid.UUID
+		switch item.MediaType {
+		case tmdb.MediaTypeTV:
+			result.Title = item.Name
+			result.OriginalTitle = item.OriginalName
+			result.ReleaseDate = item.FirstAirDate
+			localID, err = h.mediaRepo.CacheTVShow(ctx, item.TVShow())
+		default:
+			result.Title = item.Title
+			result.OriginalTitle = item.OriginalTitle
+			result.ReleaseDate = item.ReleaseDate
+			localID, err = h.mediaRepo.CacheMovie(ctx, item.Movie())
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to cache %s %d: %v", item.MediaType, item.ID, err)
+			// Continue even if caching fails - we can still return TMDB data
 		}
+		result.ID = localID
+
 		results = append(results, result)
 	}
 
+	response := MultiSearchResponse{
+		Page:         tmdbResp.Page,
+		Results:      results,
+		TotalPages:   tmdbResp.TotalPages,
+		TotalResults: tmdbResp.TotalResults,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetSearchOptions handles GET /api/media/search/options
+func (h *MediaHandler) GetSearchOptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Genres come from the TMDB cache; sorting and the year filter still work without them
+	genres, err := h.tmdbClient.GetGenres(r.Context())
+	if err != nil {
+		log.Printf("Warning: Failed to load genres for search options: %v", err)
+	}
+	if genres == nil {
+		genres = []tmdb.Genre{}
+	}
+
+	response := SearchOptionsResponse{
+		SortKeys:    searchSortKeys,
+		DefaultSort: sortRelevance,
+		Genres:      genres,
+		MinYear:     minSearchYear,
+		MaxYear:     maxSearchYear(),
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// DiscoverByGenre handles GET /api/media/discover?genre=&page=&image_size=
+// Results are TMDB's most popular movies in the genre, cached so they can be voted on
+func (h *MediaHandler) DiscoverByGenre(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	genreStr := r.URL.Query().Get("genre")
+	if genreStr == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Query parameter 'genre' is required")
+		return
+	}
+
+	genreID, err := strconv.Atoi(genreStr)
+	if err != nil || genreID < 1 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid genre")
+		return
+	}
+
+	page := 1
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err = strconv.Atoi(pageStr)
+		if err != nil || page < 1 || page > maxDiscoverPage {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid page")
+			return
+		}
+	}
+
+	imageSize, ok := parseImageSize(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image_size")
+		return
+	}
+
+	// Only genres TMDB knows about are accepted, so typos fail fast rather than returning an empty page
+	genres, err := h.tmdbClient.GetGenres(r.Context())
+	if err != nil {
+		log.Printf("Error loading TMDB genres: %v", err)
+		writeTMDBError(w, err, "Failed to load genres")
+		return
+	}
+	if !hasGenre(genres, genreID) {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Unknown genre")
+		return
+	}
+
+	tmdbResp, err := h.tmdbClient.DiscoverByGenre(r.Context(), genreID, page, h.includeAdult(r))
+	if err != nil {
+		log.Printf("Error discovering TMDB genre %d: %v", genreID, err)
+		writeTMDBError(w, err, "Failed to discover movies")
+		return
+	}
+
+	ctx := r.Context()
+	imageBaseURL := h.tmdbClient.ImageBaseURL(ctx)
+	results := make([]MovieSearchResult, 0, len(tmdbResp.Results))
+
+	for _, movie := range tmdbResp.Results {
+		localID, err := h.mediaRepo.CacheMovie(ctx, movie)
+		if err != nil {
+			log.Printf("Warning: Failed to cache movie %d: %v", movie.ID, err)
+			// Continue even if caching fails - we can still return TMDB data
+		}
+
+		results = append(results, newMovieSearchResult(movie, localID, imageBaseURL, imageSize))
+	}
+
 	response := SearchResponse{
 		Page:         tmdbResp.Page,
 		Results:      results,
@@ -110,10 +453,391 @@ func (h *MediaHandler) SearchMovies(w http.ResponseWriter, r *http.Request) {
 		TotalResults: tmdbResp.TotalResults,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetMovieDetails handles GET /api/media/{tmdb_id}?image_size=
+func (h *MediaHandler) GetMovieDetails(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract TMDB ID from URL path
+	// Expected format: /api/media/{tmdb_id}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	tmdbID, err := strconv.Atoi(parts[2])
+	if err != nil || tmdbID < 1 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid TMDB ID")
+		return
+	}
+
+	imageSize, ok := parseImageSize(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image_size")
 		return
 	}
+
+	ctx := r.Context()
+
+	details, err := h.tmdbClient.GetMovieDetails(ctx, tmdbID)
+	if errors.Is(err, tmdb.ErrMovieNotFound) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Movie not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching movie details for %d: %v", tmdbID, err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get movie details")
+		return
+	}
+
+	// Cache the base movie so the details can be voted on
+	localID, err := h.mediaRepo.CacheMovie(ctx, details.Movie)
+	if err != nil {
+		log.Printf("Warning: Failed to cache movie %d: %v", details.ID, err)
+		// Continue even if caching fails - we can still return TMDB data
+	}
+
+	imageBaseURL := h.tmdbClient.ImageBaseURL(ctx)
+
+	genres := details.Genres
+	if genres == nil {
+		genres = []tmdb.Genre{}
+	}
+	cast := details.Credits.Cast
+	if cast == nil {
+		cast = []tmdb.CastMember{}
+	}
+
+	response := MovieDetailsResponse{
+		ID:           localID,
+		TMDBID:       details.ID,
+		Title:        details.Title,
+		Overview:     details.Overview,
+		PosterPath:   details.PosterPath,
+		BackdropPath: details.BackdropPath,
+		PosterURL:    tmdb.ImageURL(imageBaseURL, imageSize, details.PosterPath),
+		BackdropURL:  tmdb.ImageURL(imageBaseURL, imageSize, details.BackdropPath),
+		ReleaseDate:  details.ReleaseDate,
+		VoteAverage:  details.VoteAverage,
+		Runtime:      details.Runtime,
+		Tagline:      details.Tagline,
+		Genres:       genres,
+		Cast:         cast,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetMediaByID handles GET /api/media/id/{uuid}
+// It re-fetches a cached media item by the local ID search results carry
+func (h *MediaHandler) GetMediaByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract media ID from URL path
+	// Expected format: /api/media/id/{uuid}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[2] != "id" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	mediaID, err := uuid.Parse(parts[3])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid media ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	var item *database.MediaItem
+	err = retryRead(ctx, func() error {
+		var err error
+		item, err = h.mediaRepo.GetMediaByID(ctx, mediaID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get media")
+		return
+	}
+
+	if item == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Media not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newMediaResponse(r, *item))
+}
+
+// GetWatchProviders handles GET /api/media/{tmdb_id}/providers?region=
+func (h *MediaHandler) GetWatchProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract TMDB ID from URL path
+	// Expected format: /api/media/{tmdb_id}/providers
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "providers" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	tmdbID, err := strconv.Atoi(parts[2])
+	if err != nil || tmdbID < 1 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid TMDB ID")
+		return
+	}
+
+	// Regions are ISO 3166-1 alpha-2 codes, e.g. US or GB
+	region := r.URL.Query().Get("region")
+	if region != "" && !isRegionCode(region) {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid region")
+		return
+	}
+
+	providers, err := h.tmdbClient.GetWatchProviders(r.Context(), tmdbID, region)
+	if errors.Is(err, tmdb.ErrMovieNotFound) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Movie not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching watch providers for %d: %v", tmdbID, err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get watch providers")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, providers)
+}
+
+// imageCacheControl lets browsers and CDNs keep proxied images for a year; TMDB never changes the file at a path
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// GetImage handles GET /api/media/image?path=/poster.jpg&size=w500
+// Streams the image from the TMDB image CDN so clients never see its URLs; size defaults to tmdb.DefaultImageSize
+func (h *MediaHandler) GetImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if !tmdb.IsImagePath(path) {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image path")
+		return
+	}
+
+	size := r.URL.Query().Get("size")
+	if size == "" {
+		size = tmdb.DefaultImageSize
+	}
+	if !tmdb.IsImageSize(size) {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid image size")
+		return
+	}
+
+	resp, err := h.tmdbClient.GetImage(r.Context(), size, path)
+	if errors.Is(err, tmdb.ErrImageNotFound) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Image not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching image %s at %s: %v", path, size, err)
+		writeTMDBError(w, err, "Failed to get image")
+		return
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if contentLength := resp.Header.Get("Content-Length"); contentLength != "" {
+		w.Header().Set("Content-Length", contentLength)
+	}
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failed copy can only be logged
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Printf("Error streaming image %s: %v", path, err)
+	}
+}
+
+// newMovieSearchResult converts a TMDB movie and its local ID into a search result
+func newMovieSearchResult(movie tmdb.Movie, localID *uuid.UUID, imageBaseURL, imageSize string) MovieSearchResult {
+	return MovieSearchResult{
+		ID:               localID,
+		TMDBID:           movie.ID,
+		Title:            movie.Title,
+		OriginalTitle:    movie.OriginalTitle,
+		Overview:         movie.Overview,
+		PosterPath:       movie.PosterPath,
+		BackdropPath:     movie.BackdropPath,
+		PosterURL:        tmdb.ImageURL(imageBaseURL, imageSize, movie.PosterPath),
+		BackdropURL:      tmdb.ImageURL(imageBaseURL, imageSize, movie.BackdropPath),
+		ReleaseDate:      movie.ReleaseDate,
+		VoteAverage:      movie.VoteAverage,
+		VoteCount:        movie.VoteCount,
+		Popularity:       movie.Popularity,
+		Adult:            movie.Adult,
+		OriginalLanguage: movie.OriginalLanguage,
+		GenreIDs:         movie.GenreIDs,
+	}
+}
+
+// hasGenre reports whether genreID is in the genre list
+func hasGenre(genres []tmdb.Genre, genreID int) bool {
+	for _, genre := range genres {
+		if genre.ID == genreID {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTMDBError maps a failed TMDB call to a response
+// A rejected API key is our misconfiguration (502) and rate limiting is temporary (503); anything else is a 500
+func writeTMDBError(w http.ResponseWriter, err error, message string) {
+	var tmdbErr *tmdb.TMDBError
+	if errors.As(err, &tmdbErr) {
+		switch tmdbErr.StatusCode {
+		case http.StatusUnauthorized:
+			writeJSONError(w, http.StatusBadGateway, errCodeUpstream, message)
+			return
+		case http.StatusTooManyRequests:
+			retryAfter := tmdbErr.RetryAfter
+			if retryAfter == "" {
+				retryAfter = defaultTMDBRetryAfter
+			}
+			w.Header().Set("Retry-After", retryAfter)
+			writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable, message)
+			return
+		}
+	}
+
+	writeJSONError(w, http.StatusInternalServerError, errCodeInternal, message)
+}
+
+// isRegionCode reports whether region looks like a two-letter ISO 3166-1 code
+func isRegionCode(region string) bool {
+	if len(region) != 2 {
+		return false
+	}
+	for _, c := range region {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImageSize reads the optional image_size query parameter, defaulting to tmdb.DefaultImageSize
+// TMDB sizes are "original" or a w/h prefix followed by a pixel count, e.g. "w500" or "h632"
+func parseImageSize(r *http.Request) (string, bool) {
+	size := r.URL.Query().Get("image_size")
+	if size == "" {
+		return tmdb.DefaultImageSize, true
+	}
+	if size == "original" {
+		return size, true
+	}
+	if len(size) < 2 || (size[0] != 'w' && size[0] != 'h') {
+		return "", false
+	}
+	for _, c := range size[1:] {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return size, true
+}
+
+// parseSortKey reads the optional ?sort= key, defaulting to relevance and resolving aliases
+// The second result is false if the key is not supported
+func parseSortKey(r *http.Request) (string, bool) {
+	key := r.URL.Query().Get("sort")
+	switch key {
+	case "":
+		return sortRelevance, true
+	case sortRating:
+		return sortVoteAverage, true
+	}
+	return key, isSearchSortKey(key)
+}
+
+// isSearchSortKey reports whether key is one of the supported sort keys
+func isSearchSortKey(key string) bool {
+	for _, supported := range searchSortKeys {
+		if key == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSearchFilters applies the optional genre, release year, and minimum rating filters; zero disables a filter
+func matchesSearchFilters(movie tmdb.Movie, genreID, year int, minRating float64) bool {
+	if genreID != 0 {
+		found := false
+		for _, id := range movie.GenreIDs {
+			if id == genreID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if year != 0 && !strings.HasPrefix(movie.ReleaseDate, strconv.Itoa(year)) {
+		return false
+	}
+
+	if movie.VoteAverage < minRating {
+		return false
+	}
+
+	return true
+}
+
+// sortSearchResults orders results in place; relevance keeps the TMDB order
+func sortSearchResults(results []MovieSearchResult, key string) {
+	switch key {
+	case sortPopularity:
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Popularity > results[j].Popularity })
+	case sortReleaseDate:
+		sort.SliceStable(results, func(i, j int) bool { return results[i].ReleaseDate > results[j].ReleaseDate })
+	case sortVoteAverage:
+		sort.SliceStable(results, func(i, j int) bool { return results[i].VoteAverage > results[j].VoteAverage })
+	case sortTitle:
+		sort.SliceStable(results, func(i, j int) bool {
+			return strings.ToLower(results[i].Title) < strings.ToLower(results[j].Title)
+		})
+	}
+}
+
+// sortMatches orders matches in place by their decoded metadata; relevance keeps the most-voted-first order
+func sortMatches(matches []MatchResponse, key string) {
+	switch key {
+	case sortPopularity:
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Metadata.Popularity > matches[j].Metadata.Popularity })
+	case sortReleaseDate:
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Metadata.ReleaseDate > matches[j].Metadata.ReleaseDate })
+	case sortVoteAverage:
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Metadata.VoteAverage > matches[j].Metadata.VoteAverage })
+	case sortTitle:
+		sort.SliceStable(matches, func(i, j int) bool {
+			return strings.ToLower(matches[i].Title) < strings.ToLower(matches[j].Title)
+		})
+	}
 }
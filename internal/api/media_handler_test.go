@@ -0,0 +1,632 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+func TestMediaHandler_GetSearchOptions(t *testing.T) {
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/genre/movie/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"genres":[{"id":28,"name":"Action"},{"id":35,"name":"Comedy"}]}`))
+	}))
+	defer tmdbServer.Close()
+
+	tmdbClient := tmdb.NewClient("test-key")
+	tmdbClient.BaseURL = tmdbServer.URL
+
+	handler := NewMediaHandler(tmdbClient, nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.GetSearchOptions(rec, httptest.NewRequest(http.MethodGet, "/api/media/search/options", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var options SearchOptionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&options); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	t.Run("returns genres from TMDB", func(t *testing.T) {
+		if len(options.Genres) != 2 {
+			t.Fatalf("Expected 2 genres, got %d", len(options.Genres))
+		}
+		if options.Genres[0].ID != 28 || options.Genres[0].Name != "Action" {
+			t.Errorf("Expected first genre Action (28), got %+v", options.Genres[0])
+		}
+	})
+
+	t.Run("returns the supported sort keys", func(t *testing.T) {
+		if len(options.SortKeys) != len(searchSortKeys) {
+			t.Fatalf("Expected %d sort keys, got %d", len(searchSortKeys), len(options.SortKeys))
+		}
+		for i, key := range searchSortKeys {
+			if options.SortKeys[i] != key {
+				t.Errorf("Expected sort key %d to be '%s', got '%s'", i, key, options.SortKeys[i])
+			}
+			if !isSearchSortKey(options.SortKeys[i]) {
+				t.Errorf("Advertised sort key '%s' is not accepted by search", options.SortKeys[i])
+			}
+		}
+		if options.DefaultSort != sortRelevance {
+			t.Errorf("Expected default sort '%s', got '%s'", sortRelevance, options.DefaultSort)
+		}
+	})
+
+	t.Run("returns a valid year range", func(t *testing.T) {
+		if options.MinYear != minSearchYear {
+			t.Errorf("Expected min year %d, got %d", minSearchYear, options.MinYear)
+		}
+		if options.MaxYear < options.MinYear {
+			t.Errorf("Expected max year >= min year, got %d..%d", options.MinYear, options.MaxYear)
+		}
+	})
+}
+
+func TestMediaHandler_SearchMoviesRejectsUnknownSort(t *testing.T) {
+	handler := NewMediaHandler(tmdb.NewClient("test-key"), nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien&sort=random", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown sort, got %d", rec.Code)
+	}
+}
+
+func TestMediaHandler_SearchMoviesSort(t *testing.T) {
+	// Each sort key orders these three differently
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"page": 1, "total_pages": 1, "total_results": 3, "results": [
+			{"id": 1, "title": "Brazil", "popularity": 10, "vote_average": 8.0, "release_date": "1979-05-25"},
+			{"id": 2, "title": "alien", "popularity": 50, "vote_average": 7.0, "release_date": "1985-02-20"},
+			{"id": 3, "title": "Cube", "popularity": 30, "vote_average": 9.0, "release_date": "1997-09-09"}
+		]}`))
+	}))
+	defer tmdbServer.Close()
+
+	db, _ := newFaultyDB(t, 0, nil)
+	handler := NewMediaHandler(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), database.NewMediaRepository(db), nil)
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{"", []int{1, 2, 3}},
+		{sortRelevance, []int{1, 2, 3}},
+		{sortPopularity, []int{2, 3, 1}},
+		{sortVoteAverage, []int{3, 1, 2}},
+		{sortRating, []int{3, 1, 2}},
+		{sortReleaseDate, []int{3, 2, 1}},
+		{sortTitle, []int{2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run("sort '"+tt.sort+"'", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=film&sort="+tt.sort, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var resp SearchResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			got := make([]int, 0, len(resp.Results))
+			for _, result := range resp.Results {
+				got = append(got, result.TMDBID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected order %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMediaHandler_GetMovieDetails(t *testing.T) {
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/27205" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+			"id": 27205,
+			"title": "Inception",
+			"runtime": 148,
+			"tagline": "Your mind is the scene of the crime.",
+			"genres": [{"id": 28, "name": "Action"}],
+			"credits": {"cast": [
+				{"id": 6193, "name": "Leonardo DiCaprio", "character": "Cobb", "order": 0},
+				{"id": 24045, "name": "Joseph Gordon-Levitt", "character": "Arthur", "order": 1}
+			]}
+		}`))
+	}))
+	defer tmdbServer.Close()
+
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewMediaHandler(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), database.NewMediaRepository(db), nil)
+
+	// Route through a mux so the wildcard is checked against the search routes
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/media/search", handler.SearchMovies)
+	mux.HandleFunc("/api/media/search/options", handler.GetSearchOptions)
+	mux.HandleFunc("/api/media/{tmdb_id}", handler.GetMovieDetails)
+
+	t.Run("returns details with runtime and cast", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/media/27205", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var details MovieDetailsResponse
+		if err := json.NewDecoder(rec.Body).Decode(&details); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if details.TMDBID != 27205 || details.Runtime != 148 {
+			t.Errorf("Expected Inception with runtime 148, got %d with runtime %d", details.TMDBID, details.Runtime)
+		}
+		if details.Tagline == "" {
+			t.Error("Expected a tagline")
+		}
+		if len(details.Genres) != 1 || details.Genres[0].Name != "Action" {
+			t.Errorf("Expected genre Action, got %+v", details.Genres)
+		}
+		if len(details.Cast) != 2 || details.Cast[0].Name != "Leonardo DiCaprio" {
+			t.Errorf("Expected cast led by Leonardo DiCaprio, got %+v", details.Cast)
+		}
+		if got := connector.queryCount(); got != 1 {
+			t.Errorf("Expected the base movie to be cached once, got %d queries", got)
+		}
+	})
+
+	t.Run("returns 404 for an unknown movie", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/media/999999", nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns 400 for a non-numeric ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/media/inception", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMediaHandler_SearchMoviesFilters(t *testing.T) {
+	var gotYear string
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotYear = r.URL.Query().Get("primary_release_year")
+		w.Write([]byte(`{"page":1,"results":[
+			{"id":348,"title":"Alien","release_date":"1979-05-25","vote_average":8.1,"poster_path":"/alien.jpg"},
+			{"id":679,"title":"Aliens","release_date":"1986-07-18","vote_average":7.9},
+			{"id":8077,"title":"Alien 3","release_date":"1992-05-22","vote_average":6.4}
+		],"total_pages":1,"total_results":3}`))
+	}))
+	defer tmdbServer.Close()
+
+	db, _ := newFaultyDB(t, 0, nil)
+	tmdbClient := tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL), tmdb.WithImageBaseURL("https://image.tmdb.org/t/p/"))
+	handler := NewMediaHandler(tmdbClient, database.NewMediaRepository(db), nil)
+
+	t.Run("forwards the year to TMDB", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien&year=1979", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotYear != "1979" {
+			t.Errorf("Expected primary_release_year 1979, got %q", gotYear)
+		}
+	})
+
+	t.Run("filters out movies below the minimum rating", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien&min_rating=7.5", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp SearchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if len(resp.Results) != 2 {
+			t.Fatalf("Expected 2 results rated 7.5 or higher, got %d", len(resp.Results))
+		}
+		for _, movie := range resp.Results {
+			if movie.VoteAverage < 7.5 {
+				t.Errorf("Expected only movies rated 7.5 or higher, got %s at %.1f", movie.Title, movie.VoteAverage)
+			}
+		}
+	})
+
+	t.Run("builds full image URLs at the requested size", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien&image_size=w342", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp SearchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if got := resp.Results[0].PosterURL; got != "https://image.tmdb.org/t/p/w342/alien.jpg" {
+			t.Errorf("Expected a full poster URL, got %q", got)
+		}
+		if got := resp.Results[0].BackdropURL; got != "" {
+			t.Errorf("Expected no backdrop URL for an empty path, got %q", got)
+		}
+		if got := resp.Results[1].PosterURL; got != "" {
+			t.Errorf("Expected no poster URL for an empty path, got %q", got)
+		}
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		for _, query := range []string{"year=79", "year=01979", "year=abcd", "min_rating=-1", "min_rating=10.5", "min_rating=high", "min_rating=NaN", "min_rating=Inf", "min_rating=-Inf", "image_size=huge", "image_size=w"} {
+			rec := httptest.NewRecorder()
+			handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien&"+query, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, rec.Code)
+			}
+		}
+	})
+}
+
+func TestMediaHandler_SearchMulti(t *testing.T) {
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page":1,"results":[
+			{"id":603,"media_type":"movie","title":"The Matrix","release_date":"1999-03-30"},
+			{"id":6384,"media_type":"person","name":"Keanu Reeves"},
+			{"id":1396,"media_type":"tv","name":"Breaking Bad","first_air_date":"2008-01-20","poster_path":"/bb.jpg"}
+		],"total_pages":1,"total_results":3}`))
+	}))
+	defer tmdbServer.Close()
+
+	db, _ := newFaultyDB(t, 0, nil)
+	tmdbClient := tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL), tmdb.WithImageBaseURL("https://image.tmdb.org/t/p/"))
+	handler := NewMediaHandler(tmdbClient, database.NewMediaRepository(db), nil)
+
+	rec := httptest.NewRecorder()
+	handler.SearchMulti(rec, httptest.NewRequest(http.MethodGet, "/api/media/search/multi?q=matrix", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp MultiSearchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("Expected a movie and a TV show, got %+v", resp.Results)
+	}
+	if resp.Results[0].MediaType != tmdb.MediaTypeMovie || resp.Results[0].Title != "The Matrix" {
+		t.Errorf("Expected The Matrix as a movie, got %+v", resp.Results[0])
+	}
+
+	show := resp.Results[1]
+	if show.MediaType != tmdb.MediaTypeTV || show.Title != "Breaking Bad" || show.ReleaseDate != "2008-01-20" {
+		t.Errorf("Expected Breaking Bad with its name and first air date, got %+v", show)
+	}
+	if show.PosterURL != "https://image.tmdb.org/t/p/w500/bb.jpg" {
+		t.Errorf("Expected a full poster URL, got %q", show.PosterURL)
+	}
+
+	t.Run("requires a query", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.SearchMulti(rec, httptest.NewRequest(http.MethodGet, "/api/media/search/multi", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMediaHandler_SearchMoviesTMDBErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		tmdbStatus       int
+		tmdbRetryAfter   string
+		expectedStatus   int
+		expectRetryAfter string
+	}{
+		{"rejected API key is a bad gateway", http.StatusUnauthorized, "", http.StatusBadGateway, ""},
+		{"rate limit forwards Retry-After", http.StatusTooManyRequests, "30", http.StatusServiceUnavailable, "30"},
+		{"rate limit without Retry-After uses the default", http.StatusTooManyRequests, "", http.StatusServiceUnavailable, defaultTMDBRetryAfter},
+		{"other failures are internal errors", http.StatusInternalServerError, "", http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.tmdbRetryAfter != "" {
+					w.Header().Set("Retry-After", tt.tmdbRetryAfter)
+				}
+				w.WriteHeader(tt.tmdbStatus)
+				w.Write([]byte(`{"status_message":"upstream failure"}`))
+			}))
+			defer tmdbServer.Close()
+
+			handler := NewMediaHandler(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), nil, nil)
+
+			rec := httptest.NewRecorder()
+			handler.SearchMovies(rec, httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.expectRetryAfter {
+				t.Errorf("Expected Retry-After %q, got %q", tt.expectRetryAfter, got)
+			}
+		})
+	}
+}
+
+func TestMediaHandler_GetMediaByID(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewMediaHandler(nil, database.NewMediaRepository(db), nil)
+
+	t.Run("returns 404 for a missing ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.GetMediaByID(rec, httptest.NewRequest(http.MethodGet, "/api/media/id/"+uuid.New().String(), nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("returns 400 for a malformed ID", func(t *testing.T) {
+		before := connector.queryCount()
+
+		rec := httptest.NewRecorder()
+		handler.GetMediaByID(rec, httptest.NewRequest(http.MethodGet, "/api/media/id/not-a-uuid", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if connector.queryCount() != before {
+			t.Error("Expected no query for a malformed ID")
+		}
+	})
+}
+
+func TestMediaHandler_DiscoverByGenre(t *testing.T) {
+	var discoverCalls int
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genre/movie/list":
+			w.Write([]byte(`{"genres":[{"id":28,"name":"Action"},{"id":35,"name":"Comedy"}]}`))
+		case "/discover/movie":
+			discoverCalls++
+			w.Write([]byte(`{"page":1,"results":[{"id":245891,"title":"John Wick","genre_ids":[28]}],"total_pages":1,"total_results":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer tmdbServer.Close()
+
+	db, _ := newFaultyDB(t, 0, nil)
+	handler := NewMediaHandler(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), database.NewMediaRepository(db), nil)
+
+	t.Run("rejects a genre TMDB does not know", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.DiscoverByGenre(rec, httptest.NewRequest(http.MethodGet, "/api/media/discover?genre=9999", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if discoverCalls != 0 {
+			t.Errorf("Expected no discover call for an unknown genre, got %d", discoverCalls)
+		}
+	})
+
+	t.Run("rejects a malformed genre or page", func(t *testing.T) {
+		for _, target := range []string{
+			"/api/media/discover",
+			"/api/media/discover?genre=action",
+			"/api/media/discover?genre=28&page=0",
+			"/api/media/discover?genre=28&page=501",
+		} {
+			rec := httptest.NewRecorder()
+			handler.DiscoverByGenre(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", target, rec.Code)
+			}
+		}
+	})
+
+	t.Run("returns movies in a known genre", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.DiscoverByGenre(rec, httptest.NewRequest(http.MethodGet, "/api/media/discover?genre=28&page=1", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp SearchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].TMDBID != 245891 {
+			t.Errorf("Expected John Wick, got %+v", resp.Results)
+		}
+	})
+}
+
+func TestMediaHandler_SearchMoviesAdultPreference(t *testing.T) {
+	var gotAdult string
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAdult = r.URL.Query().Get("include_adult")
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":1,"total_results":0}`))
+	}))
+	defer tmdbServer.Close()
+
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	// Searches are cached per preference, so disable the cache to see every upstream call
+	tmdbClient := tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL), tmdb.WithSearchCache(0, 0))
+	socialRepo := database.NewSocialRepository(testDB.DB)
+	handler := NewMediaHandler(tmdbClient, database.NewMediaRepository(testDB.DB), socialRepo)
+
+	search := func(userID uuid.UUID) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/media/search?q=alien", nil)
+		req = req.WithContext(middleware.SetUserID(req.Context(), userID.String()))
+		rec := httptest.NewRecorder()
+		handler.SearchMovies(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	t.Run("a default profile forces include_adult=false", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "adult_default")
+
+		search(userID)
+
+		if gotAdult != "false" {
+			t.Errorf("Expected include_adult=false, got %q", gotAdult)
+		}
+	})
+
+	t.Run("an opted-in profile forwards include_adult=true", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "adult_opted_in")
+
+		optIn := true
+		if err := socialRepo.CreateOrUpdateProfile(context.Background(), userID, "adult_opted_in", "everyone", &optIn); err != nil {
+			t.Fatalf("CreateOrUpdateProfile failed: %v", err)
+		}
+
+		search(userID)
+
+		if gotAdult != "true" {
+			t.Errorf("Expected include_adult=true, got %q", gotAdult)
+		}
+	})
+
+	t.Run("an unknown user forces include_adult=false", func(t *testing.T) {
+		search(uuid.New())
+
+		if gotAdult != "false" {
+			t.Errorf("Expected include_adult=false, got %q", gotAdult)
+		}
+	})
+}
+
+func TestMediaHandler_GetImage(t *testing.T) {
+	var requestedPath string
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		if r.URL.Path == "/w342/missing.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg-bytes"))
+	}))
+	defer cdn.Close()
+
+	handler := NewMediaHandler(tmdb.NewClient("test-key", tmdb.WithImageBaseURL(cdn.URL)), nil, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		requestedPath = ""
+		rec := httptest.NewRecorder()
+		handler.GetImage(rec, httptest.NewRequest(http.MethodGet, "/api/media/image?"+query, nil))
+		return rec
+	}
+
+	t.Run("proxies a valid image with long-lived caching", func(t *testing.T) {
+		rec := get("path=/kqjL17yufvn9OVLyXYpvtyrFfak.jpg&size=w342")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if requestedPath != "/w342/kqjL17yufvn9OVLyXYpvtyrFfak.jpg" {
+			t.Errorf("Expected the CDN to be asked for the sized path, got '%s'", requestedPath)
+		}
+		if rec.Body.String() != "jpeg-bytes" {
+			t.Errorf("Expected the image bytes, got '%s'", rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != "image/jpeg" {
+			t.Errorf("Expected Content-Type image/jpeg, got '%s'", got)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != imageCacheControl {
+			t.Errorf("Expected Cache-Control '%s', got '%s'", imageCacheControl, got)
+		}
+	})
+
+	t.Run("defaults the size", func(t *testing.T) {
+		if rec := get("path=/poster.jpg"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if requestedPath != "/"+tmdb.DefaultImageSize+"/poster.jpg" {
+			t.Errorf("Expected the default size, got '%s'", requestedPath)
+		}
+	})
+
+	t.Run("rejects paths outside the image CDN", func(t *testing.T) {
+		for _, path := range []string{"/../../etc/passwd", "https://evil.example/x.jpg", "/a/b.jpg", "/poster.jpg?x=1", "poster.jpg", ""} {
+			if rec := get("path=" + url.QueryEscape(path)); rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for path %q, got %d", path, rec.Code)
+			}
+		}
+		if requestedPath != "" {
+			t.Errorf("Expected no upstream request, got '%s'", requestedPath)
+		}
+	})
+
+	t.Run("rejects an unknown size", func(t *testing.T) {
+		if rec := get("path=/poster.jpg&size=w9999"); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("404 when the CDN has no such image", func(t *testing.T) {
+		if rec := get("path=/missing.jpg&size=w342"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
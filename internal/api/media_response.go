@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+)
+
+// MediaResponse is a cached media item with its metadata decoded into typed fields
+type MediaResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	TMDBID    int                    `json:"tmdb_id"`
+	MediaType string                 `json:"media_type"`
+	Title     string                 `json:"title"`
+	Metadata  database.MovieMetadata `json:"metadata"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// MatchResponse is a session match with typed metadata and how many participants voted "yes"
+type MatchResponse struct {
+	MediaResponse
+	YesCount int `json:"yes_count"`
+}
+
+// newMediaResponse decodes item's metadata for a client response
+// Undecodable metadata is logged and left empty rather than failing the whole response
+func newMediaResponse(r *http.Request, item database.MediaItem) MediaResponse {
+	metadata, err := item.DecodeMetadata()
+	if err != nil {
+		logger.FromContext(r.Context()).Warn("failed to decode media metadata", "media_id", item.ID, "error", err)
+	}
+	if metadata.GenreIDs == nil {
+		metadata.GenreIDs = []int{}
+	}
+
+	return MediaResponse{
+		ID:        item.ID,
+		TMDBID:    item.TMDBID,
+		MediaType: item.MediaType,
+		Title:     item.Title,
+		Metadata:  metadata,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
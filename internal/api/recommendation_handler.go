@@ -1,16 +1,24 @@
 package api
 
 import (
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
 	"github.com/tahaburak/would-watch-backend/internal/service"
-	"github.com/google/uuid"
 )
 
+// RecommendationResponse is a recommended movie with typed metadata and, for AI picks, why it was recommended
+type RecommendationResponse struct {
+	MediaResponse
+	Reason string `json:"reason,omitempty"`
+}
+
 type RecommendationHandler struct {
 	recService *service.RecommendationService
 }
@@ -20,9 +28,12 @@ func NewRecommendationHandler(s *service.RecommendationService) *RecommendationH
 }
 
 // GetRecommendations handles GET /api/sessions/{id}/recommendations
+// Pass ?group=true to weight the prompt by titles the whole group liked,
+// or ?source=tmdb to recommend TMDB's similar movies instead of asking OpenAI
+// Responds 422 when the session has too few liked movies for AI recommendations
 func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -31,36 +42,126 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 4 || parts[3] != "recommendations" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	sessionIDStr := parts[2]
 	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		http.Error(w, "Invalid session ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
 	// Verify user is authenticated (redundant if middleware is used, but good for context extraction)
-	_, ok := middleware.GetUserID(r.Context())
+	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Skip anything the requesting user has already said no to
+	opts := service.RecommendationOptions{UserID: userID}
+	if groupStr := r.URL.Query().Get("group"); groupStr != "" {
+		opts.Group, err = strconv.ParseBool(groupStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid group")
+			return
+		}
+	}
+
+	switch source := r.URL.Query().Get("source"); source {
+	case "", service.SourceAI, service.SourceTMDB:
+		opts.Source = source
+	default:
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid source")
 		return
 	}
 
 	// Generate recommendations
-	recommendations, err := h.recService.GenerateRecommendations(r.Context(), sessionID)
+	recommendations, err := h.recService.GenerateRecommendationsWithOptions(r.Context(), sessionID, opts)
+	h.writeRecommendations(w, r, sessionID, recommendations, err)
+}
+
+// RerollRecommendations handles POST /api/sessions/{id}/recommendations/reroll
+// Returns a fresh AI batch that leaves out everything already recommended in the session;
+// ?group=true works as for GetRecommendations
+func (h *RecommendationHandler) RerollRecommendations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/recommendations/reroll
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 5 || parts[3] != "recommendations" || parts[4] != "reroll" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
 	if err != nil {
-		log.Printf("Error generating recommendations: %v", err)
-		http.Error(w, "Failed to generate recommendations", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(recommendations); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Skip anything the requesting user has already said no to
+	opts := service.RecommendationOptions{UserID: userID}
+	if groupStr := r.URL.Query().Get("group"); groupStr != "" {
+		opts.Group, err = strconv.ParseBool(groupStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid group")
+			return
+		}
+	}
+
+	recommendations, err := h.recService.RerollRecommendations(r.Context(), sessionID, opts)
+	h.writeRecommendations(w, r, sessionID, recommendations, err)
+}
+
+// writeRecommendations responds with the generated recommendations, or the error that prevented them
+func (h *RecommendationHandler) writeRecommendations(w http.ResponseWriter, r *http.Request, sessionID uuid.UUID, recommendations []service.Recommendation, err error) {
+	if errors.Is(err, service.ErrNotEnoughLikes) {
+		// Let the UI prompt for more swipes instead of showing an empty list
+		writeJSON(w, r, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":     fmt.Sprintf("need at least %d liked movies", h.recService.MinLikedMovies()),
+			"min_liked": h.recService.MinLikedMovies(),
+		})
+		return
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to generate recommendations", "session_id", sessionID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to generate recommendations")
+		return
+	}
+
+	response := make([]RecommendationResponse, 0, len(recommendations))
+	for _, rec := range recommendations {
+		response = append(response, RecommendationResponse{
+			MediaResponse: newMediaResponse(r, rec.MediaItem),
+			Reason:        rec.Reason,
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
 }
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxRequestBodyBytes caps JSON request bodies so a client cannot stream an unbounded body into a handler
+const maxRequestBodyBytes = 1 << 20
+
+// decodeJSONBody decodes the request body into v, reading at most maxRequestBodyBytes
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// decodeStrictJSONBody is decodeJSONBody but rejects fields v does not declare, so client typos fail loudly
+func decodeStrictJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// unknownFieldPrefix starts the error encoding/json returns for an undeclared field; there is no typed error for it
+const unknownFieldPrefix = "json: unknown field "
+
+// writeBodyError answers a failed body decode: 413 when the body was too large, 400 otherwise
+// A 400 for an unknown field names the field
+func writeBodyError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, errCodeBodyTooLarge, "Request body too large")
+		return
+	}
+	if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, "Unknown field "+field)
+		return
+	}
+	writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, "Invalid request body")
+}
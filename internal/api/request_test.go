@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+func TestCreateRoom_RejectsOversizedBody(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewRoomHandler(database.NewRoomRepository(db), database.NewSocialRepository(db), nil, nil)
+
+	// A valid JSON prefix followed by padding, so only the size limit can reject it
+	body := `{"name":"` + strings.Repeat("a", maxRequestBodyBytes) + `"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBufferString(body))
+	req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	rec := httptest.NewRecorder()
+	handler.CreateRoom(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, got %d", rec.Code)
+	}
+	if connector.queryCount() != 0 {
+		t.Error("Expected no queries for an oversized body")
+	}
+}
+
+func TestCreateRoom_MalformedBodyIsBadRequest(t *testing.T) {
+	db, _ := newFaultyDB(t, 0, nil)
+	handler := NewRoomHandler(database.NewRoomRepository(db), database.NewSocialRepository(db), nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBufferString(`{"name":`))
+	req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	rec := httptest.NewRecorder()
+	handler.CreateRoom(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateRoom_CapsInitialMembers(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewRoomHandler(database.NewRoomRepository(db), database.NewSocialRepository(db), nil, nil)
+	handler.MaxInitialMembers = 3
+
+	createRoom := func(members []string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(CreateRoomRequest{Name: "Crowded", InitialMembers: members})
+		if err != nil {
+			t.Fatalf("Failed to encode request: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewReader(body))
+		req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+		rec := httptest.NewRecorder()
+		handler.CreateRoom(rec, req)
+		return rec
+	}
+
+	t.Run("rejects too many members before touching the database", func(t *testing.T) {
+		members := []string{uuid.New().String(), uuid.New().String(), uuid.New().String(), uuid.New().String()}
+
+		rec := createRoom(members)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", rec.Code)
+		}
+		if connector.queryCount() != 0 {
+			t.Error("Expected no queries for a rejected member list")
+		}
+	})
+
+	t.Run("counts repeated members once", func(t *testing.T) {
+		repeated := uuid.New().String()
+		members := []string{repeated, repeated, repeated, repeated, uuid.New().String()}
+
+		rec := createRoom(members)
+
+		// The fake database cannot open a transaction, so getting past validation means a 500
+		if rec.Code == http.StatusBadRequest {
+			t.Fatalf("Expected duplicates to fit under the cap, got 400: %s", rec.Body.String())
+		}
+	})
+}
+
+func TestCreateRoom_ReportsEveryValidationError(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewRoomHandler(database.NewRoomRepository(db), database.NewSocialRepository(db), nil, nil)
+
+	body := `{"name":"   ","initial_members":["not-a-uuid"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBufferString(body))
+	req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	rec := httptest.NewRecorder()
+	handler.CreateRoom(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if connector.queryCount() != 0 {
+		t.Error("Expected no queries for an invalid room")
+	}
+
+	var resp ValidationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	fields := make(map[string]bool, len(resp.Errors))
+	for _, fieldErr := range resp.Errors {
+		fields[fieldErr.Field] = true
+	}
+	if len(resp.Errors) != 2 || !fields["name"] || !fields["initial_members"] {
+		t.Errorf("Expected name and initial_members errors, got %v", resp.Errors)
+	}
+}
+
+func TestCastVote_RejectsUnknownFields(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewVoteHandler(database.NewVoteRepository(db), database.NewSessionRepository(db), database.NewMediaRepository(db), nil)
+	path := "/api/sessions/" + uuid.New().String() + "/vote"
+
+	castVote := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+		rec := httptest.NewRecorder()
+		handler.CastVote(rec, req)
+		return rec
+	}
+
+	t.Run("rejects a typo'd field by name", func(t *testing.T) {
+		rec := castVote(`{"media_id":"` + uuid.New().String() + `","vot":"yes"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", rec.Code)
+		}
+		var resp ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode error response: %v", err)
+		}
+		if resp.Error.Code != errCodeInvalidBody {
+			t.Errorf("Expected code %q, got %q", errCodeInvalidBody, resp.Error.Code)
+		}
+		if !strings.Contains(resp.Error.Message, `"vot"`) {
+			t.Errorf("Expected the error to name the field, got %q", resp.Error.Message)
+		}
+		if connector.queryCount() != 0 {
+			t.Error("Expected no queries for a rejected body")
+		}
+	})
+
+	t.Run("accepts a correct body", func(t *testing.T) {
+		rec := castVote(`{"media_id":"` + uuid.New().String() + `","vote":"yes"}`)
+
+		if rec.Code == http.StatusBadRequest {
+			t.Fatalf("Expected a correct body to pass validation, got 400: %s", rec.Body.String())
+		}
+		if connector.queryCount() == 0 {
+			t.Error("Expected a correct body to reach the database")
+		}
+	})
+}
+
+func TestCastVote_SeasonNumber(t *testing.T) {
+	mediaID := uuid.New().String()
+	path := "/api/sessions/" + uuid.New().String() + "/vote"
+
+	tests := []struct {
+		name     string
+		body     string
+		rejected bool
+	}{
+		{"no season", `{"media_id":"` + mediaID + `","vote":"yes"}`, false},
+		{"season 2", `{"media_id":"` + mediaID + `","vote":"yes","season_number":2}`, false},
+		{"specials season", `{"media_id":"` + mediaID + `","vote":"yes","season_number":0}`, false},
+		{"negative season", `{"media_id":"` + mediaID + `","vote":"yes","season_number":-1}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, connector := newFaultyDB(t, 0, nil)
+			handler := NewVoteHandler(database.NewVoteRepository(db), database.NewSessionRepository(db), database.NewMediaRepository(db), nil)
+
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(tt.body))
+			req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+			rec := httptest.NewRecorder()
+			handler.CastVote(rec, req)
+
+			if tt.rejected {
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Expected status 400, got %d", rec.Code)
+				}
+				if connector.queryCount() != 0 {
+					t.Error("Expected no queries for a rejected season")
+				}
+				return
+			}
+			if rec.Code == http.StatusBadRequest {
+				t.Errorf("Expected the season to pass validation, got 400: %s", rec.Body.String())
+			}
+		})
+	}
+}
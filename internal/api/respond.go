@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http"
+
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+)
+
+// encodeErrors counts responses that failed JSON encoding, published at /debug/vars
+var encodeErrors = expvar.NewInt("api_json_encode_errors")
+
+// Error codes sent in the error envelope; clients branch on these rather than on messages
+const (
+	errCodeBadRequest       = "bad_request"
+	errCodeInvalidBody      = "invalid_body"
+	errCodeInvalidVote      = "invalid_vote"
+	errCodeValidation       = "validation_failed"
+	errCodeUnauthorized     = "unauthorized"
+	errCodeForbidden        = "forbidden"
+	errCodeNotFound         = "not_found"
+	errCodeMethodNotAllowed = "method_not_allowed"
+	errCodeConflict         = "conflict"
+	errCodeSessionExpired   = "session_expired"
+	errCodeBodyTooLarge     = "body_too_large"
+	errCodeInternal         = "internal_error"
+	errCodeUpstream         = "upstream_error"
+	errCodeUnavailable      = "unavailable"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes what went wrong: a stable machine-readable code and a human-readable message
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeJSONError responds with status and the error envelope {"error":{"code":...,"message":...}}
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error: ErrorDetail{Code: code, Message: message},
+	})
+}
+
+// writeJSON encodes v into a buffer before writing anything, so an encoding failure can still
+// become a clean 500 instead of a truncated body under a 2xx status
+// Failures increment encodeErrors and are logged with the request's logger, which carries the request ID
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		encodeErrors.Add(1)
+		logger.FromContext(r.Context()).Error("failed to encode response", "status", status, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logger.FromContext(r.Context()).Warn("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+func TestWriteJSON(t *testing.T) {
+	t.Run("writes status, content type, and body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		writeJSON(rec, req, http.StatusCreated, map[string]interface{}{"id": "abc"})
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", got)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != `{"id":"abc"}` {
+			t.Errorf("Unexpected body %s", got)
+		}
+	})
+
+	t.Run("returns 500 and counts unencodable values", func(t *testing.T) {
+		before := encodeErrors.Value()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		// Channels cannot be encoded as JSON
+		writeJSON(rec, req, http.StatusOK, map[string]interface{}{"broken": make(chan int)})
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "broken") {
+			t.Errorf("Expected no partial body, got %s", rec.Body.String())
+		}
+		if got := encodeErrors.Value() - before; got != 1 {
+			t.Errorf("Expected encode error counter to increment by 1, got %d", got)
+		}
+	})
+}
+
+func TestWriteJSONError(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	writeJSONError(rec, http.StatusNotFound, errCodeNotFound, "Room not found")
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %s", got)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"error":{"code":"not_found","message":"Room not found"}}` {
+		t.Errorf("Unexpected body %s", got)
+	}
+}
+
+func TestCastVote_InvalidVoteIsJSONError(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewVoteHandler(database.NewVoteRepository(db), database.NewSessionRepository(db), database.NewMediaRepository(db), nil)
+
+	body := `{"media_id":"` + uuid.New().String() + `","vote":"perhaps"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/"+uuid.New().String()+"/vote", bytes.NewBufferString(body))
+	req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	rec := httptest.NewRecorder()
+	handler.CastVote(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %s", got)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if resp.Error.Code != errCodeInvalidVote {
+		t.Errorf("Expected code %q, got %q", errCodeInvalidVote, resp.Error.Code)
+	}
+	if resp.Error.Message == "" {
+		t.Error("Expected a human-readable message")
+	}
+	if connector.queryCount() != 0 {
+		t.Error("Expected no queries for an invalid vote")
+	}
+}
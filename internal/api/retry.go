@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+)
+
+// retryAfter is the Retry-After hint sent when the database is unreachable
+const retryAfter = 5 * time.Second
+
+// readRetryAttempts and readRetryBackoff tune retries for idempotent read endpoints
+var (
+	readRetryAttempts = database.DefaultRetryAttempts
+	readRetryBackoff  = database.DefaultRetryBackoff
+)
+
+// retryRead runs an idempotent database read, retrying transient failures
+func retryRead(ctx context.Context, fn func() error) error {
+	return database.WithRetry(ctx, readRetryAttempts, readRetryBackoff, fn)
+}
+
+// writeReadError responds to a failed database read
+// Transient failures that survived retries become 503 with Retry-After so clients back off;
+// anything else is a 500
+func writeReadError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if database.IsTransient(err) {
+		logger.FromContext(r.Context()).Warn("database unavailable", "operation", message, "error", err)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable, "Service temporarily unavailable")
+		return
+	}
+
+	logger.FromContext(r.Context()).Error("database read failed", "operation", message, "error", err)
+	writeJSONError(w, http.StatusInternalServerError, errCodeInternal, message)
+}
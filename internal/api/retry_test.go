@@ -0,0 +1,296 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+// faultyConnector is a database/sql connector whose queries fail a set number of times
+// before returning empty result sets (or a zero for COUNT queries), so handlers can be exercised without Postgres
+type faultyConnector struct {
+	mu       sync.Mutex
+	failures int // remaining failures; negative fails forever
+	err      error
+	queries  int
+}
+
+func (c *faultyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &faultyConn{connector: c}, nil
+}
+
+func (c *faultyConnector) Driver() driver.Driver {
+	return faultyDriver{}
+}
+
+// nextError records a query attempt and returns the injected error, if any remain
+func (c *faultyConnector) nextError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queries++
+	if c.failures == 0 {
+		return nil
+	}
+	if c.failures > 0 {
+		c.failures--
+	}
+	return c.err
+}
+
+func (c *faultyConnector) queryCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queries
+}
+
+type faultyDriver struct{}
+
+func (faultyDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("faultyDriver: use sql.OpenDB with a faultyConnector")
+}
+
+type faultyConn struct {
+	connector *faultyConnector
+}
+
+func (c *faultyConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.connector.nextError(); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(query), "SELECT COUNT(*)") {
+		return &countRows{}, nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(query), "SELECT EXISTS") {
+		return &existsRows{}, nil
+	}
+	return emptyRows{}, nil
+}
+
+func (c *faultyConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.connector.nextError(); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *faultyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("faultyConn: prepare not supported")
+}
+
+func (c *faultyConn) Close() error { return nil }
+
+func (c *faultyConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("faultyConn: transactions not supported")
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return []string{} }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// countRows is a single-row result holding a zero count
+type countRows struct {
+	done bool
+}
+
+func (r *countRows) Columns() []string { return []string{"count"} }
+func (r *countRows) Close() error      { return nil }
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(0)
+	return nil
+}
+
+// existsRows is a single-row result holding a true EXISTS check
+type existsRows struct {
+	done bool
+}
+
+func (r *existsRows) Columns() []string { return []string{"exists"} }
+func (r *existsRows) Close() error      { return nil }
+func (r *existsRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = true
+	return nil
+}
+
+func newFaultyDB(t *testing.T, failures int, err error) (*sql.DB, *faultyConnector) {
+	t.Helper()
+
+	connector := &faultyConnector{failures: failures, err: err}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db, connector
+}
+
+func TestReadEndpoints_TransientDatabaseErrors(t *testing.T) {
+	// Keep retries fast
+	originalBackoff := readRetryBackoff
+	readRetryBackoff = time.Millisecond
+	defer func() { readRetryBackoff = originalBackoff }()
+
+	connRefused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	userID := uuid.New().String()
+
+	endpoints := []struct {
+		name    string
+		path    string
+		queries int // queries issued by one successful read; zero means 1
+		handler func(db *sql.DB) http.HandlerFunc
+	}{
+		{
+			name: "GetMatches",
+			path: "/api/sessions/" + uuid.New().String() + "/matches",
+			// The access check, the read, and recording that the user viewed the matches
+			queries: 3,
+			handler: func(db *sql.DB) http.HandlerFunc {
+				return NewMatchHandler(database.NewVoteRepository(db), database.NewSessionRepository(db)).GetMatches
+			},
+		},
+		{
+			name:    "GetRooms",
+			path:    "/api/rooms",
+			queries: 2,
+			handler: func(db *sql.DB) http.HandlerFunc {
+				return NewRoomHandler(database.NewRoomRepository(db), database.NewSocialRepository(db), nil, nil).GetRooms
+			},
+		},
+		{
+			name:    "GetFollowing",
+			path:    "/api/me/following",
+			queries: 2,
+			handler: func(db *sql.DB) http.HandlerFunc {
+				return NewSocialHandler(database.NewSocialRepository(db)).GetFollowing
+			},
+		},
+		{
+			name: "GetFriends",
+			path: "/api/me/friends",
+			handler: func(db *sql.DB) http.HandlerFunc {
+				return NewSocialHandler(database.NewSocialRepository(db)).GetFriends
+			},
+		},
+	}
+
+	serve := func(handler http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(middleware.SetUserID(req.Context(), userID))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.name+" recovers after a transient failure", func(t *testing.T) {
+			db, connector := newFaultyDB(t, 2, connRefused)
+
+			rec := serve(endpoint.handler(db), endpoint.path)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200 after retries, got %d: %s", rec.Code, rec.Body.String())
+			}
+			queries := endpoint.queries
+			if queries == 0 {
+				queries = 1
+			}
+			if want := 2 + queries; connector.queryCount() != want {
+				t.Errorf("Expected %d query attempts, got %d", want, connector.queryCount())
+			}
+		})
+
+		t.Run(endpoint.name+" returns 503 when the database stays unreachable", func(t *testing.T) {
+			db, connector := newFaultyDB(t, -1, connRefused)
+
+			rec := serve(endpoint.handler(db), endpoint.path)
+
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("Expected status 503, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if rec.Header().Get("Retry-After") == "" {
+				t.Error("Expected Retry-After header on 503")
+			}
+			if got := connector.queryCount(); got != readRetryAttempts {
+				t.Errorf("Expected %d query attempts, got %d", readRetryAttempts, got)
+			}
+		})
+
+		t.Run(endpoint.name+" does not retry non-transient errors", func(t *testing.T) {
+			db, connector := newFaultyDB(t, -1, errors.New("syntax error"))
+
+			rec := serve(endpoint.handler(db), endpoint.path)
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("Expected status 500, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := connector.queryCount(); got != 1 {
+				t.Errorf("Expected 1 query attempt, got %d", got)
+			}
+		})
+	}
+}
+
+func TestRewindHandler_GetStats_TransientDatabaseErrors(t *testing.T) {
+	// Keep retries fast
+	originalBackoff := readRetryBackoff
+	readRetryBackoff = time.Millisecond
+	defer func() { readRetryBackoff = originalBackoff }()
+
+	serve := func(db *sql.DB) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/me/stats", nil)
+		req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+		rec := httptest.NewRecorder()
+		NewRewindHandler(database.NewRewindRepository(db), nil).GetStats(rec, req)
+		return rec
+	}
+
+	t.Run("returns 503 when the database stays unreachable", func(t *testing.T) {
+		db, connector := newFaultyDB(t, -1, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+
+		rec := serve(db)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Error("Expected Retry-After header on 503")
+		}
+		if got := connector.queryCount(); got != readRetryAttempts {
+			t.Errorf("Expected %d query attempts, got %d", readRetryAttempts, got)
+		}
+	})
+
+	t.Run("does not retry non-transient errors", func(t *testing.T) {
+		db, connector := newFaultyDB(t, -1, errors.New("syntax error"))
+
+		rec := serve(db)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := connector.queryCount(); got != 1 {
+			t.Errorf("Expected 1 query attempt, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+// rewindWindow is the period covered by the weekly rewind
+const rewindWindow = 7 * 24 * time.Hour
+
+// RewindHandler handles activity summary endpoints
+type RewindHandler struct {
+	rewindRepo *database.RewindRepository
+	tmdbClient *tmdb.Client
+}
+
+// NewRewindHandler creates a new rewind handler
+func NewRewindHandler(rewindRepo *database.RewindRepository, tmdbClient *tmdb.Client) *RewindHandler {
+	return &RewindHandler{
+		rewindRepo: rewindRepo,
+		tmdbClient: tmdbClient,
+	}
+}
+
+// GetRewind handles GET /api/me/rewind
+func (h *RewindHandler) GetRewind(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-rewindWindow)
+
+	ctx := r.Context()
+
+	rewind, err := h.rewindRepo.GetRewind(ctx, userID, start, end)
+	if err != nil {
+		log.Printf("Error getting rewind: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get rewind")
+		return
+	}
+
+	// Genre names are best-effort; the ID is still returned if TMDB is unavailable
+	if rewind.TopGenreID != nil {
+		rewind.TopGenre = h.genreNames(ctx)[*rewind.TopGenreID]
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"window_start": start.Format(time.RFC3339),
+		"window_end":   end.Format(time.RFC3339),
+		"rewind":       rewind,
+	})
+}
+
+// GetGenreAgreement handles GET /api/me/genre-agreement
+func (h *RewindHandler) GetGenreAgreement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	agreement, err := h.rewindRepo.GetGenreAgreement(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting genre agreement: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get genre agreement")
+		return
+	}
+
+	if agreement == nil {
+		agreement = []database.GenreAgreement{}
+	}
+
+	// Genre names are best-effort; IDs are still returned if TMDB is unavailable
+	if len(agreement) > 0 {
+		names := h.genreNames(ctx)
+		for i := range agreement {
+			agreement[i].Genre = names[agreement[i].GenreID]
+		}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"genres": agreement,
+		"count":  len(agreement),
+	})
+}
+
+// GetStats handles GET /api/me/stats
+func (h *RewindHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	var stats *database.UserStats
+	err = retryRead(ctx, func() error {
+		var err error
+		stats, err = h.rewindRepo.GetUserStats(ctx, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get stats")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, stats)
+}
+
+// genreNames maps genre IDs to names from the TMDB genre cache, returning an empty map if it is unavailable
+func (h *RewindHandler) genreNames(ctx context.Context) map[int]*string {
+	names := map[int]*string{}
+	if h.tmdbClient == nil {
+		return names
+	}
+
+	genres, err := h.tmdbClient.GetGenres(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to resolve genre names: %v", err)
+		return names
+	}
+
+	for _, genre := range genres {
+		name := genre.Name
+		names[genre.ID] = &name
+	}
+
+	return names
+}
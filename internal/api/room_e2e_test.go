@@ -54,6 +54,29 @@ func TestE2E_CreateRoom(t *testing.T) {
 			ValueEqual("is_public", true)
 	})
 
+	t.Run("collapses duplicate initial members into one participant", func(t *testing.T) {
+		memberID := uuid.New()
+		ts.DB.SeedProfile(t, memberID, "repeat_member")
+
+		roomID := ts.POST("/api/rooms").
+			WithJSON(map[string]interface{}{
+				"name":            "Repeat Room",
+				"is_public":       false,
+				"initial_members": []string{memberID.String(), memberID.String(), memberID.String()},
+			}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			Value("id").String().Raw()
+
+		// The creator plus one member
+		ts.GET("/api/rooms/"+roomID).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("participant_count", 2)
+	})
+
 	t.Run("fails with invalid request body", func(t *testing.T) {
 		ts.POST("/api/rooms").
 			WithText("invalid json").
@@ -243,6 +266,427 @@ func TestE2E_RoomFlow(t *testing.T) {
 	})
 }
 
+func TestE2E_CloseRoom(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create test users
+	creatorID := uuid.New()
+	ts.DB.SeedProfile(t, creatorID, "creator")
+
+	memberID := uuid.New()
+	ts.DB.SeedProfile(t, memberID, "member")
+
+	ts.SetMockUserID(creatorID.String())
+	roomID := ts.POST("/api/rooms").
+		WithJSON(map[string]interface{}{
+			"name":            "Closing Room",
+			"is_public":       false,
+			"initial_members": []string{memberID.String()},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("id").String().Raw()
+
+	t.Run("non-creator cannot close the room", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+		ts.POST("/api/rooms/" + roomID + "/close").
+			Expect().
+			Status(403)
+	})
+
+	t.Run("creator can close the room", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+		ts.POST("/api/rooms/"+roomID+"/close").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("status", "closed")
+	})
+
+	t.Run("closed room is hidden from listing by default", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+		ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 0)
+
+		resp := ts.GET("/api/rooms").
+			WithQuery("include_closed", "true").
+			Expect().
+			Status(200).
+			JSON().Object()
+		resp.ValueEqual("count", 1)
+		resp.Value("rooms").Array().Element(0).Object().ValueEqual("id", roomID)
+	})
+
+	t.Run("404 for non-existent room", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+		ts.POST("/api/rooms/" + uuid.New().String() + "/close").
+			Expect().
+			Status(404)
+	})
+}
+
+func TestE2E_UpdateRoomVisibility(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	creatorID := uuid.New()
+	ts.DB.SeedProfile(t, creatorID, "creator")
+
+	memberID := uuid.New()
+	ts.DB.SeedProfile(t, memberID, "member")
+
+	ts.SetMockUserID(creatorID.String())
+	roomID := ts.POST("/api/rooms").
+		WithJSON(map[string]interface{}{
+			"name":            "Private Room",
+			"is_public":       false,
+			"initial_members": []string{memberID.String()},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("id").String().Raw()
+
+	t.Run("non-creator cannot change visibility", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+		ts.PATCH("/api/rooms/" + roomID).
+			WithJSON(map[string]interface{}{"is_public": true}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("creator can make the room public", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+		ts.PATCH("/api/rooms/"+roomID).
+			WithJSON(map[string]interface{}{"is_public": true}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("is_public", true)
+
+		ts.GET("/api/rooms/"+roomID).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("room").Object().
+			ValueEqual("is_public", true)
+	})
+
+	t.Run("missing is_public is rejected", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+		ts.PATCH("/api/rooms/" + roomID).
+			WithJSON(map[string]interface{}{}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("404 for non-existent room", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+		ts.PATCH("/api/rooms/" + uuid.New().String()).
+			WithJSON(map[string]interface{}{"is_public": true}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestE2E_GetRoom(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	creatorID := uuid.New()
+	memberID := uuid.New()
+	outsiderID := uuid.New()
+	ts.DB.SeedProfile(t, creatorID, "room_creator")
+	ts.DB.SeedProfile(t, memberID, "room_member")
+	ts.DB.SeedProfile(t, outsiderID, "room_outsider")
+
+	privateRoomID := ts.DB.SeedWatchSession(t, creatorID, "Private Room", false)
+	ts.DB.SeedRoomParticipant(t, privateRoomID, creatorID, "owner", "joined")
+	ts.DB.SeedRoomParticipant(t, privateRoomID, memberID, "member", "invited")
+
+	publicRoomID := ts.DB.SeedWatchSession(t, creatorID, "Public Room", true)
+	ts.DB.SeedRoomParticipant(t, publicRoomID, creatorID, "owner", "joined")
+
+	t.Run("participant can read a private room", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+
+		ts.GET("/api/rooms/"+privateRoomID.String()).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("id", privateRoomID.String()).
+			ValueEqual("name", "Private Room").
+			ValueEqual("is_public", false).
+			ValueEqual("participant_count", 2)
+	})
+
+	t.Run("anyone can read a public room", func(t *testing.T) {
+		ts.SetMockUserID(outsiderID.String())
+
+		ts.GET("/api/rooms/"+publicRoomID.String()).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("id", publicRoomID.String()).
+			ValueEqual("participant_count", 1)
+	})
+
+	t.Run("non-participant cannot read a private room", func(t *testing.T) {
+		ts.SetMockUserID(outsiderID.String())
+
+		ts.GET("/api/rooms/" + privateRoomID.String()).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("missing room is not found", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+
+		ts.GET("/api/rooms/" + uuid.New().String()).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("invalid room ID is rejected", func(t *testing.T) {
+		ts.SetMockUserID(creatorID.String())
+
+		ts.GET("/api/rooms/not-a-uuid").
+			Expect().
+			Status(400)
+	})
+}
+
+func TestE2E_JoinRoom(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	creatorID := uuid.New()
+	joinerID := uuid.New()
+	ts.DB.SeedProfile(t, creatorID, "join_creator")
+	ts.DB.SeedProfile(t, joinerID, "join_joiner")
+
+	publicRoomID := ts.DB.SeedWatchSession(t, creatorID, "Open Room", true)
+	ts.DB.SeedRoomParticipant(t, publicRoomID, creatorID, "owner", "joined")
+
+	privateRoomID := ts.DB.SeedWatchSession(t, creatorID, "Closed Door", false)
+	ts.DB.SeedRoomParticipant(t, privateRoomID, creatorID, "owner", "joined")
+
+	t.Run("joining a public room makes the user a participant", func(t *testing.T) {
+		ts.SetMockUserID(joinerID.String())
+
+		ts.POST("/api/rooms/"+publicRoomID.String()+"/join").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("id", publicRoomID.String())
+
+		ts.GET("/api/rooms/"+publicRoomID.String()).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("participant_count", 2)
+
+		resp := ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object()
+		resp.ValueEqual("count", 1)
+		resp.Value("rooms").Array().Element(0).Object().ValueEqual("id", publicRoomID.String())
+	})
+
+	t.Run("joining twice is harmless", func(t *testing.T) {
+		ts.SetMockUserID(joinerID.String())
+
+		ts.POST("/api/rooms/" + publicRoomID.String() + "/join").
+			Expect().
+			Status(200)
+
+		ts.GET("/api/rooms/"+publicRoomID.String()).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("participant_count", 2)
+	})
+
+	t.Run("joining accepts a pending invite", func(t *testing.T) {
+		inviteeID := uuid.New()
+		ts.DB.SeedProfile(t, inviteeID, "join_invitee")
+		ts.DB.SeedRoomParticipant(t, publicRoomID, inviteeID, "member", "invited")
+		ts.SetMockUserID(inviteeID.String())
+
+		ts.POST("/api/rooms/" + publicRoomID.String() + "/join").
+			Expect().
+			Status(200)
+
+		var status string
+		err := ts.DB.DB.QueryRow(
+			"SELECT status FROM room_participants WHERE room_id = $1 AND user_id = $2",
+			publicRoomID, inviteeID,
+		).Scan(&status)
+		if err != nil {
+			t.Fatalf("Failed to get participant status: %v", err)
+		}
+		if status != "joined" {
+			t.Errorf("Expected the invite to be accepted, got status %q", status)
+		}
+	})
+
+	t.Run("joining a closed room is rejected", func(t *testing.T) {
+		closedRoomID := ts.DB.SeedWatchSession(t, creatorID, "Finished Room", true)
+		if _, err := ts.DB.DB.Exec("UPDATE watch_sessions SET status = 'closed' WHERE id = $1", closedRoomID); err != nil {
+			t.Fatalf("Failed to close room: %v", err)
+		}
+		ts.SetMockUserID(joinerID.String())
+
+		ts.POST("/api/rooms/" + closedRoomID.String() + "/join").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("joining a private room is rejected", func(t *testing.T) {
+		ts.SetMockUserID(joinerID.String())
+
+		ts.POST("/api/rooms/" + privateRoomID.String() + "/join").
+			Expect().
+			Status(403)
+
+		ts.GET("/api/rooms/" + privateRoomID.String()).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("joining a missing room is not found", func(t *testing.T) {
+		ts.SetMockUserID(joinerID.String())
+
+		ts.POST("/api/rooms/" + uuid.New().String() + "/join").
+			Expect().
+			Status(404)
+	})
+}
+
+func TestE2E_InviteResponse(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	ownerID := uuid.New()
+	accepterID := uuid.New()
+	declinerID := uuid.New()
+	ts.DB.SeedProfile(t, ownerID, "invite_owner")
+	ts.DB.SeedProfile(t, accepterID, "invite_accepter")
+	ts.DB.SeedProfile(t, declinerID, "invite_decliner")
+
+	ts.SetMockUserID(ownerID.String())
+	roomID := ts.POST("/api/rooms").
+		WithJSON(map[string]interface{}{
+			"name":            "Invite Only",
+			"is_public":       false,
+			"initial_members": []string{},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("id").String().Raw()
+
+	invitePath := "/api/rooms/" + roomID + "/invite"
+	respondPath := "/api/rooms/" + roomID + "/invite/respond"
+
+	t.Run("invite stays pending until accepted", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		ts.POST(invitePath).
+			WithJSON(map[string]interface{}{"user_id": accepterID.String()}).
+			Expect().
+			Status(200)
+
+		ts.SetMockUserID(accepterID.String())
+		ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 0)
+
+		invites := ts.GET("/api/me/invites").
+			Expect().
+			Status(200).
+			JSON().Object()
+		invites.ValueEqual("count", 1)
+		invites.Value("invites").Array().Element(0).Object().ValueEqual("id", roomID)
+
+		ts.POST(respondPath).
+			WithJSON(map[string]interface{}{"accept": true}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("accepted", true)
+
+		rooms := ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object()
+		rooms.ValueEqual("count", 1)
+		rooms.Value("rooms").Array().Element(0).Object().ValueEqual("id", roomID)
+
+		ts.GET("/api/me/invites").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 0)
+	})
+
+	t.Run("declined invite never becomes visible", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		ts.POST(invitePath).
+			WithJSON(map[string]interface{}{"user_id": declinerID.String()}).
+			Expect().
+			Status(200)
+
+		ts.SetMockUserID(declinerID.String())
+		ts.POST(respondPath).
+			WithJSON(map[string]interface{}{"accept": false}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("accepted", false)
+
+		ts.GET("/api/rooms").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 0)
+
+		ts.GET("/api/me/invites").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 0)
+	})
+
+	t.Run("responding without a pending invite is not found", func(t *testing.T) {
+		ts.SetMockUserID(accepterID.String())
+		ts.POST(respondPath).
+			WithJSON(map[string]interface{}{"accept": true}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("accept is required", func(t *testing.T) {
+		ts.SetMockUserID(declinerID.String())
+		ts.POST(respondPath).
+			WithJSON(map[string]interface{}{}).
+			Expect().
+			Status(400)
+	})
+}
+
 func TestE2E_ErrorHandling(t *testing.T) {
 	ts := NewTestServer(t)
 	defer ts.Close()
@@ -254,8 +698,9 @@ func TestE2E_ErrorHandling(t *testing.T) {
 	ts.SetMockUserID(userID.String())
 
 	t.Run("404 for non-existent room", func(t *testing.T) {
-		// This would return 404 if the GetRoomByID endpoint existed
-		// For now, we test other error scenarios
+		ts.GET("/api/rooms/" + uuid.New().String()).
+			Expect().
+			Status(404)
 	})
 
 	t.Run("405 Method Not Allowed", func(t *testing.T) {
@@ -1,28 +1,46 @@
 package api
 
 import (
-	"context"
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
-	"github.com/google/uuid"
 )
 
 // RoomHandler handles room management endpoints
 type RoomHandler struct {
-	roomRepo   *database.RoomRepository
-	socialRepo *database.SocialRepository
+	roomRepo        *database.RoomRepository
+	socialRepo      *database.SocialRepository
+	idempotencyRepo *database.IdempotencyRepository
+	hub             *RoomHub
+	// MaxInitialMembers caps how many distinct initial members a new room may be created with
+	MaxInitialMembers int
 }
 
+// DefaultMaxInitialMembers is the initial member cap used unless configured otherwise
+const DefaultMaxInitialMembers = 50
+
+// maxRoomNameLength bounds room names, in characters
+const maxRoomNameLength = 100
+
 // NewRoomHandler creates a new room handler
-func NewRoomHandler(roomRepo *database.RoomRepository, socialRepo *database.SocialRepository) *RoomHandler {
+// A nil idempotencyRepo disables Idempotency-Key support on room creation
+func NewRoomHandler(roomRepo *database.RoomRepository, socialRepo *database.SocialRepository, idempotencyRepo *database.IdempotencyRepository, hub *RoomHub) *RoomHandler {
 	return &RoomHandler{
-		roomRepo:   roomRepo,
-		socialRepo: socialRepo,
+		roomRepo:          roomRepo,
+		socialRepo:        socialRepo,
+		idempotencyRepo:   idempotencyRepo,
+		hub:               hub,
+		MaxInitialMembers: DefaultMaxInitialMembers,
 	}
 }
 
@@ -36,78 +54,143 @@ type CreateRoomRequest struct {
 // InviteRequest represents the request to invite a user to a room
 type InviteRequest struct {
 	UserID string `json:"user_id"`
+	// Force resets a declined or pending invite so the user is asked again
+	Force bool `json:"force"`
 }
 
 // CreateRoom handles POST /api/rooms
 func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	creatorID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	key, ok := idempotencyKey(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid Idempotency-Key")
 		return
 	}
 
 	// Parse request body
 	var req CreateRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
-	// Parse initial members
+	// Validate every field, parsing and deduping initial members, before touching the database
+	var v validator
+	req.Name = strings.TrimSpace(req.Name)
+	v.check(req.Name != "", "name", "required")
+	v.check(utf8.RuneCountInString(req.Name) <= maxRoomNameLength, "name", fmt.Sprintf("must be at most %d characters", maxRoomNameLength))
+
 	var memberIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(req.InitialMembers))
 	for _, memberStr := range req.InitialMembers {
 		memberID, err := uuid.Parse(memberStr)
 		if err != nil {
-			http.Error(w, "Invalid member ID format", http.StatusBadRequest)
-			return
+			v.check(false, "initial_members", "must be user IDs")
+			break
 		}
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
 		memberIDs = append(memberIDs, memberID)
 	}
+	v.check(len(memberIDs) <= h.MaxInitialMembers, "initial_members", fmt.Sprintf("must have at most %d members", h.MaxInitialMembers))
 
-	ctx := context.Background()
+	if !v.valid() {
+		writeValidationErrors(w, &v)
+		return
+	}
+
+	if h.idempotencyRepo == nil {
+		key = ""
+	}
 
-	// Create room
-	room, err := h.roomRepo.CreateRoom(ctx, creatorID, req.Name, req.IsPublic, memberIDs)
+	// A retried request gets the room its first attempt created
+	if h.replayCreateRoom(w, r, creatorID, key) {
+		return
+	}
+
+	// The key is reserved with the room, so of two concurrent requests sharing it only one creates a room
+	room, err := h.roomRepo.CreateRoomWithIdempotencyKey(r.Context(), creatorID, req.Name, req.IsPublic, memberIDs, key)
+	if errors.Is(err, database.ErrIdempotencyKeyUsed) {
+		if !h.replayCreateRoom(w, r, creatorID, key) {
+			writeJSONError(w, http.StatusConflict, errCodeConflict, "Idempotency-Key is already in use")
+		}
+		return
+	}
 	if err != nil {
-		log.Printf("Error creating room: %v", err)
-		http.Error(w, "Failed to create room", http.StatusInternalServerError)
+		logger.FromContext(r.Context()).Error("failed to create room", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create room")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(room)
+	writeJSON(w, r, http.StatusCreated, room)
+}
+
+// replayCreateRoom responds with the room an earlier request with key created, reporting whether it wrote a response
+// Nothing is written when the key is unused or its room has since been deleted
+func (h *RoomHandler) replayCreateRoom(w http.ResponseWriter, r *http.Request, creatorID uuid.UUID, key string) bool {
+	ctx := r.Context()
+
+	prior, err := findIdempotentRequest(ctx, h.idempotencyRepo, creatorID, database.IdempotencyScopeRoom, key)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to check idempotency key", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create room")
+		return true
+	}
+	if prior == nil {
+		return false
+	}
+
+	room, err := h.roomRepo.GetRoomByID(ctx, prior.ResourceID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get room", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create room")
+		return true
+	}
+	if room == nil {
+		return false
+	}
+
+	writeJSON(w, r, prior.StatusCode, room)
+	return true
 }
 
 // InviteToRoom handles POST /api/rooms/{id}/invite
+// With "force": true a previously declined or pending invite is reset
 func (h *RoomHandler) InviteToRoom(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	inviterID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -116,65 +199,75 @@ func (h *RoomHandler) InviteToRoom(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 4 || parts[3] != "invite" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	roomID, err := uuid.Parse(parts[2])
 	if err != nil {
-		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
 		return
 	}
 
 	// Parse request body
 	var req InviteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
 	targetUserID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		http.Error(w, "Invalid target user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
-	// Check if room exists and inviter is creator
+	// Check if room exists and inviter may invite
 	room, err := h.roomRepo.GetRoomByID(ctx, roomID)
 	if err != nil {
-		log.Printf("Error getting room: %v", err)
-		http.Error(w, "Failed to get room", http.StatusInternalServerError)
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
 		return
 	}
 
 	if room == nil {
-		http.Error(w, "Room not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
 		return
 	}
 
+	// The creator may always invite; otherwise the inviter needs an owner or moderator role
 	if room.CreatorID != inviterID {
-		http.Error(w, "Only room creator can invite users", http.StatusForbidden)
-		return
+		role, err := h.roomRepo.GetParticipantRole(ctx, roomID, inviterID)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("failed to get participant role", "room_id", roomID, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check room permissions")
+			return
+		}
+
+		if !database.CanInvite(role) {
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only room owners and admins can invite users")
+			return
+		}
 	}
 
 	// Get target user's profile to check invite preferences
 	profile, err := h.socialRepo.GetProfile(ctx, targetUserID)
 	if err != nil {
-		log.Printf("Error getting profile: %v", err)
-		http.Error(w, "Failed to get user profile", http.StatusInternalServerError)
+		logger.FromContext(r.Context()).Error("failed to get profile", "target_user_id", targetUserID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get user profile")
 		return
 	}
 
 	if profile == nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "User not found")
 		return
 	}
 
 	// Check invite preference
 	if profile.InvitePreference == "none" {
-		http.Error(w, "User does not accept invitations", http.StatusForbidden)
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "User does not accept invitations")
 		return
 	}
 
@@ -182,58 +275,218 @@ func (h *RoomHandler) InviteToRoom(w http.ResponseWriter, r *http.Request) {
 		// Check if target user is following the inviter
 		isFollowing, err := h.socialRepo.IsFollowing(ctx, targetUserID, inviterID)
 		if err != nil {
-			log.Printf("Error checking following status: %v", err)
-			http.Error(w, "Failed to check following status", http.StatusInternalServerError)
+			logger.FromContext(r.Context()).Error("failed to check following status", "target_user_id", targetUserID, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check following status")
 			return
 		}
 
 		if !isFollowing {
-			http.Error(w, "User only accepts invites from people they follow", http.StatusForbidden)
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "User only accepts invites from people they follow")
 			return
 		}
 	}
 
-	// Add user to room
-	if err := h.roomRepo.AddParticipant(ctx, roomID, targetUserID); err != nil {
-		log.Printf("Error adding participant: %v", err)
-		http.Error(w, "Failed to add user to room", http.StatusInternalServerError)
-		return
+	if req.Force {
+		// Re-invite, replacing any declined or stale pending invite
+		reinvited, err := h.roomRepo.ReInvite(ctx, roomID, targetUserID)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("failed to re-invite participant", "room_id", roomID, "target_user_id", targetUserID, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to add user to room")
+			return
+		}
+
+		if !reinvited {
+			writeJSONError(w, http.StatusConflict, errCodeConflict, "User has already joined the room")
+			return
+		}
+	} else {
+		// Add user to room
+		if err := h.roomRepo.AddParticipant(ctx, roomID, targetUserID, database.RoleMember); err != nil {
+			// The room was just loaded, so a missing reference means the target user is gone
+			if database.IsForeignKeyViolation(err) {
+				writeJSONError(w, http.StatusNotFound, errCodeNotFound, "User not found")
+				return
+			}
+			logger.FromContext(r.Context()).Error("failed to add participant", "room_id", roomID, "target_user_id", targetUserID, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to add user to room")
+			return
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"message": "User invited successfully",
 	})
 }
 
-// GetRooms handles GET /api/rooms
+// InviteResponseRequest represents an invitee's answer to a room invite
+type InviteResponseRequest struct {
+	Accept *bool `json:"accept"`
+}
+
+// RespondToInvite handles POST /api/rooms/{id}/invite/respond
+// Accepting joins the room; declining removes the pending invite
+func (h *RoomHandler) RespondToInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}/invite/respond
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[3] != "invite" || parts[4] != "respond" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req InviteResponseRequest
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if req.Accept == nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "accept is required")
+		return
+	}
+
+	responded, err := h.roomRepo.RespondToInvite(r.Context(), roomID, userID, *req.Accept)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to respond to invite", "room_id", roomID, "user_id", userID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to respond to invite")
+		return
+	}
+
+	if !responded {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "No pending invite for this room")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"accepted": *req.Accept,
+	})
+}
+
+// GetInvites handles GET /api/me/invites
+// Lists the open rooms the current user has been invited to but not yet answered
+func (h *RoomHandler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	var invites []database.Room
+	err = retryRead(ctx, func() error {
+		var err error
+		invites, err = h.roomRepo.GetPendingInvites(ctx, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get invites")
+		return
+	}
+
+	if invites == nil {
+		invites = []database.Room{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"invites": invites,
+		"count":   len(invites),
+	})
+}
+
+// GetRooms handles GET /api/rooms?include_closed=&limit=&offset=
 func (h *RoomHandler) GetRooms(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
-	ctx := context.Background()
+	includeClosed := r.URL.Query().Get("include_closed") == "true"
+
+	limit := database.DefaultRoomsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > database.MaxRoomsLimit {
+			limit = database.MaxRoomsLimit
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	ctx := r.Context()
 
-	// Get rooms for user
-	rooms, err := h.roomRepo.GetRoomsByUser(ctx, userID)
+	// Get a page of the user's rooms
+	var rooms []database.Room
+	var total int
+	err = retryRead(ctx, func() error {
+		var err error
+		rooms, total, err = h.roomRepo.GetRoomsByUser(ctx, userID, includeClosed, limit, offset)
+		return err
+	})
 	if err != nil {
-		log.Printf("Error getting rooms: %v", err)
-		http.Error(w, "Failed to get rooms", http.StatusInternalServerError)
+		writeReadError(w, r, err, "Failed to get rooms")
 		return
 	}
 
@@ -241,9 +494,557 @@ func (h *RoomHandler) GetRooms(w http.ResponseWriter, r *http.Request) {
 		rooms = []database.Room{}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"rooms": rooms,
-		"count": len(rooms),
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"rooms":  rooms,
+		"count":  len(rooms),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// RoomDetailsResponse is a room together with how many participants it has
+type RoomDetailsResponse struct {
+	database.Room
+	ParticipantCount int `json:"participant_count"`
+}
+
+// GetRoom handles GET /api/rooms/{id}
+// Public rooms are readable by anyone; private rooms only by their participants
+func (h *RoomHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	var room *database.Room
+	err = retryRead(ctx, func() error {
+		var err error
+		room, err = h.roomRepo.GetRoomByID(ctx, roomID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	if !room.IsPublic {
+		var isParticipant bool
+		err = retryRead(ctx, func() error {
+			var err error
+			isParticipant, err = h.roomRepo.IsParticipant(ctx, roomID, userID)
+			return err
+		})
+		if err != nil {
+			writeReadError(w, r, err, "Failed to check room access")
+			return
+		}
+
+		if !isParticipant {
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You are not a participant in this room")
+			return
+		}
+	}
+
+	var participantCount int
+	err = retryRead(ctx, func() error {
+		var err error
+		participantCount, err = h.roomRepo.CountParticipants(ctx, roomID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to count participants")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, RoomDetailsResponse{
+		Room:             *room,
+		ParticipantCount: participantCount,
+	})
+}
+
+// CloseRoom handles POST /api/rooms/{id}/close
+func (h *RoomHandler) CloseRoom(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}/close
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "close" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	// Check if room exists and caller is creator
+	room, err := h.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	if room.CreatorID != userID {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only room creator can close the room")
+		return
+	}
+
+	room, err = h.roomRepo.CloseRoom(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to close room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to close room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, room)
+}
+
+// UpdateRoomRequest represents the request to change a room's settings
+type UpdateRoomRequest struct {
+	IsPublic *bool `json:"is_public"`
+}
+
+// UpdateRoom handles PATCH /api/rooms/{id}
+// Only the room creator may change its visibility
+func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req UpdateRoomRequest
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if req.IsPublic == nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "is_public is required")
+		return
+	}
+
+	ctx := r.Context()
+
+	room, err := h.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	if room.CreatorID != userID {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only room creator can update the room")
+		return
+	}
+
+	if err := h.roomRepo.SetRoomVisibility(ctx, roomID, *req.IsPublic); err != nil {
+		logger.FromContext(r.Context()).Error("failed to set room visibility", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update room")
+		return
+	}
+
+	room, err = h.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, room)
+}
+
+// JoinRoom handles POST /api/rooms/{id}/join
+// Any user may join an active public room themselves; private rooms are invite-only
+func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}/join
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "join" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	room, err := h.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	if !room.IsPublic {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Private rooms can only be joined by invitation")
+		return
+	}
+
+	if room.Status != "active" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Room is not active")
+		return
+	}
+
+	if err := h.roomRepo.JoinRoom(ctx, roomID, userID); err != nil {
+		logger.FromContext(r.Context()).Error("failed to join room", "room_id", roomID, "user_id", userID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to join room")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, room)
+}
+
+// SetRoleRequest represents the request to change a participant's role
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// SetParticipantRole handles POST /api/rooms/{id}/participants/{uid}/role
+// Only the room owner may change roles, and ownership itself cannot be granted or removed
+func (h *RoomHandler) SetParticipantRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room and participant IDs from URL
+	// Expected format: /api/rooms/{id}/participants/{uid}/role
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 6 || parts[3] != "participants" || parts[5] != "role" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	targetUserID, err := uuid.Parse(parts[4])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
+		return
+	}
+
+	var req SetRoleRequest
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if req.Role != database.RoleModerator && req.Role != database.RoleMember {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Role must be 'moderator' or 'member'")
+		return
+	}
+
+	ctx := r.Context()
+
+	room, err := h.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to get room", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get room")
+		return
+	}
+
+	if room == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Room not found")
+		return
+	}
+
+	// Rooms created before roles existed have no owner row, so the creator counts as owner
+	if room.CreatorID != userID {
+		role, err := h.roomRepo.GetParticipantRole(ctx, roomID, userID)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("failed to get participant role", "room_id", roomID, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check room permissions")
+			return
+		}
+
+		if role != database.RoleOwner {
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only the room owner can change roles")
+			return
+		}
+	}
+
+	if targetUserID == room.CreatorID {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Cannot change the room creator's role")
+		return
+	}
+
+	updated, err := h.roomRepo.SetParticipantRole(ctx, roomID, targetUserID, req.Role)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to set participant role", "room_id", roomID, "target_user_id", targetUserID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to set participant role")
+		return
+	}
+
+	if !updated {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "User is not a participant in this room")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"user_id": targetUserID,
+		"role":    req.Role,
 	})
 }
+
+// Keepalive timings for live room connections
+const (
+	roomWSWriteWait  = 10 * time.Second
+	roomWSPongWait   = 60 * time.Second
+	roomWSPingPeriod = roomWSPongWait * 9 / 10
+)
+
+// roomUpgrader upgrades live room requests to WebSockets
+// Any origin is accepted because callers authenticate with a bearer token, not cookies
+var roomUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// LiveUpdates handles GET /api/rooms/{id}/ws
+// Participants receive a JSON RoomEvent for every vote and match in the room until they disconnect
+func (h *RoomHandler) LiveUpdates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract room ID from URL
+	// Expected format: /api/rooms/{id}/ws
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "ws" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	roomID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid room ID")
+		return
+	}
+
+	isParticipant, err := h.roomRepo.IsParticipant(r.Context(), roomID, userID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("failed to check room participant", "room_id", roomID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check room access")
+		return
+	}
+
+	if !isParticipant {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Not a participant in this room")
+		return
+	}
+
+	// The upgrader writes its own error response on failure
+	conn, err := roomUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.FromContext(r.Context()).Warn("failed to upgrade room connection", "room_id", roomID, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := h.hub.Subscribe(roomID)
+	defer unsubscribe()
+
+	// Drain client frames so pongs and close messages are processed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(roomWSPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(roomWSPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(roomWSPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(roomWSWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(roomWSWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+)
+
+// Room event types sent to live subscribers
+const (
+	RoomEventVote  = "vote"
+	RoomEventMatch = "match"
+)
+
+// roomSubscriberBuffer is how many events a slow subscriber may lag behind before events are dropped
+const roomSubscriberBuffer = 32
+
+// RoomEvent is a live update broadcast to everyone watching a room
+type RoomEvent struct {
+	Type    string              `json:"type"`
+	RoomID  uuid.UUID           `json:"room_id"`
+	UserID  uuid.UUID           `json:"user_id"`
+	MediaID uuid.UUID           `json:"media_id"`
+	Vote    string              `json:"vote,omitempty"`
+	Media   *database.MediaItem `json:"media,omitempty"`
+}
+
+// RoomHub fans room events out to in-process subscribers
+// Publishing to a nil hub is a no-op, so handlers work without live updates
+type RoomHub struct {
+	mu    sync.Mutex
+	rooms map[uuid.UUID]map[chan RoomEvent]struct{}
+}
+
+// NewRoomHub creates an empty room hub
+func NewRoomHub() *RoomHub {
+	return &RoomHub{
+		rooms: make(map[uuid.UUID]map[chan RoomEvent]struct{}),
+	}
+}
+
+// Subscribe registers a listener for a room's events
+// The returned function unsubscribes and closes the channel; it is safe to call more than once
+func (h *RoomHub) Subscribe(roomID uuid.UUID) (<-chan RoomEvent, func()) {
+	events := make(chan RoomEvent, roomSubscriberBuffer)
+
+	h.mu.Lock()
+	subscribers, ok := h.rooms[roomID]
+	if !ok {
+		subscribers = make(map[chan RoomEvent]struct{})
+		h.rooms[roomID] = subscribers
+	}
+	subscribers[events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(subscribers, events)
+			if len(subscribers) == 0 {
+				delete(h.rooms, roomID)
+			}
+			close(events)
+		})
+	}
+
+	return events, unsubscribe
+}
+
+// Publish sends an event to every subscriber of its room without blocking
+// Subscribers whose buffer is full miss the event rather than stall the voter's request
+func (h *RoomHub) Publish(event RoomEvent) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.rooms[event.RoomID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// SubscriberCount returns how many listeners a room currently has
+func (h *RoomHub) SubscriberCount(roomID uuid.UUID) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.rooms[roomID])
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func receiveEvent(t *testing.T, events <-chan RoomEvent) RoomEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for room event")
+		return RoomEvent{}
+	}
+}
+
+func TestRoomHub(t *testing.T) {
+	t.Run("broadcasts to every subscriber in the room", func(t *testing.T) {
+		hub := NewRoomHub()
+		roomID := uuid.New()
+
+		first, unsubscribeFirst := hub.Subscribe(roomID)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := hub.Subscribe(roomID)
+		defer unsubscribeSecond()
+
+		mediaID := uuid.New()
+		hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: roomID, MediaID: mediaID, Vote: "yes"})
+
+		for _, events := range []<-chan RoomEvent{first, second} {
+			event := receiveEvent(t, events)
+			if event.Type != RoomEventVote || event.MediaID != mediaID || event.Vote != "yes" {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		}
+	})
+
+	t.Run("does not leak events across rooms", func(t *testing.T) {
+		hub := NewRoomHub()
+
+		events, unsubscribe := hub.Subscribe(uuid.New())
+		defer unsubscribe()
+
+		hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: uuid.New()})
+
+		select {
+		case event := <-events:
+			t.Errorf("Expected no event, got %+v", event)
+		default:
+		}
+	})
+
+	t.Run("unsubscribe removes the subscriber and closes its channel", func(t *testing.T) {
+		hub := NewRoomHub()
+		roomID := uuid.New()
+
+		events, unsubscribe := hub.Subscribe(roomID)
+		if got := hub.SubscriberCount(roomID); got != 1 {
+			t.Fatalf("Expected 1 subscriber, got %d", got)
+		}
+
+		unsubscribe()
+		unsubscribe()
+
+		if got := hub.SubscriberCount(roomID); got != 0 {
+			t.Errorf("Expected 0 subscribers, got %d", got)
+		}
+		if _, ok := <-events; ok {
+			t.Error("Expected channel to be closed")
+		}
+
+		// Publishing to an empty room must not panic on the closed channel
+		hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: roomID})
+	})
+
+	t.Run("drops events for a full subscriber instead of blocking", func(t *testing.T) {
+		hub := NewRoomHub()
+		roomID := uuid.New()
+
+		_, unsubscribe := hub.Subscribe(roomID)
+		defer unsubscribe()
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < roomSubscriberBuffer*2; i++ {
+				hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: roomID})
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Publish blocked on a full subscriber")
+		}
+	})
+
+	t.Run("nil hub ignores publishes", func(t *testing.T) {
+		var hub *RoomHub
+		hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: uuid.New()})
+	})
+}
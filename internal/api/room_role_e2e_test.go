@@ -0,0 +1,121 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_RoomRoles(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create an owner, a future moderator, a plain member, and two invitees
+	ownerID := uuid.New()
+	ts.DB.SeedProfile(t, ownerID, "role_owner")
+
+	moderatorID := uuid.New()
+	ts.DB.SeedProfile(t, moderatorID, "role_moderator")
+
+	memberID := uuid.New()
+	ts.DB.SeedProfile(t, memberID, "role_member")
+
+	invitee1ID := uuid.New()
+	ts.DB.SeedProfile(t, invitee1ID, "role_invitee1")
+
+	invitee2ID := uuid.New()
+	ts.DB.SeedProfile(t, invitee2ID, "role_invitee2")
+
+	ts.SetMockUserID(ownerID.String())
+	roomID := ts.POST("/api/rooms").
+		WithJSON(map[string]interface{}{
+			"name":            "Moderated Room",
+			"is_public":       false,
+			"initial_members": []string{moderatorID.String(), memberID.String()},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("id").String().Raw()
+
+	invitePath := "/api/rooms/" + roomID + "/invite"
+	rolePath := func(userID uuid.UUID) string {
+		return "/api/rooms/" + roomID + "/participants/" + userID.String() + "/role"
+	}
+
+	t.Run("member cannot change roles", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+		ts.POST(rolePath(moderatorID)).
+			WithJSON(map[string]interface{}{"role": "moderator"}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("owner cannot grant ownership", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		ts.POST(rolePath(moderatorID)).
+			WithJSON(map[string]interface{}{"role": "owner"}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("rejects the legacy admin and viewer roles", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		for _, role := range []string{"admin", "viewer"} {
+			ts.POST(rolePath(moderatorID)).
+				WithJSON(map[string]interface{}{"role": role}).
+				Expect().
+				Status(400)
+		}
+	})
+
+	t.Run("owner promotes a moderator", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		ts.POST(rolePath(moderatorID)).
+			WithJSON(map[string]interface{}{"role": "moderator"}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true).
+			ValueEqual("role", "moderator")
+	})
+
+	t.Run("moderator can invite", func(t *testing.T) {
+		ts.SetMockUserID(moderatorID.String())
+		ts.POST(invitePath).
+			WithJSON(map[string]interface{}{"user_id": invitee1ID.String()}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+
+		var isParticipant bool
+		err := ts.DB.DB.QueryRow(
+			"SELECT EXISTS(SELECT 1 FROM room_participants WHERE room_id = $1 AND user_id = $2)",
+			roomID, invitee1ID,
+		).Scan(&isParticipant)
+		if err != nil {
+			t.Fatalf("Failed to check participant: %v", err)
+		}
+		if !isParticipant {
+			t.Error("Expected the invitee to be added to the room")
+		}
+	})
+
+	t.Run("member cannot invite", func(t *testing.T) {
+		ts.SetMockUserID(memberID.String())
+		ts.POST(invitePath).
+			WithJSON(map[string]interface{}{"user_id": invitee2ID.String()}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("404 for a user outside the room", func(t *testing.T) {
+		ts.SetMockUserID(ownerID.String())
+		ts.POST(rolePath(invitee2ID)).
+			WithJSON(map[string]interface{}{"role": "moderator"}).
+			Expect().
+			Status(404)
+	})
+}
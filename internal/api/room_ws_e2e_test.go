@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func TestE2E_RoomLiveUpdates(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create a room with two participants and a movie
+	watcherID := uuid.New()
+	ts.DB.SeedProfile(t, watcherID, "live_watcher")
+
+	voterID := uuid.New()
+	ts.DB.SeedProfile(t, voterID, "live_voter")
+
+	outsiderID := uuid.New()
+	ts.DB.SeedProfile(t, outsiderID, "live_outsider")
+
+	roomID := ts.DB.SeedWatchSession(t, watcherID, "Live Room", false)
+	ts.DB.SeedRoomParticipant(t, roomID, watcherID, "member", "joined")
+	ts.DB.SeedRoomParticipant(t, roomID, voterID, "member", "joined")
+	mediaID := ts.DB.SeedMediaItem(t, 603, "movie", "The Matrix")
+
+	wsURL := "ws" + strings.TrimPrefix(ts.Server.URL, "http") + "/api/rooms/" + roomID.String() + "/ws"
+
+	dial := func(t *testing.T, userID uuid.UUID) (*websocket.Conn, *http.Response, error) {
+		t.Helper()
+		header := http.Header{}
+		header.Set("X-Test-User-ID", userID.String())
+		return websocket.DefaultDialer.Dial(wsURL, header)
+	}
+
+	t.Run("rejects a non-participant", func(t *testing.T) {
+		_, resp, err := dial(t, outsiderID)
+		if err == nil {
+			t.Fatal("Expected the upgrade to be rejected")
+		}
+		if resp == nil || resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %v", resp)
+		}
+	})
+
+	t.Run("broadcasts a participant's vote", func(t *testing.T) {
+		conn, _, err := dial(t, watcherID)
+		if err != nil {
+			t.Fatalf("Failed to open WebSocket: %v", err)
+		}
+		defer conn.Close()
+
+		ts.SetMockUserID(voterID.String())
+		ts.POST("/api/sessions/" + roomID.String() + "/vote").
+			WithJSON(map[string]interface{}{
+				"media_id": mediaID.String(),
+				"vote":     "yes",
+			}).
+			Expect().
+			Status(200)
+
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		var event RoomEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("Failed to read room event: %v", err)
+		}
+
+		if event.Type != RoomEventVote {
+			t.Errorf("Expected a vote event, got '%s'", event.Type)
+		}
+		if event.RoomID != roomID || event.UserID != voterID || event.MediaID != mediaID {
+			t.Errorf("Unexpected event %+v", event)
+		}
+		if event.Vote != "yes" {
+			t.Errorf("Expected vote 'yes', got '%s'", event.Vote)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_CompleteSession(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create a host and a participant who did not create the session
+	hostID := uuid.New()
+	ts.DB.SeedProfile(t, hostID, "complete_host")
+
+	guestID := uuid.New()
+	ts.DB.SeedProfile(t, guestID, "complete_guest")
+
+	sessionID := ts.DB.SeedWatchSession(t, hostID, "Finale Night", false)
+	ts.DB.SeedRoomParticipant(t, sessionID, guestID, "member", "joined")
+
+	completePath := "/api/sessions/" + sessionID.String() + "/complete"
+
+	t.Run("non-creator cannot complete the session", func(t *testing.T) {
+		ts.SetMockUserID(guestID.String())
+
+		ts.POST(completePath).
+			Expect().
+			Status(403)
+
+		// The session is left untouched
+		ts.GET("/api/sessions/"+sessionID.String()).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("status", "active")
+	})
+
+	t.Run("creator completes the session", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+
+		obj := ts.POST(completePath).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		session := obj.Value("session").Object()
+		session.ValueEqual("id", sessionID.String())
+		session.ValueEqual("creator_id", hostID.String())
+		session.ValueEqual("status", "completed")
+
+		// Nobody voted, so there are no matches
+		obj.Value("matches").Array().Length().IsEqual(0)
+		obj.ValueEqual("match_count", 0)
+	})
+
+	t.Run("missing session returns 404", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+
+		ts.POST("/api/sessions/" + uuid.New().String() + "/complete").
+			Expect().
+			Status(404)
+	})
+}
+
+func TestE2E_CompleteSession_ReturnsMatches(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	hostID := uuid.New()
+	ts.DB.SeedProfile(t, hostID, "flow_host")
+
+	friendID := uuid.New()
+	ts.DB.SeedProfile(t, friendID, "flow_friend")
+
+	matchedID := ts.DB.SeedMediaItem(t, 603, "movie", "The Matrix")
+	splitID := ts.DB.SeedMediaItem(t, 604, "movie", "The Matrix Reloaded")
+
+	// Create the session and invite the friend through the API
+	ts.SetMockUserID(hostID.String())
+
+	sessionID := ts.POST("/api/sessions").
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("id").String().Raw()
+
+	sessionPath := "/api/sessions/" + sessionID
+
+	// The friend follows the host, so their default invite preference lets the host invite them
+	ts.DB.SeedFollow(t, friendID, hostID)
+	ts.POST(sessionPath + "/participants").
+		WithJSON(map[string]interface{}{"user_id": friendID.String()}).
+		Expect().
+		Status(200)
+
+	// The invite must be accepted before the friend can vote
+	ts.SetMockUserID(friendID.String())
+	ts.POST("/api/rooms/" + sessionID + "/invite/respond").
+		WithJSON(map[string]interface{}{"accept": true}).
+		Expect().
+		Status(200)
+
+	castVote := func(userID, mediaID uuid.UUID, vote string) {
+		t.Helper()
+		ts.SetMockUserID(userID.String())
+		ts.POST(sessionPath + "/vote").
+			WithJSON(map[string]interface{}{
+				"media_id": mediaID.String(),
+				"vote":     vote,
+			}).
+			Expect().
+			Status(200)
+	}
+
+	castVote(hostID, matchedID, "yes")
+	castVote(friendID, matchedID, "yes")
+	castVote(hostID, splitID, "yes")
+	castVote(friendID, splitID, "no")
+
+	ts.SetMockUserID(hostID.String())
+
+	obj := ts.POST(sessionPath + "/complete").
+		Expect().
+		Status(200).
+		JSON().Object()
+
+	obj.Value("session").Object().ValueEqual("status", "completed")
+	obj.ValueEqual("match_count", 1)
+
+	matches := obj.Value("matches").Array()
+	matches.Length().IsEqual(1)
+
+	match := matches.Element(0).Object()
+	match.ValueEqual("id", matchedID.String())
+	match.ValueEqual("title", "The Matrix")
+	match.ValueEqual("yes_count", 2)
+}
+
+func TestE2E_SessionSnapshot(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	hostID := uuid.New()
+	ts.DB.SeedProfile(t, hostID, "snapshot_host")
+
+	friendID := uuid.New()
+	ts.DB.SeedProfile(t, friendID, "snapshot_friend")
+
+	otherID := uuid.New()
+	ts.DB.SeedProfile(t, otherID, "snapshot_other")
+
+	outsiderID := uuid.New()
+	ts.DB.SeedProfile(t, outsiderID, "snapshot_outsider")
+
+	sessionID := ts.DB.SeedWatchSession(t, hostID, "Snapshot Night", false)
+	mediaID := ts.DB.SeedMediaItem(t, 9401, "movie", "Snapshot Movie")
+
+	ts.DB.SeedVote(t, sessionID, hostID, mediaID, "yes")
+	ts.DB.SeedVote(t, sessionID, friendID, mediaID, "yes")
+	ts.DB.SeedVote(t, sessionID, otherID, mediaID, "no")
+
+	snapshotPath := "/api/sessions/" + sessionID.String() + "/snapshot"
+
+	t.Run("404 before the session is completed", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+
+		ts.GET(snapshotPath).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("lists the three voters after completion", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+
+		ts.POST("/api/sessions/" + sessionID.String() + "/complete").
+			Expect().
+			Status(200)
+
+		obj := ts.GET(snapshotPath).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		obj.ValueEqual("session_id", sessionID.String())
+		obj.ValueEqual("count", 3)
+		obj.Value("participants").Array().Length().Equal(3)
+	})
+
+	t.Run("outsiders cannot read the snapshot", func(t *testing.T) {
+		ts.SetMockUserID(outsiderID.String())
+
+		ts.GET(snapshotPath).
+			Expect().
+			Status(403)
+	})
+}
@@ -1,46 +1,66 @@
 package api
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/database"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
-	"github.com/google/uuid"
 )
 
 // SessionHandler handles session-related API endpoints
 type SessionHandler struct {
-	sessionRepo *database.SessionRepository
+	sessionRepo     *database.SessionRepository
+	voteRepo        *database.VoteRepository
+	idempotencyRepo *database.IdempotencyRepository
 }
 
 // NewSessionHandler creates a new session handler
-func NewSessionHandler(sessionRepo *database.SessionRepository) *SessionHandler {
+// A nil idempotencyRepo disables Idempotency-Key support on session creation
+func NewSessionHandler(sessionRepo *database.SessionRepository, voteRepo *database.VoteRepository, idempotencyRepo *database.IdempotencyRepository) *SessionHandler {
 	return &SessionHandler{
-		sessionRepo: sessionRepo,
+		sessionRepo:     sessionRepo,
+		voteRepo:        voteRepo,
+		idempotencyRepo: idempotencyRepo,
 	}
 }
 
+// maxSessionExpiryMinutes bounds expires_in_minutes to a week
+const maxSessionExpiryMinutes = 7 * 24 * 60
+
+// CreateSessionRequest represents the optional request body when creating a session
+type CreateSessionRequest struct {
+	// CloneFrom names a prior session whose candidate deck is copied into the new one
+	CloneFrom *string `json:"clone_from,omitempty"`
+	// ExpiresInMinutes sets a voting deadline; sessions without one never expire
+	ExpiresInMinutes *int `json:"expires_in_minutes,omitempty"`
+}
+
 // CreateSessionResponse represents the response when creating a session
 type CreateSessionResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID               string     `json:"id"`
+	Status           string     `json:"status"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	ClonedCandidates int        `json:"cloned_candidates,omitempty"`
 }
 
 // CreateSession handles POST /api/sessions
 func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get user ID from context (set by auth middleware)
 	userID, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
@@ -48,37 +68,142 @@ func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	creatorID, err := uuid.Parse(userID)
 	if err != nil {
 		log.Printf("Invalid user ID format: %v", err)
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	key, ok := idempotencyKey(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid Idempotency-Key")
+		return
+	}
+
+	// The body is optional; an empty one creates a blank session
+	var req CreateSessionRequest
+	if err := decodeJSONBody(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeBodyError(w, err)
+		return
+	}
+
+	var cloneFrom *uuid.UUID
+	if req.CloneFrom != nil {
+		cloneFromID, err := uuid.Parse(*req.CloneFrom)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid clone_from session ID format")
+			return
+		}
+		cloneFrom = &cloneFromID
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInMinutes != nil {
+		minutes := *req.ExpiresInMinutes
+		if minutes < 1 || minutes > maxSessionExpiryMinutes {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, fmt.Sprintf("expires_in_minutes must be between 1 and %d", maxSessionExpiryMinutes))
+			return
+		}
+		deadline := time.Now().Add(time.Duration(minutes) * time.Minute)
+		expiresAt = &deadline
+	}
+
+	ctx := r.Context()
+
+	if h.idempotencyRepo == nil {
+		key = ""
+	}
+
+	// A retried request gets the session its first attempt created
+	if h.replayCreateSession(w, r, creatorID, key) {
 		return
 	}
 
-	ctx := context.Background()
+	// Only decks from sessions the caller can see may be cloned
+	if cloneFrom != nil {
+		source, err := h.sessionRepo.GetSessionByID(ctx, *cloneFrom)
+		if err != nil {
+			log.Printf("Error getting session to clone: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
+			return
+		}
+
+		if source == nil {
+			writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session to clone not found")
+			return
+		}
 
-	// Create session in database
-	session, err := h.sessionRepo.CreateSession(ctx, creatorID)
+		canAccess, err := h.sessionRepo.CanAccessSession(ctx, *cloneFrom, creatorID)
+		if err != nil {
+			log.Printf("Error checking session access: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check session access")
+			return
+		}
+
+		if !canAccess {
+			writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to that session")
+			return
+		}
+	}
+
+	// The key is reserved and the deck cloned with the session, so of two concurrent requests sharing the key
+	// only one creates a session, and a failed clone leaves no empty session behind
+	session, cloned, err := h.sessionRepo.CreateSessionWithIdempotencyKey(ctx, creatorID, expiresAt, cloneFrom, key)
+	if errors.Is(err, database.ErrIdempotencyKeyUsed) {
+		if !h.replayCreateSession(w, r, creatorID, key) {
+			writeJSONError(w, http.StatusConflict, errCodeConflict, "Idempotency-Key is already in use")
+		}
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
-		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create session")
 		return
 	}
 
-	response := CreateSessionResponse{
-		ID:     session.ID.String(),
-		Status: session.Status,
+	writeJSON(w, r, http.StatusCreated, CreateSessionResponse{
+		ID:               session.ID.String(),
+		Status:           session.Status,
+		ExpiresAt:        session.ExpiresAt,
+		ClonedCandidates: cloned,
+	})
+}
+
+// replayCreateSession responds with the session an earlier request with key created, reporting whether it wrote a response
+// Nothing is written when the key is unused or its session has since been deleted
+func (h *SessionHandler) replayCreateSession(w http.ResponseWriter, r *http.Request, creatorID uuid.UUID, key string) bool {
+	ctx := r.Context()
+
+	prior, err := findIdempotentRequest(ctx, h.idempotencyRepo, creatorID, database.IdempotencyScopeSession, key)
+	if err != nil {
+		log.Printf("Error checking idempotency key: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create session")
+		return true
+	}
+	if prior == nil {
+		return false
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		return
+	session, err := h.sessionRepo.GetSessionByID(ctx, prior.ResourceID)
+	if err != nil {
+		log.Printf("Error getting session: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create session")
+		return true
+	}
+	if session == nil {
+		return false
 	}
+
+	writeJSON(w, r, prior.StatusCode, CreateSessionResponse{
+		ID:        session.ID.String(),
+		Status:    session.Status,
+		ExpiresAt: session.ExpiresAt,
+	})
+	return true
 }
 
 // GetSession handles GET /api/sessions/{id}
 func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -87,44 +212,111 @@ func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 3 {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	sessionIDStr := parts[2]
 	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		http.Error(w, "Invalid session ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Get session from database
 	session, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error getting session: %v", err)
-		http.Error(w, "Failed to get session", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
 		return
 	}
 
 	if session == nil {
-		http.Error(w, "Session not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, session)
+}
+
+// DeleteSession handles DELETE /api/sessions/{id}
+// Only the session creator may delete it; its votes are removed with it
+func (h *SessionHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(session); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	err = h.sessionRepo.DeleteSession(r.Context(), sessionID, userID)
+	if errors.Is(err, database.ErrSessionNotFound) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
 		return
 	}
+	if errors.Is(err, database.ErrNotSessionCreator) {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only the session creator can delete the session")
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting session %s: %v", sessionID, err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to delete session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompleteSessionResponse is a completed session along with its final matches
+type CompleteSessionResponse struct {
+	Session    *database.WatchSession `json:"session"`
+	Matches    []MatchResponse        `json:"matches"`
+	MatchCount int                    `json:"match_count"`
 }
 
 // CompleteSession handles POST /api/sessions/{id}/complete
+// Only the session creator may complete the session; the final matches are returned so clients needn't fetch them
 func (h *SessionHandler) CompleteSession(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -133,36 +325,296 @@ func (h *SessionHandler) CompleteSession(w http.ResponseWriter, r *http.Request)
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 4 || parts[3] != "complete" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
-	sessionIDStr := parts[2]
-	sessionID, err := uuid.Parse(sessionIDStr)
+	sessionID, err := uuid.Parse(parts[2])
 	if err != nil {
-		http.Error(w, "Invalid session ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
+
+	existing, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting session: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
+		return
+	}
+
+	if existing == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	if existing.CreatorID != userID {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only the session creator can complete the session")
+		return
+	}
 
 	// Complete the session
 	session, err := h.sessionRepo.CompleteSession(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error completing session: %v", err)
-		http.Error(w, "Failed to complete session", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to complete session")
 		return
 	}
 
 	if session == nil {
-		http.Error(w, "Session not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(session); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	// Completing again is harmless, so a client can recover from a failed read by retrying
+	var matches []database.MatchResult
+	err = retryRead(ctx, func() error {
+		var err error
+		matches, err = h.voteRepo.GetMatchesForSession(ctx, sessionID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get matches")
+		return
+	}
+
+	items := make([]MatchResponse, 0, len(matches))
+	for _, match := range matches {
+		items = append(items, MatchResponse{
+			MediaResponse: newMediaResponse(r, match.MediaItem),
+			YesCount:      match.YesCount,
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, CompleteSessionResponse{
+		Session:    session,
+		Matches:    items,
+		MatchCount: len(items),
+	})
+}
+
+// SessionSnapshotResponse lists who had voted in a session when it was completed
+type SessionSnapshotResponse struct {
+	SessionID    uuid.UUID          `json:"session_id"`
+	Participants []database.Profile `json:"participants"`
+	Count        int                `json:"count"`
+}
+
+// GetSnapshot handles GET /api/sessions/{id}/snapshot
+// Only sessions that have been completed have a snapshot; anyone who can read the session may fetch it
+func (h *SessionHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/snapshot
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "snapshot" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	ctx := r.Context()
+
+	var session *database.WatchSession
+	err = retryRead(ctx, func() error {
+		var err error
+		session, err = h.sessionRepo.GetSessionByID(ctx, sessionID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get session")
+		return
+	}
+
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	canAccess, err := h.sessionRepo.CanAccessSession(ctx, sessionID, userID)
+	if err != nil {
+		log.Printf("Error checking session access: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check session access")
+		return
+	}
+
+	if !canAccess {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "You do not have access to this session")
+		return
+	}
+
+	if session.Status != "completed" {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session has not been completed")
+		return
+	}
+
+	var participants []database.Profile
+	err = retryRead(ctx, func() error {
+		var err error
+		participants, err = h.sessionRepo.GetSnapshot(ctx, sessionID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get session snapshot")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, SessionSnapshotResponse{
+		SessionID:    sessionID,
+		Participants: participants,
+		Count:        len(participants),
+	})
+}
+
+// AddParticipantRequest represents the request to add a voter to a session
+type AddParticipantRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// AddParticipant handles POST /api/sessions/{id}/participants
+// Only the session creator may add participants, who are invited and vote once they accept
+func (h *SessionHandler) AddParticipant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/participants
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "participants" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	var req AddParticipantRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	participantID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	session, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting session: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
+		return
+	}
+
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	if session.CreatorID != userID {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Only the session creator can add participants")
+		return
+	}
+
+	invited, err := h.sessionRepo.AddSessionParticipant(ctx, sessionID, participantID)
+	if errors.Is(err, database.ErrUserNotFound) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error adding session participant: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to add participant")
+		return
+	}
+
+	if !invited {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "User does not accept invitations from you")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"user_id": participantID,
+	})
+}
+
+// GetUnfinishedSessions handles GET /api/me/unfinished
+func (h *SessionHandler) GetUnfinishedSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	sessions, err := h.sessionRepo.GetUnfinishedSessions(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting unfinished sessions: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get unfinished sessions")
+		return
+	}
+
+	if sessions == nil {
+		sessions = []database.UnfinishedSession{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
 }
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+// blockingConnector is a database/sql connector whose queries block until their context ends,
+// recording the context error the driver observed
+type blockingConnector struct {
+	started  chan struct{}
+	observed chan error
+}
+
+func (c *blockingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &blockingConn{connector: c}, nil
+}
+
+func (c *blockingConnector) Driver() driver.Driver {
+	return faultyDriver{}
+}
+
+type blockingConn struct {
+	connector *blockingConnector
+}
+
+func (c *blockingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	close(c.connector.started)
+	<-ctx.Done()
+	c.connector.observed <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func (c *blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("blockingConn: prepare not supported")
+}
+
+func (c *blockingConn) Close() error { return nil }
+
+func (c *blockingConn) Begin() (driver.Tx, error) {
+	return blockingTx{}, nil
+}
+
+// blockingTx lets repositories open a transaction so their first query reaches the blocking connection
+type blockingTx struct{}
+
+func (blockingTx) Commit() error   { return nil }
+func (blockingTx) Rollback() error { return nil }
+
+func TestSessionHandler_CreateSession_PropagatesRequestContext(t *testing.T) {
+	connector := &blockingConnector{
+		started:  make(chan struct{}),
+		observed: make(chan error, 1),
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	handler := NewSessionHandler(database.NewSessionRepository(db), database.NewVoteRepository(db), nil)
+
+	ctx, cancel := context.WithCancel(middleware.SetUserID(context.Background(), uuid.New().String()))
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.CreateSession(rec, req)
+		close(done)
+	}()
+
+	select {
+	case <-connector.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the repository query to reach the database")
+	}
+
+	// Simulate the client disconnecting mid-request
+	cancel()
+
+	select {
+	case err := <-connector.observed:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the query to observe context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the query to be cancelled with the request context")
+	}
+
+	<-done
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+}
+
+func TestSessionHandler_CreateSession_ValidatesExpiry(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewSessionHandler(database.NewSessionRepository(db), database.NewVoteRepository(db), nil)
+
+	for _, body := range []string{
+		`{"expires_in_minutes":0}`,
+		`{"expires_in_minutes":-30}`,
+		`{"expires_in_minutes":10081}`,
+	} {
+		t.Run(body, func(t *testing.T) {
+			before := connector.queryCount()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(body))
+			req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+			rec := httptest.NewRecorder()
+
+			handler.CreateSession(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if connector.queryCount() != before {
+				t.Error("Expected no query for an invalid expiry")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_FollowUnknownUser(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	followerID := uuid.New()
+	ts.DB.SeedProfile(t, followerID, "lonely_follower")
+	ts.SetMockUserID(followerID.String())
+
+	ts.POST("/api/follows/" + uuid.New().String()).
+		Expect().
+		Status(404)
+}
+
+func TestE2E_RestoreFollow(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	followerID := uuid.New()
+	ts.DB.SeedProfile(t, followerID, "fickle_follower")
+	ts.SetMockUserID(followerID.String())
+
+	followingID := uuid.New()
+	ts.DB.SeedProfile(t, followingID, "followed_user")
+	ts.DB.SeedFollow(t, followerID, followingID)
+
+	restorePath := "/api/follows/" + followingID.String() + "/restore"
+
+	t.Run("nothing to restore while following", func(t *testing.T) {
+		ts.POST(restorePath).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("restores an accidental unfollow", func(t *testing.T) {
+		ts.DELETE("/api/follows/" + followingID.String()).
+			Expect().
+			Status(200)
+
+		ts.POST(restorePath).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+
+		ts.GET("/api/me/following").
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("total", 1)
+	})
+
+	t.Run("rejects a malformed user ID", func(t *testing.T) {
+		ts.POST("/api/follows/not-a-uuid/restore").
+			Expect().
+			Status(400)
+	})
+}
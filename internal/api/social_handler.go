@@ -1,15 +1,14 @@
 package api
 
 import (
-	"context"
-	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/database"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
-	"github.com/google/uuid"
 )
 
 // SocialHandler handles social-related API endpoints
@@ -27,20 +26,20 @@ func NewSocialHandler(socialRepo *database.SocialRepository) *SocialHandler {
 // FollowUser handles POST /api/follows/{id}
 func (h *SocialHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	followerID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -49,33 +48,36 @@ func (h *SocialHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 3 {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	followingID, err := uuid.Parse(parts[2])
 	if err != nil {
-		http.Error(w, "Invalid target user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
 		return
 	}
 
 	// Prevent self-follow
 	if followerID == followingID {
-		http.Error(w, "Cannot follow yourself", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Cannot follow yourself")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Follow the user
 	if err := h.socialRepo.FollowUser(ctx, followerID, followingID); err != nil {
+		if database.IsForeignKeyViolation(err) {
+			writeJSONError(w, http.StatusNotFound, errCodeNotFound, "User not found")
+			return
+		}
 		log.Printf("Error following user: %v", err)
-		http.Error(w, "Failed to follow user", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to follow user")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"message": "User followed successfully",
 	})
@@ -84,20 +86,20 @@ func (h *SocialHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 // UnfollowUser handles DELETE /api/follows/{id}
 func (h *SocialHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	followerID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -105,194 +107,491 @@ func (h *SocialHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 3 {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	followingID, err := uuid.Parse(parts[2])
 	if err != nil {
-		http.Error(w, "Invalid target user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Unfollow the user
 	if err := h.socialRepo.UnfollowUser(ctx, followerID, followingID); err != nil {
 		log.Printf("Error unfollowing user: %v", err)
-		http.Error(w, "Failed to unfollow user", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to unfollow user")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"message": "User unfollowed successfully",
 	})
 }
 
-// GetFollowing handles GET /api/me/following
+// RestoreFollow handles POST /api/follows/{id}/restore
+// It undoes a recent unfollow; once the restore window has passed the user must follow again
+func (h *SocialHandler) RestoreFollow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	followerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract target user ID from URL
+	// Expected format: /api/follows/{id}/restore
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "restore" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	followingID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
+		return
+	}
+
+	restored, err := h.socialRepo.RestoreFollow(r.Context(), followerID, followingID)
+	if err != nil {
+		log.Printf("Error restoring follow: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to restore follow")
+		return
+	}
+
+	if !restored {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "No recent unfollow to restore")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Follow restored successfully",
+	})
+}
+
+// maxBulkUnfollow caps how many users one bulk unfollow request may name
+const maxBulkUnfollow = 100
+
+// BulkUnfollowRequest represents the request body for unfollowing several users at once
+type BulkUnfollowRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// BulkUnfollow handles POST /api/me/following/bulk-unfollow
+func (h *SocialHandler) BulkUnfollow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	followerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req BulkUnfollowRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(req.UserIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "user_ids is required")
+		return
+	}
+
+	if len(req.UserIDs) > maxBulkUnfollow {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Too many user_ids")
+		return
+	}
+
+	followingIDs := make([]uuid.UUID, 0, len(req.UserIDs))
+	for _, idStr := range req.UserIDs {
+		followingID, err := uuid.Parse(idStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid target user ID")
+			return
+		}
+		followingIDs = append(followingIDs, followingID)
+	}
+
+	ctx := r.Context()
+
+	removed, err := h.socialRepo.UnfollowUsers(ctx, followerID, followingIDs)
+	if err != nil {
+		log.Printf("Error bulk unfollowing users: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to unfollow users")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"removed": removed,
+	})
+}
+
+// maxProfileBatch caps how many profiles one batch lookup may request
+const maxProfileBatch = 100
+
+// ProfileBatchRequest represents the request body for looking up several profiles at once
+type ProfileBatchRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// GetProfilesBatch handles POST /api/users/batch
+// Profiles are returned in request order; unknown IDs are omitted
+func (h *SocialHandler) GetProfilesBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, ok := middleware.GetUserID(r.Context()); !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	var req ProfileBatchRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "ids is required")
+		return
+	}
+
+	if len(req.IDs) > maxProfileBatch {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Too many ids")
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(req.IDs))
+	for _, idStr := range req.IDs {
+		userID, err := uuid.Parse(idStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	ctx := r.Context()
+
+	var profiles []database.Profile
+	err := retryRead(ctx, func() error {
+		var err error
+		profiles, err = h.socialRepo.GetProfilesByIDs(ctx, userIDs)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get profiles")
+		return
+	}
+
+	if profiles == nil {
+		profiles = []database.Profile{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"profiles": profiles,
+		"count":    len(profiles),
+	})
+}
+
+// GetFollowing handles GET /api/me/following?limit=&offset=
 func (h *SocialHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get current user ID
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
-	ctx := context.Background()
+	limit := database.DefaultFollowingLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > database.MaxFollowingLimit {
+			limit = database.MaxFollowingLimit
+		}
+	}
 
-	// Get following list
-	following, err := h.socialRepo.GetFollowing(ctx, userID)
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	// Get a page of the following list
+	var following []database.UserSearchResult
+	var total int
+	err = retryRead(ctx, func() error {
+		var err error
+		following, total, err = h.socialRepo.GetFollowing(ctx, userID, limit, offset)
+		return err
+	})
 	if err != nil {
-		log.Printf("Error getting following: %v", err)
-		http.Error(w, "Failed to get following", http.StatusInternalServerError)
+		writeReadError(w, r, err, "Failed to get following")
 		return
 	}
 
 	if following == nil {
-		following = []database.Profile{}
+		following = []database.UserSearchResult{}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
 		"following": following,
 		"count":     len(following),
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
 	})
 }
 
-// SearchUsers handles GET /api/users/search?q=
+// GetFriends handles GET /api/me/friends
+// Friends are mutual follows: users the caller follows who also follow the caller
+func (h *SocialHandler) GetFriends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get current user ID
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := r.Context()
+
+	var friends []database.Profile
+	err = retryRead(ctx, func() error {
+		var err error
+		friends, err = h.socialRepo.GetMutuals(ctx, userID)
+		return err
+	})
+	if err != nil {
+		writeReadError(w, r, err, "Failed to get friends")
+		return
+	}
+
+	if friends == nil {
+		friends = []database.Profile{}
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"friends": friends,
+		"count":   len(friends),
+	})
+}
+
+// SearchUsers handles GET /api/users/search?q=&limit=&offset=
 func (h *SocialHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Query parameter 'q' is required")
 		return
 	}
 
-	ctx := context.Background()
+	limit := database.DefaultSearchLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid limit")
+			return
+		}
+		if limit > database.MaxSearchLimit {
+			limit = database.MaxSearchLimit
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	ctx := r.Context()
 
-	// Search for users
-	users, err := h.socialRepo.SearchUsers(ctx, query)
+	// Search for users the caller could follow
+	users, total, err := h.socialRepo.SearchUsersForFollow(ctx, userID, query, limit, offset)
 	if err != nil {
 		log.Printf("Error searching users: %v", err)
-		http.Error(w, "Failed to search users", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to search users")
 		return
 	}
 
 	if users == nil {
-		users = []database.Profile{}
+		users = []database.UserSearchResult{}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"users": users,
-		"count": len(users),
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"users":  users,
+		"count":  len(users),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
 	})
 }
 
 // GetProfile handles GET /api/me/profile
 func (h *SocialHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 	profile, err := h.socialRepo.GetProfile(ctx, userID)
 	if err != nil {
 		log.Printf("Error getting profile: %v", err)
-		http.Error(w, "Failed to get profile", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get profile")
 		return
 	}
 
 	if profile == nil {
 		// Return 404 or empty profile? Let's return 404 so frontend knows to create one
-		http.Error(w, "Profile not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Profile not found")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(profile)
+	writeJSON(w, r, http.StatusOK, profile)
 }
 
 type UpdateProfileRequest struct {
 	Username         string `json:"username"`
 	InvitePreference string `json:"invite_preference"`
+	// IncludeAdult opts in to adult search results; omitting it keeps the current setting
+	IncludeAdult *bool `json:"include_adult,omitempty"`
 }
 
 // UpdateProfile handles PUT /api/me/profile
 func (h *SocialHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
 	var req UpdateProfileRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validate inputs
-	if req.Username == "" {
-		http.Error(w, "Username is required", http.StatusBadRequest)
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
-	if req.InvitePreference != "everyone" && req.InvitePreference != "following" && req.InvitePreference != "none" {
-		http.Error(w, "Invalid invite preference", http.StatusBadRequest)
+	// Validate every field so clients can fix them all in one round trip
+	var v validator
+	v.check(req.Username != "", "username", "required")
+	v.check(req.InvitePreference == "everyone" || req.InvitePreference == "following" || req.InvitePreference == "none",
+		"invite_preference", "must be one of everyone, following, none")
+	if !v.valid() {
+		writeValidationErrors(w, &v)
 		return
 	}
 
-	ctx := context.Background()
-	if err := h.socialRepo.CreateOrUpdateProfile(ctx, userID, req.Username, req.InvitePreference); err != nil {
+	ctx := r.Context()
+	if err := h.socialRepo.CreateOrUpdateProfile(ctx, userID, req.Username, req.InvitePreference, req.IncludeAdult); err != nil {
 		log.Printf("Error updating profile: %v", err)
 		// Check for unique violation on username
 		if strings.Contains(err.Error(), "unique constraint") || strings.Contains(err.Error(), "duplicate key") {
-			http.Error(w, "Username already taken", http.StatusConflict)
+			writeJSONError(w, http.StatusConflict, errCodeConflict, "Username already taken")
 			return
 		}
-		http.Error(w, "Failed to update profile", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update profile")
 		return
 	}
 
@@ -304,6 +603,5 @@ func (h *SocialHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(profile)
+	writeJSON(w, r, http.StatusOK, profile)
 }
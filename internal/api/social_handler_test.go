@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/middleware"
+)
+
+func TestSocialHandler_FollowUserErrors(t *testing.T) {
+	serve := func(t *testing.T, err error) *httptest.ResponseRecorder {
+		t.Helper()
+
+		db, _ := newFaultyDB(t, -1, err)
+		handler := NewSocialHandler(database.NewSocialRepository(db))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/follows/"+uuid.New().String(), nil)
+		req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+		rec := httptest.NewRecorder()
+		handler.FollowUser(rec, req)
+		return rec
+	}
+
+	t.Run("returns 404 when the target user does not exist", func(t *testing.T) {
+		rec := serve(t, &pgconn.PgError{Code: "23503", ConstraintName: "user_follows_following_id_fkey"})
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("returns 500 for other database errors", func(t *testing.T) {
+		rec := serve(t, errors.New("syntax error"))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestSocialHandler_GetProfilesBatch(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewSocialHandler(database.NewSocialRepository(db))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/users/batch", strings.NewReader(body))
+		req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+		rec := httptest.NewRecorder()
+		handler.GetProfilesBatch(rec, req)
+		return rec
+	}
+
+	idList := func(n int) string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = `"` + uuid.New().String() + `"`
+		}
+		return `{"ids":[` + strings.Join(ids, ",") + `]}`
+	}
+
+	t.Run("accepts up to the cap", func(t *testing.T) {
+		rec := serve(idList(maxProfileBatch))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), `"profiles":[]`) {
+			t.Errorf("Expected an empty profile list for unknown IDs, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("rejects more than the cap without querying", func(t *testing.T) {
+		before := connector.queryCount()
+		rec := serve(idList(maxProfileBatch + 1))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if connector.queryCount() != before {
+			t.Error("Expected no query for an oversized batch")
+		}
+	})
+
+	t.Run("rejects an empty or malformed list", func(t *testing.T) {
+		for _, body := range []string{`{"ids":[]}`, `{"ids":["not-a-uuid"]}`} {
+			if rec := serve(body); rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+			}
+		}
+	})
+}
+
+func TestSocialHandler_UpdateProfileReportsEveryValidationError(t *testing.T) {
+	db, connector := newFaultyDB(t, 0, nil)
+	handler := NewSocialHandler(database.NewSocialRepository(db))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/me/profile", strings.NewReader(`{"username":"","invite_preference":"friends-only"}`))
+	req = req.WithContext(middleware.SetUserID(req.Context(), uuid.New().String()))
+	rec := httptest.NewRecorder()
+	handler.UpdateProfile(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if connector.queryCount() != 0 {
+		t.Error("Expected no queries for an invalid profile")
+	}
+
+	var resp ValidationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Error.Code != errCodeValidation {
+		t.Errorf("Expected code %q, got %q", errCodeValidation, resp.Error.Code)
+	}
+
+	expected := []FieldError{
+		{Field: "username", Message: "required"},
+		{Field: "invite_preference", Message: "must be one of everyone, following, none"},
+	}
+	if len(resp.Errors) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %v", len(expected), resp.Errors)
+	}
+	for i, fieldErr := range expected {
+		if resp.Errors[i] != fieldErr {
+			t.Errorf("Expected error %d to be %+v, got %+v", i, fieldErr, resp.Errors[i])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// FieldError describes one invalid field in a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse is the error envelope plus every field that failed validation
+type ValidationErrorResponse struct {
+	Error  ErrorDetail  `json:"error"`
+	Errors []FieldError `json:"errors"`
+}
+
+// validator accumulates field errors so one response can report every problem at once
+type validator struct {
+	errors []FieldError
+}
+
+// check records message against field unless ok holds
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.errors = append(v.errors, FieldError{Field: field, Message: message})
+	}
+}
+
+// valid reports whether every check so far has passed
+func (v *validator) valid() bool {
+	return len(v.errors) == 0
+}
+
+// writeValidationErrors responds with 400 and the accumulated field errors
+func writeValidationErrors(w http.ResponseWriter, v *validator) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{
+		Error:  ErrorDetail{Code: errCodeValidation, Message: "Request validation failed"},
+		Errors: v.errors,
+	})
+}
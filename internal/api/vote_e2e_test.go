@@ -0,0 +1,419 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestE2E_CastVote(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create two voters, a session, and a movie
+	user1ID := uuid.New()
+	ts.DB.SeedProfile(t, user1ID, "voter_one")
+
+	user2ID := uuid.New()
+	ts.DB.SeedProfile(t, user2ID, "voter_two")
+
+	sessionID := ts.DB.SeedWatchSession(t, user1ID, "Vote Night", false)
+	mediaID := ts.DB.SeedMediaItem(t, 27205, "movie", "Inception")
+
+	// Only the creator and joined participants may vote
+	ts.DB.SeedRoomParticipant(t, sessionID, user2ID, "member", "joined")
+
+	votePath := "/api/sessions/" + sessionID.String() + "/vote"
+
+	t.Run("first yes vote is not a match", func(t *testing.T) {
+		ts.SetMockUserID(user1ID.String())
+
+		obj := ts.POST(votePath).
+			WithJSON(map[string]interface{}{
+				"media_id": mediaID.String(),
+				"vote":     "yes",
+			}).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		obj.ValueEqual("success", true)
+		obj.ValueEqual("is_match", false)
+		obj.NotContainsKey("matched_media")
+	})
+
+	t.Run("second yes vote returns the matched media", func(t *testing.T) {
+		ts.SetMockUserID(user2ID.String())
+
+		obj := ts.POST(votePath).
+			WithJSON(map[string]interface{}{
+				"media_id": mediaID.String(),
+				"vote":     "yes",
+			}).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		obj.ValueEqual("is_match", true)
+
+		media := obj.Value("matched_media").Object()
+		media.ValueEqual("id", mediaID.String())
+		media.ValueEqual("tmdb_id", 27205)
+		media.ValueEqual("title", "Inception")
+	})
+
+	t.Run("only users with access to the session see the match voters", func(t *testing.T) {
+		votersPath := "/api/sessions/" + sessionID.String() + "/matches/" + mediaID.String() + "/voters"
+
+		ts.SetMockUserID(user1ID.String())
+		ts.GET(votersPath).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("count", 2)
+
+		outsiderID := uuid.New()
+		ts.DB.SeedProfile(t, outsiderID, "voter_outsider")
+		ts.SetMockUserID(outsiderID.String())
+		ts.GET(votersPath).
+			Expect().
+			Status(403)
+	})
+}
+
+func TestE2E_CastVote_Concurrent(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	const voters = 5
+
+	userIDs := make([]uuid.UUID, voters)
+	for i := range userIDs {
+		userIDs[i] = uuid.New()
+		ts.DB.SeedProfile(t, userIDs[i], fmt.Sprintf("racing_voter%d", i))
+	}
+
+	sessionID := ts.DB.SeedWatchSession(t, userIDs[0], "Race Night", false)
+	for _, userID := range userIDs[1:] {
+		ts.DB.SeedRoomParticipant(t, sessionID, userID, "member", "joined")
+	}
+	mediaID := ts.DB.SeedMediaItem(t, 603, "movie", "The Matrix")
+
+	body := fmt.Sprintf(`{"media_id":%q,"vote":"yes"}`, mediaID.String())
+	responses := make([]VoteResponse, voters)
+	errs := make([]error, voters)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i int, userID uuid.UUID) {
+			defer wg.Done()
+			<-start
+
+			req, err := http.NewRequest(http.MethodPost, ts.Server.URL+"/api/sessions/"+sessionID.String()+"/vote", strings.NewReader(body))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Test-User-ID", userID.String())
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				errs[i] = fmt.Errorf("status %d", resp.StatusCode)
+				return
+			}
+			errs[i] = json.NewDecoder(resp.Body).Decode(&responses[i])
+		}(i, userID)
+	}
+	close(start)
+	wg.Wait()
+
+	matches := 0
+	for i, resp := range responses {
+		if errs[i] != nil {
+			t.Fatalf("Vote request failed: %v", errs[i])
+		}
+		if !resp.IsMatch {
+			continue
+		}
+		matches++
+		if resp.MatchedMedia == nil || resp.MatchedMedia.ID != mediaID {
+			t.Errorf("Expected the claiming vote to return the matched media, got %+v", resp.MatchedMedia)
+		}
+	}
+
+	if matches != 1 {
+		t.Errorf("Expected exactly 1 vote to report the match, got %d", matches)
+	}
+}
+
+func TestE2E_CastVote_Participants(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	// Create a host, an invited friend, and a stranger
+	hostID := uuid.New()
+	ts.DB.SeedProfile(t, hostID, "session_host")
+
+	friendID := uuid.New()
+	ts.DB.SeedProfile(t, friendID, "session_friend")
+
+	strangerID := uuid.New()
+	ts.DB.SeedProfile(t, strangerID, "session_stranger")
+
+	sessionID := ts.DB.SeedWatchSession(t, hostID, "Invite Only", false)
+	mediaID := ts.DB.SeedMediaItem(t, 155, "movie", "The Dark Knight")
+
+	sessionPath := "/api/sessions/" + sessionID.String()
+	vote := map[string]interface{}{
+		"media_id": mediaID.String(),
+		"vote":     "yes",
+	}
+
+	t.Run("non-participant vote is rejected", func(t *testing.T) {
+		ts.SetMockUserID(strangerID.String())
+		ts.POST(sessionPath + "/vote").
+			WithJSON(vote).
+			Expect().
+			Status(403)
+
+		ts.POST(sessionPath + "/votes").
+			WithJSON([]map[string]interface{}{vote}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("non-participant cannot check matches", func(t *testing.T) {
+		ts.SetMockUserID(strangerID.String())
+		ts.POST(sessionPath + "/matches/check").
+			WithJSON(map[string]interface{}{"media_ids": []string{mediaID.String()}}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("non-participant cannot read matches", func(t *testing.T) {
+		ts.SetMockUserID(strangerID.String())
+		ts.GET(sessionPath + "/matches").
+			Expect().
+			Status(403)
+
+		ts.GET(sessionPath + "/matches/unseen").
+			Expect().
+			Status(403)
+
+		// A refused read records no view
+		var views int
+		if err := ts.DB.DB.QueryRow(`SELECT COUNT(*) FROM session_views WHERE session_id = $1`, sessionID).Scan(&views); err != nil {
+			t.Fatalf("Failed to count session views: %v", err)
+		}
+		if views != 0 {
+			t.Errorf("Expected no session view, got %d", views)
+		}
+	})
+
+	t.Run("only the creator can add participants", func(t *testing.T) {
+		ts.SetMockUserID(strangerID.String())
+		ts.POST(sessionPath + "/participants").
+			WithJSON(map[string]interface{}{"user_id": strangerID.String()}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("adding an unknown user returns 404", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+		ts.POST(sessionPath + "/participants").
+			WithJSON(map[string]interface{}{"user_id": uuid.New().String()}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("an invited participant cannot vote before accepting", func(t *testing.T) {
+		// The friend follows the host, so their default invite preference lets the host invite them
+		ts.DB.SeedFollow(t, friendID, hostID)
+
+		ts.SetMockUserID(hostID.String())
+		ts.POST(sessionPath+"/participants").
+			WithJSON(map[string]interface{}{"user_id": friendID.String()}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+
+		ts.SetMockUserID(friendID.String())
+		ts.POST(sessionPath + "/vote").
+			WithJSON(vote).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("the host cannot invite someone who does not accept their invitations", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+		ts.POST(sessionPath + "/participants").
+			WithJSON(map[string]interface{}{"user_id": strangerID.String()}).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("participant vote succeeds", func(t *testing.T) {
+		ts.SetMockUserID(friendID.String())
+		ts.POST("/api/rooms/" + sessionID.String() + "/invite/respond").
+			WithJSON(map[string]interface{}{"accept": true}).
+			Expect().
+			Status(200)
+
+		ts.POST(sessionPath+"/vote").
+			WithJSON(vote).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+	})
+
+	t.Run("creator can always vote", func(t *testing.T) {
+		ts.SetMockUserID(hostID.String())
+		ts.POST(sessionPath+"/vote").
+			WithJSON(vote).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("is_match", true)
+	})
+
+	t.Run("a batch does not report an already claimed match", func(t *testing.T) {
+		ts.SetMockUserID(friendID.String())
+		ts.POST(sessionPath + "/votes").
+			WithJSON([]map[string]interface{}{vote}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("matches").Array().Length().IsEqual(0)
+	})
+}
+
+func TestE2E_CastVote_Expiry(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	userID := uuid.New()
+	ts.DB.SeedProfile(t, userID, "deadline_voter")
+	ts.SetMockUserID(userID.String())
+
+	mediaID := ts.DB.SeedMediaItem(t, 27205, "movie", "Inception")
+
+	t.Run("rejects a non-positive expires_in_minutes", func(t *testing.T) {
+		for _, minutes := range []int{0, -5} {
+			ts.POST("/api/sessions").
+				WithJSON(map[string]interface{}{"expires_in_minutes": minutes}).
+				Expect().
+				Status(400)
+		}
+	})
+
+	sessionID := ts.POST("/api/sessions").
+		WithJSON(map[string]interface{}{"expires_in_minutes": 30}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		ContainsKey("expires_at").
+		Value("id").String().Raw()
+
+	votePath := "/api/sessions/" + sessionID + "/vote"
+	vote := map[string]interface{}{
+		"media_id": mediaID.String(),
+		"vote":     "yes",
+	}
+
+	t.Run("accepts votes before the deadline", func(t *testing.T) {
+		ts.POST(votePath).
+			WithJSON(vote).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("success", true)
+	})
+
+	t.Run("rejects votes after the deadline with 410", func(t *testing.T) {
+		if _, err := ts.DB.DB.Exec(`UPDATE watch_sessions SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, sessionID); err != nil {
+			t.Fatalf("Failed to expire session: %v", err)
+		}
+
+		ts.POST(votePath).
+			WithJSON(vote).
+			Expect().
+			Status(410).
+			JSON().Object().
+			Value("error").Object().
+			ValueEqual("code", errCodeSessionExpired)
+
+		ts.POST("/api/sessions/" + sessionID + "/votes").
+			WithJSON([]map[string]interface{}{vote}).
+			Expect().
+			Status(410)
+	})
+}
+
+func TestE2E_CastVote_Seasons(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Close()
+	defer ts.DB.Cleanup(t)
+
+	user1ID := uuid.New()
+	ts.DB.SeedProfile(t, user1ID, "season_one")
+
+	user2ID := uuid.New()
+	ts.DB.SeedProfile(t, user2ID, "season_two")
+
+	sessionID := ts.DB.SeedWatchSession(t, user1ID, "Binge Night", false)
+	showID := ts.DB.SeedMediaItem(t, 1399, "tv", "Game of Thrones")
+	ts.DB.SeedRoomParticipant(t, sessionID, user2ID, "member", "joined")
+
+	votePath := "/api/sessions/" + sessionID.String() + "/vote"
+	seasonVote := func(season int) map[string]interface{} {
+		return map[string]interface{}{
+			"media_id":      showID.String(),
+			"season_number": season,
+			"vote":          "yes",
+		}
+	}
+
+	t.Run("one user's yes on two seasons is not a match", func(t *testing.T) {
+		ts.SetMockUserID(user1ID.String())
+
+		for _, season := range []int{1, 2} {
+			ts.POST(votePath).
+				WithJSON(seasonVote(season)).
+				Expect().
+				Status(200).
+				JSON().Object().
+				ValueEqual("is_match", false)
+		}
+	})
+
+	t.Run("a second user's season vote makes the match", func(t *testing.T) {
+		ts.SetMockUserID(user2ID.String())
+
+		ts.POST(votePath).
+			WithJSON(seasonVote(1)).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ValueEqual("is_match", true)
+	})
+}
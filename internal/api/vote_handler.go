@@ -2,60 +2,68 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/database"
 	"github.com/tahaburak/would-watch-backend/internal/middleware"
-	"github.com/google/uuid"
 )
 
 // VoteHandler handles vote-related API endpoints
 type VoteHandler struct {
 	voteRepo    *database.VoteRepository
 	sessionRepo *database.SessionRepository
+	mediaRepo   *database.MediaRepository
+	hub         *RoomHub
 }
 
 // NewVoteHandler creates a new vote handler
-func NewVoteHandler(voteRepo *database.VoteRepository, sessionRepo *database.SessionRepository) *VoteHandler {
+// Votes and matches are published to hub for live room subscribers; hub may be nil
+func NewVoteHandler(voteRepo *database.VoteRepository, sessionRepo *database.SessionRepository, mediaRepo *database.MediaRepository, hub *RoomHub) *VoteHandler {
 	return &VoteHandler{
 		voteRepo:    voteRepo,
 		sessionRepo: sessionRepo,
+		mediaRepo:   mediaRepo,
+		hub:         hub,
 	}
 }
 
 // VoteRequest represents the request body for casting a vote
+// SeasonNumber optionally scopes the vote to one season of a TV show
 type VoteRequest struct {
-	MediaID string `json:"media_id"`
-	Vote    string `json:"vote"`
+	MediaID      string `json:"media_id"`
+	SeasonNumber *int   `json:"season_number,omitempty"`
+	Vote         string `json:"vote"`
 }
 
 // VoteResponse represents the response after casting a vote
 type VoteResponse struct {
-	Success bool `json:"success"`
-	IsMatch bool `json:"is_match"`
+	Success      bool                `json:"success"`
+	IsMatch      bool                `json:"is_match"`
+	MatchedMedia *database.MediaItem `json:"matched_media,omitempty"`
 }
 
 // CastVote handles POST /api/sessions/{id}/vote
 func (h *VoteHandler) CastVote(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get user ID from context
 	userIDStr, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, "User ID not found", http.StatusUnauthorized)
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
 		log.Printf("Invalid user ID format: %v", err)
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -64,83 +72,307 @@ func (h *VoteHandler) CastVote(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) != 4 || parts[3] != "vote" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
 		return
 	}
 
 	sessionIDStr := parts[2]
 	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		http.Error(w, "Invalid session ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
 		return
 	}
 
 	// Parse request body
 	var req VoteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeStrictJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
 	// Validate vote value
-	if req.Vote != "yes" && req.Vote != "no" && req.Vote != "maybe" {
-		http.Error(w, "Vote must be 'yes', 'no', or 'maybe'", http.StatusBadRequest)
+	if !database.IsValidVote(req.Vote) {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidVote, "Vote must be 'yes', 'no', or 'maybe'")
 		return
 	}
 
 	// Parse media ID
 	mediaID, err := uuid.Parse(req.MediaID)
 	if err != nil {
-		http.Error(w, "Invalid media ID format", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid media ID format")
+		return
+	}
+
+	if !isValidSeasonNumber(req.SeasonNumber) {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid season number")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Check if session exists and is active
 	session, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error getting session: %v", err)
-		http.Error(w, "Failed to get session", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
 		return
 	}
 
 	if session == nil {
-		http.Error(w, "Session not found", http.StatusNotFound)
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
 		return
 	}
 
 	if session.Status != "active" {
-		http.Error(w, "Session is not active", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Session is not active")
+		return
+	}
+
+	if database.IsExpired(session) {
+		writeJSONError(w, http.StatusGone, errCodeSessionExpired, "Voting for this session has ended")
 		return
 	}
 
-	// Cast the vote
-	if err := h.voteRepo.CastVote(ctx, sessionID, userID, mediaID, req.Vote); err != nil {
+	isParticipant, err := h.canVote(ctx, sessionID, userID)
+	if err != nil {
+		log.Printf("Error checking session participant: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check session access")
+		return
+	}
+
+	if !isParticipant {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Not a participant in this session")
+		return
+	}
+
+	// Cast the vote and learn whether it created a match; a match already claimed by another vote is not reported again
+	isMatch, err := h.voteRepo.CastVoteAndCheckMatch(ctx, sessionID, userID, mediaID, req.Vote, req.SeasonNumber)
+	if err != nil {
 		log.Printf("Error casting vote: %v", err)
-		http.Error(w, "Failed to cast vote", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to cast vote")
 		return
 	}
 
-	// Check if this creates a match (optimization)
-	isMatch := false
-	if req.Vote == "yes" {
-		isMatch, err = h.voteRepo.CheckMatch(ctx, sessionID, mediaID)
+	// Only matches need the media item, which is returned when this vote created the match.
+	// The claim already decided that, so concurrent voters may have pushed the yes count past 2
+	var matchedMedia *database.MediaItem
+	if isMatch {
+		matchedMedia, err = h.mediaRepo.GetMediaByID(ctx, mediaID)
 		if err != nil {
-			log.Printf("Warning: Failed to check match: %v", err)
+			log.Printf("Warning: Failed to get matched media: %v", err)
 			// Don't fail the request, just log the error
 		}
 	}
 
+	h.hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: sessionID, UserID: userID, MediaID: mediaID, Vote: req.Vote})
+	if isMatch {
+		h.hub.Publish(RoomEvent{Type: RoomEventMatch, RoomID: sessionID, UserID: userID, MediaID: mediaID, Media: matchedMedia})
+	}
+
 	response := VoteResponse{
-		Success: true,
-		IsMatch: isMatch,
+		Success:      true,
+		IsMatch:      isMatch,
+		MatchedMedia: matchedMedia,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// canVote reports whether the caller may vote in the session
+// Guests may only vote in the session their token was minted for; everyone else must be a participant
+func (h *VoteHandler) canVote(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	if guestSessionID, ok := middleware.GetGuestSessionID(ctx); ok {
+		return guestSessionID == sessionID.String(), nil
 	}
+	return h.sessionRepo.IsSessionParticipant(ctx, sessionID, userID)
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+// isValidSeasonNumber reports whether an optional season number is absent or non-negative
+// Season 0 is TMDB's "specials" season
+func isValidSeasonNumber(season *int) bool {
+	return season == nil || *season >= 0
+}
+
+// maxBatchVotes caps how many votes one CastVotes request may carry
+const maxBatchVotes = 100
+
+// BatchVoteResponse represents the response after casting a batch of votes
+type BatchVoteResponse struct {
+	Success bool     `json:"success"`
+	Count   int      `json:"count"`
+	Matches []string `json:"matches"`
+}
+
+// CastVotes handles POST /api/sessions/{id}/votes
+// The body is a JSON array of {media_id, vote}; the whole batch is rejected if any entry is invalid
+func (h *VoteHandler) CastVotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
+
+	// Get user ID from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "User ID not found")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("Invalid user ID format: %v", err)
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Extract session ID from URL path
+	// Expected format: /api/sessions/{id}/votes
+	path := r.URL.Path
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "votes" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid URL format")
+		return
+	}
+
+	sessionID, err := uuid.Parse(parts[2])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid session ID format")
+		return
+	}
+
+	// Parse request body
+	var reqs []VoteRequest
+	if err := decodeStrictJSONBody(w, r, &reqs); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(reqs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "At least one vote is required")
+		return
+	}
+
+	if len(reqs) > maxBatchVotes {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Too many votes")
+		return
+	}
+
+	// Validate every entry before touching the database
+	votes := make([]database.VoteInput, 0, len(reqs))
+	for _, req := range reqs {
+		if !database.IsValidVote(req.Vote) {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidVote, "Vote must be 'yes', 'no', or 'maybe'")
+			return
+		}
+
+		mediaID, err := uuid.Parse(req.MediaID)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid media ID format")
+			return
+		}
+
+		if !isValidSeasonNumber(req.SeasonNumber) {
+			writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid season number")
+			return
+		}
+
+		votes = append(votes, database.VoteInput{MediaID: mediaID, SeasonNumber: req.SeasonNumber, Vote: req.Vote})
+	}
+
+	ctx := r.Context()
+
+	// Check if session exists and is active
+	session, err := h.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting session: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get session")
+		return
+	}
+
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "Session not found")
+		return
+	}
+
+	if session.Status != "active" {
+		writeJSONError(w, http.StatusBadRequest, errCodeBadRequest, "Session is not active")
+		return
+	}
+
+	if database.IsExpired(session) {
+		writeJSONError(w, http.StatusGone, errCodeSessionExpired, "Voting for this session has ended")
+		return
+	}
+
+	isParticipant, err := h.canVote(ctx, sessionID, userID)
+	if err != nil {
+		log.Printf("Error checking session participant: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to check session access")
+		return
+	}
+
+	if !isParticipant {
+		writeJSONError(w, http.StatusForbidden, errCodeForbidden, "Not a participant in this session")
+		return
+	}
+
+	if err := h.voteRepo.CastVotes(ctx, sessionID, userID, votes); err != nil {
+		if errors.Is(err, database.ErrInvalidVote) {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidVote, "Vote must be 'yes', 'no', or 'maybe'")
+			return
+		}
+		log.Printf("Error casting votes: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, errCodeInternal, "Failed to cast votes")
+		return
+	}
+
+	for _, v := range votes {
+		h.hub.Publish(RoomEvent{Type: RoomEventVote, RoomID: sessionID, UserID: userID, MediaID: v.MediaID, Vote: v.Vote})
+	}
+
+	// Claim a match for each media the batch leaves a yes vote on; a later entry for the same media and season wins.
+	// As with single votes, a match already claimed by another vote is not reported again
+	type voteTarget struct {
+		mediaID uuid.UUID
+		season  int
+	}
+	targetOf := func(v database.VoteInput) voteTarget {
+		if v.SeasonNumber == nil {
+			return voteTarget{mediaID: v.MediaID, season: -1}
+		}
+		return voteTarget{mediaID: v.MediaID, season: *v.SeasonNumber}
+	}
+
+	finalVotes := make(map[voteTarget]string, len(votes))
+	for _, v := range votes {
+		finalVotes[targetOf(v)] = v.Vote
+	}
+
+	matches := []string{}
+	claimed := make(map[uuid.UUID]bool, len(votes))
+	for _, v := range votes {
+		if finalVotes[targetOf(v)] != "yes" || claimed[v.MediaID] {
+			continue
+		}
+		claimed[v.MediaID] = true
+
+		isMatch, err := h.voteRepo.ClaimMatch(ctx, sessionID, v.MediaID)
+		if err != nil {
+			log.Printf("Warning: Failed to claim match: %v", err)
+			// Don't fail the request, just log the error
+			continue
+		}
+		if isMatch {
+			matches = append(matches, v.MediaID.String())
+			h.hub.Publish(RoomEvent{Type: RoomEventMatch, RoomID: sessionID, UserID: userID, MediaID: v.MediaID})
+		}
+	}
+
+	response := BatchVoteResponse{
+		Success: true,
+		Count:   len(votes),
+		Matches: matches,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
 }
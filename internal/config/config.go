@@ -1,34 +1,161 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	TMDBAPIKey        string
+	TMDBBaseURL       string
 	OpenAIAPIKey      string
+	OpenAIModel       string
 	SupabaseURL       string
 	SupabaseKey       string
 	SupabaseJWTSecret string
 	DatabaseURL       string
 	Port              string
+	AdminUserIDs      []string
+	LogLevel          string
+	// CORSAllowedOrigins is empty in local dev, which allows every origin
+	CORSAllowedOrigins []string
+	// RecommendationMinLikes is how many liked movies a session needs before AI recommendations
+	RecommendationMinLikes int
+	// TMDBImageBaseURL overrides the image base from TMDB's configuration endpoint when set
+	TMDBImageBaseURL string
+	// TMDBTimeout and OpenAITimeout bound each outbound request to those APIs
+	TMDBTimeout   time.Duration
+	OpenAITimeout time.Duration
+	// TMDBSearchCacheTTL and TMDBSearchCacheSize bound the in-memory movie search cache; a size of 0 disables it
+	TMDBSearchCacheTTL  time.Duration
+	TMDBSearchCacheSize int
+	// MetricsPort serves /metrics on a separate listener when set, keeping it off the public port
+	MetricsPort string
+	// UserSearchByEmail lets user search find an account by its exact email; off unless a deployment opts in
+	UserSearchByEmail bool
+	// RoomMaxInitialMembers caps how many members a room can be created with
+	RoomMaxInitialMembers int
+	// PrecacheInterval is how often now-playing and trending movies are cached in the background
+	PrecacheInterval time.Duration
+	// MediaCleanupInterval is how often cached media nobody voted on is purged, once older than MediaCleanupMaxAge
+	MediaCleanupInterval time.Duration
+	MediaCleanupMaxAge   time.Duration
+	// JWTLeeway is how much clock skew is tolerated when checking token expiry
+	JWTLeeway time.Duration
+	// JWTAudience turns on aud and iss checks when set; Supabase issues tokens with the "authenticated" audience
+	JWTAudience string
 }
 
 func LoadConfig() *Config {
 	return &Config{
-		TMDBAPIKey:        getEnv("TMDB_API_KEY", ""),
-		OpenAIAPIKey:      getEnv("OPENAI_API_KEY", ""),
-		SupabaseURL:       getEnv("SUPABASE_URL", ""),
-		SupabaseKey:       getEnv("SUPABASE_ANON_KEY", ""),
-		SupabaseJWTSecret: getEnv("SUPABASE_JWT_SECRET", ""),
-		DatabaseURL:       getEnv("DATABASE_URL", ""),
-		Port:              getEnv("PORT", "8080"),
+		TMDBAPIKey:             getEnv("TMDB_API_KEY", ""),
+		TMDBBaseURL:            getEnv("TMDB_BASE_URL", "https://api.themoviedb.org/3"),
+		TMDBImageBaseURL:       getEnv("TMDB_IMAGE_BASE_URL", ""),
+		OpenAIAPIKey:           getEnv("OPENAI_API_KEY", ""),
+		OpenAIModel:            getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+		SupabaseURL:            getEnv("SUPABASE_URL", ""),
+		SupabaseKey:            getEnv("SUPABASE_ANON_KEY", ""),
+		SupabaseJWTSecret:      getEnv("SUPABASE_JWT_SECRET", ""),
+		DatabaseURL:            getEnv("DATABASE_URL", ""),
+		Port:                   getEnv("PORT", "8080"),
+		AdminUserIDs:           getEnvList("ADMIN_USER_IDS"),
+		LogLevel:               getEnv("LOG_LEVEL", "info"),
+		CORSAllowedOrigins:     getEnvList("CORS_ALLOWED_ORIGINS"),
+		RecommendationMinLikes: getEnvInt("RECOMMENDATION_MIN_LIKES", 3),
+		MetricsPort:            getEnv("METRICS_PORT", ""),
+		TMDBTimeout:            getEnvSeconds("TMDB_TIMEOUT_SECONDS", 10*time.Second),
+		OpenAITimeout:          getEnvSeconds("OPENAI_TIMEOUT_SECONDS", 30*time.Second),
+		TMDBSearchCacheTTL:     getEnvSeconds("TMDB_SEARCH_CACHE_TTL_SECONDS", time.Hour),
+		TMDBSearchCacheSize:    getEnvInt("TMDB_SEARCH_CACHE_SIZE", 500),
+		UserSearchByEmail:      getEnvBool("USER_SEARCH_BY_EMAIL", false),
+		RoomMaxInitialMembers:  getEnvInt("ROOM_MAX_INITIAL_MEMBERS", 50),
+		PrecacheInterval:       getEnvSeconds("PRECACHE_INTERVAL_SECONDS", 6*time.Hour),
+		MediaCleanupInterval:   getEnvSeconds("MEDIA_CLEANUP_INTERVAL_SECONDS", 24*time.Hour),
+		MediaCleanupMaxAge:     getEnvSeconds("MEDIA_CLEANUP_MAX_AGE_SECONDS", 30*24*time.Hour),
+		JWTLeeway:              getEnvLeeway("JWT_LEEWAY_SECONDS", 30*time.Second),
+		JWTAudience:            getEnv("JWT_AUDIENCE", ""),
 	}
 }
 
+// Validate checks that every required setting is present
+// The OpenAI key is optional; without it recommendations return no results
+func (c *Config) Validate() error {
+	required := []struct {
+		envVar string
+		value  string
+	}{
+		{"TMDB_API_KEY", c.TMDBAPIKey},
+		{"DATABASE_URL", c.DatabaseURL},
+		{"SUPABASE_JWT_SECRET", c.SupabaseJWTSecret},
+	}
+
+	var missing []string
+	for _, setting := range required {
+		if strings.TrimSpace(setting.value) == "" {
+			missing = append(missing, setting.envVar)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+// getEnvList reads a comma-separated environment variable, dropping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvInt reads an integer environment variable, using fallback when unset or malformed
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(getEnv(key, "")))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvBool reads a boolean environment variable, using fallback when unset or malformed
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(getEnv(key, "")))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvSeconds reads a whole number of seconds, using fallback when unset, malformed, or not positive
+func getEnvSeconds(key string, fallback time.Duration) time.Duration {
+	seconds := getEnvInt(key, 0)
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getEnvLeeway reads a whole number of seconds where 0 is meaningful, using fallback when unset, malformed, or negative
+func getEnvLeeway(key string, fallback time.Duration) time.Duration {
+	seconds := getEnvInt(key, -1)
+	if seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,332 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		TMDBAPIKey:        "tmdb-key",
+		OpenAIAPIKey:      "openai-key",
+		SupabaseJWTSecret: "jwt-secret",
+		DatabaseURL:       "postgres://localhost/test",
+		Port:              "8080",
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("passes when all required values are present", func(t *testing.T) {
+		if err := validConfig().Validate(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("allows a missing OpenAI key", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.OpenAIAPIKey = ""
+
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Expected no error without OpenAI key, got %v", err)
+		}
+	})
+
+	t.Run("reports a single missing value", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DatabaseURL = ""
+
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("Expected an error for missing DATABASE_URL")
+		}
+		if !strings.Contains(err.Error(), "DATABASE_URL") {
+			t.Errorf("Expected error to name DATABASE_URL, got %v", err)
+		}
+		if strings.Contains(err.Error(), "TMDB_API_KEY") {
+			t.Errorf("Expected error to only name missing values, got %v", err)
+		}
+	})
+
+	t.Run("reports every missing value", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.TMDBAPIKey = ""
+		cfg.SupabaseJWTSecret = "  "
+		cfg.DatabaseURL = ""
+
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("Expected an error for missing values")
+		}
+		for _, name := range []string{"TMDB_API_KEY", "DATABASE_URL", "SUPABASE_JWT_SECRET"} {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected error to name %s, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestLoadConfig_TMDBBaseURL(t *testing.T) {
+	t.Run("defaults to the public TMDB API", func(t *testing.T) {
+		t.Setenv("TMDB_BASE_URL", "")
+		os.Unsetenv("TMDB_BASE_URL")
+
+		if got := LoadConfig().TMDBBaseURL; got != "https://api.themoviedb.org/3" {
+			t.Errorf("Expected default TMDB base URL, got '%s'", got)
+		}
+	})
+
+	t.Run("reads TMDB_BASE_URL", func(t *testing.T) {
+		t.Setenv("TMDB_BASE_URL", "https://tmdb-proxy.internal/3")
+
+		if got := LoadConfig().TMDBBaseURL; got != "https://tmdb-proxy.internal/3" {
+			t.Errorf("Expected custom TMDB base URL, got '%s'", got)
+		}
+	})
+}
+
+func TestLoadConfig_TMDBImageBaseURL(t *testing.T) {
+	t.Run("defaults to empty so TMDB's configuration is used", func(t *testing.T) {
+		t.Setenv("TMDB_IMAGE_BASE_URL", "")
+		os.Unsetenv("TMDB_IMAGE_BASE_URL")
+
+		if got := LoadConfig().TMDBImageBaseURL; got != "" {
+			t.Errorf("Expected no image base URL override, got '%s'", got)
+		}
+	})
+
+	t.Run("reads TMDB_IMAGE_BASE_URL", func(t *testing.T) {
+		t.Setenv("TMDB_IMAGE_BASE_URL", "https://cdn.example/t/p/")
+
+		if got := LoadConfig().TMDBImageBaseURL; got != "https://cdn.example/t/p/" {
+			t.Errorf("Expected custom image base URL, got '%s'", got)
+		}
+	})
+}
+
+func TestLoadConfig_CORSAllowedOrigins(t *testing.T) {
+	t.Run("defaults to an empty allowlist", func(t *testing.T) {
+		t.Setenv("CORS_ALLOWED_ORIGINS", "")
+
+		if got := LoadConfig().CORSAllowedOrigins; len(got) != 0 {
+			t.Errorf("Expected no allowed origins, got %v", got)
+		}
+	})
+
+	t.Run("splits CORS_ALLOWED_ORIGINS", func(t *testing.T) {
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://admin.example.com,")
+
+		got := LoadConfig().CORSAllowedOrigins
+		if len(got) != 2 || got[0] != "https://app.example.com" || got[1] != "https://admin.example.com" {
+			t.Errorf("Expected two trimmed origins, got %v", got)
+		}
+	})
+}
+
+func TestLoadConfig_RecommendationMinLikes(t *testing.T) {
+	t.Run("defaults to three", func(t *testing.T) {
+		t.Setenv("RECOMMENDATION_MIN_LIKES", "")
+
+		if got := LoadConfig().RecommendationMinLikes; got != 3 {
+			t.Errorf("Expected default of 3, got %d", got)
+		}
+	})
+
+	t.Run("reads RECOMMENDATION_MIN_LIKES", func(t *testing.T) {
+		t.Setenv("RECOMMENDATION_MIN_LIKES", "5")
+
+		if got := LoadConfig().RecommendationMinLikes; got != 5 {
+			t.Errorf("Expected 5, got %d", got)
+		}
+	})
+
+	t.Run("falls back on a malformed value", func(t *testing.T) {
+		t.Setenv("RECOMMENDATION_MIN_LIKES", "lots")
+
+		if got := LoadConfig().RecommendationMinLikes; got != 3 {
+			t.Errorf("Expected default of 3, got %d", got)
+		}
+	})
+}
+
+func TestLoadConfig_Timeouts(t *testing.T) {
+	t.Run("defaults to 10s for TMDB and 30s for OpenAI", func(t *testing.T) {
+		t.Setenv("TMDB_TIMEOUT_SECONDS", "")
+		t.Setenv("OPENAI_TIMEOUT_SECONDS", "")
+
+		cfg := LoadConfig()
+		if cfg.TMDBTimeout != 10*time.Second || cfg.OpenAITimeout != 30*time.Second {
+			t.Errorf("Expected 10s and 30s, got %s and %s", cfg.TMDBTimeout, cfg.OpenAITimeout)
+		}
+	})
+
+	t.Run("reads the configured seconds", func(t *testing.T) {
+		t.Setenv("TMDB_TIMEOUT_SECONDS", "3")
+		t.Setenv("OPENAI_TIMEOUT_SECONDS", "45")
+
+		cfg := LoadConfig()
+		if cfg.TMDBTimeout != 3*time.Second || cfg.OpenAITimeout != 45*time.Second {
+			t.Errorf("Expected 3s and 45s, got %s and %s", cfg.TMDBTimeout, cfg.OpenAITimeout)
+		}
+	})
+
+	t.Run("falls back on zero or malformed values", func(t *testing.T) {
+		t.Setenv("TMDB_TIMEOUT_SECONDS", "0")
+		t.Setenv("OPENAI_TIMEOUT_SECONDS", "soon")
+
+		cfg := LoadConfig()
+		if cfg.TMDBTimeout != 10*time.Second || cfg.OpenAITimeout != 30*time.Second {
+			t.Errorf("Expected the defaults, got %s and %s", cfg.TMDBTimeout, cfg.OpenAITimeout)
+		}
+	})
+}
+
+func TestLoadConfig_UserSearchByEmail(t *testing.T) {
+	t.Run("defaults to disabled", func(t *testing.T) {
+		t.Setenv("USER_SEARCH_BY_EMAIL", "")
+
+		if LoadConfig().UserSearchByEmail {
+			t.Error("Expected email search to be disabled by default")
+		}
+	})
+
+	t.Run("can be enabled", func(t *testing.T) {
+		t.Setenv("USER_SEARCH_BY_EMAIL", "true")
+
+		if !LoadConfig().UserSearchByEmail {
+			t.Error("Expected email search to be enabled")
+		}
+	})
+}
+
+func TestLoadConfig_RoomMaxInitialMembers(t *testing.T) {
+	t.Run("defaults to fifty", func(t *testing.T) {
+		t.Setenv("ROOM_MAX_INITIAL_MEMBERS", "")
+
+		if got := LoadConfig().RoomMaxInitialMembers; got != 50 {
+			t.Errorf("Expected default of 50, got %d", got)
+		}
+	})
+
+	t.Run("reads ROOM_MAX_INITIAL_MEMBERS", func(t *testing.T) {
+		t.Setenv("ROOM_MAX_INITIAL_MEMBERS", "10")
+
+		if got := LoadConfig().RoomMaxInitialMembers; got != 10 {
+			t.Errorf("Expected 10, got %d", got)
+		}
+	})
+}
+
+func TestLoadConfig_PrecacheInterval(t *testing.T) {
+	t.Run("defaults to six hours", func(t *testing.T) {
+		t.Setenv("PRECACHE_INTERVAL_SECONDS", "")
+
+		if got := LoadConfig().PrecacheInterval; got != 6*time.Hour {
+			t.Errorf("Expected default of 6h, got %s", got)
+		}
+	})
+
+	t.Run("reads PRECACHE_INTERVAL_SECONDS", func(t *testing.T) {
+		t.Setenv("PRECACHE_INTERVAL_SECONDS", "600")
+
+		if got := LoadConfig().PrecacheInterval; got != 10*time.Minute {
+			t.Errorf("Expected 10m, got %s", got)
+		}
+	})
+}
+
+func TestLoadConfig_JWTLeeway(t *testing.T) {
+	t.Run("defaults to 30 seconds", func(t *testing.T) {
+		t.Setenv("JWT_LEEWAY_SECONDS", "")
+
+		if got := LoadConfig().JWTLeeway; got != 30*time.Second {
+			t.Errorf("Expected default of 30s, got %s", got)
+		}
+	})
+
+	t.Run("allows zero to disable the leeway", func(t *testing.T) {
+		t.Setenv("JWT_LEEWAY_SECONDS", "0")
+
+		if got := LoadConfig().JWTLeeway; got != 0 {
+			t.Errorf("Expected 0, got %s", got)
+		}
+	})
+
+	t.Run("falls back on negative values", func(t *testing.T) {
+		t.Setenv("JWT_LEEWAY_SECONDS", "-5")
+
+		if got := LoadConfig().JWTLeeway; got != 30*time.Second {
+			t.Errorf("Expected default of 30s, got %s", got)
+		}
+	})
+}
+
+func TestLoadConfig_JWTAudience(t *testing.T) {
+	t.Run("is off by default", func(t *testing.T) {
+		t.Setenv("JWT_AUDIENCE", "")
+		os.Unsetenv("JWT_AUDIENCE")
+
+		if got := LoadConfig().JWTAudience; got != "" {
+			t.Errorf("Expected no audience, got '%s'", got)
+		}
+	})
+
+	t.Run("reads JWT_AUDIENCE", func(t *testing.T) {
+		t.Setenv("JWT_AUDIENCE", "authenticated")
+
+		if got := LoadConfig().JWTAudience; got != "authenticated" {
+			t.Errorf("Expected 'authenticated', got '%s'", got)
+		}
+	})
+}
+
+func TestLoadConfig_MediaCleanup(t *testing.T) {
+	t.Run("defaults to a daily purge of media older than 30 days", func(t *testing.T) {
+		t.Setenv("MEDIA_CLEANUP_INTERVAL_SECONDS", "")
+		t.Setenv("MEDIA_CLEANUP_MAX_AGE_SECONDS", "")
+
+		cfg := LoadConfig()
+		if cfg.MediaCleanupInterval != 24*time.Hour || cfg.MediaCleanupMaxAge != 30*24*time.Hour {
+			t.Errorf("Expected 24h and 720h, got %s and %s", cfg.MediaCleanupInterval, cfg.MediaCleanupMaxAge)
+		}
+	})
+
+	t.Run("reads the configured seconds", func(t *testing.T) {
+		t.Setenv("MEDIA_CLEANUP_INTERVAL_SECONDS", "3600")
+		t.Setenv("MEDIA_CLEANUP_MAX_AGE_SECONDS", "86400")
+
+		cfg := LoadConfig()
+		if cfg.MediaCleanupInterval != time.Hour || cfg.MediaCleanupMaxAge != 24*time.Hour {
+			t.Errorf("Expected 1h and 24h, got %s and %s", cfg.MediaCleanupInterval, cfg.MediaCleanupMaxAge)
+		}
+	})
+}
+
+func TestLoadConfig_TMDBSearchCache(t *testing.T) {
+	t.Run("defaults to an hour and 500 entries", func(t *testing.T) {
+		t.Setenv("TMDB_SEARCH_CACHE_TTL_SECONDS", "")
+		t.Setenv("TMDB_SEARCH_CACHE_SIZE", "")
+
+		cfg := LoadConfig()
+		if cfg.TMDBSearchCacheTTL != time.Hour {
+			t.Errorf("Expected default TTL of 1h, got %s", cfg.TMDBSearchCacheTTL)
+		}
+		if cfg.TMDBSearchCacheSize != 500 {
+			t.Errorf("Expected default size of 500, got %d", cfg.TMDBSearchCacheSize)
+		}
+	})
+
+	t.Run("reads the TTL and size", func(t *testing.T) {
+		t.Setenv("TMDB_SEARCH_CACHE_TTL_SECONDS", "120")
+		t.Setenv("TMDB_SEARCH_CACHE_SIZE", "0")
+
+		cfg := LoadConfig()
+		if cfg.TMDBSearchCacheTTL != 2*time.Minute {
+			t.Errorf("Expected TTL of 2m, got %s", cfg.TMDBSearchCacheTTL)
+		}
+		if cfg.TMDBSearchCacheSize != 0 {
+			t.Errorf("Expected size of 0, got %d", cfg.TMDBSearchCacheSize)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultGuestTokenTTL is how long a guest voting link stays valid when no TTL is configured
+const DefaultGuestTokenTTL = 24 * time.Hour
+
+// GuestToken grants a guest voter the right to vote in one session
+// Token is only populated when the link is minted; the database keeps its hash
+type GuestToken struct {
+	Token     string    `json:"token,omitempty"`
+	SessionID uuid.UUID `json:"session_id"`
+	GuestID   uuid.UUID `json:"guest_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GuestTokenRepository handles guest voting link database operations
+type GuestTokenRepository struct {
+	db *sql.DB
+	// TTL is how long a minted token is accepted
+	TTL time.Duration
+}
+
+// NewGuestTokenRepository creates a new guest token repository
+func NewGuestTokenRepository(db *sql.DB) *GuestTokenRepository {
+	return &GuestTokenRepository{db: db, TTL: DefaultGuestTokenTTL}
+}
+
+// CreateGuestToken mints a token for a session along with the guest voter that votes with it
+// Guests get no account, profile or participant row; the token alone scopes them to the session
+func (r *GuestTokenRepository) CreateGuestToken(ctx context.Context, sessionID, createdBy uuid.UUID) (*GuestToken, error) {
+	token, err := newGuestToken()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	guestQuery := `
+		INSERT INTO guest_voters (session_id)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	var guestID uuid.UUID
+	if err = tx.QueryRowContext(ctx, guestQuery, sessionID).Scan(&guestID); err != nil {
+		return nil, fmt.Errorf("failed to create guest voter: %w", err)
+	}
+
+	tokenQuery := `
+		INSERT INTO session_guest_tokens (token_hash, session_id, guest_id, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		RETURNING expires_at
+	`
+
+	guest := GuestToken{Token: token, SessionID: sessionID, GuestID: guestID}
+	err = tx.QueryRowContext(ctx, tokenQuery, hashGuestToken(token), sessionID, guestID, createdBy, r.TTL.Seconds()).Scan(&guest.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create guest token: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &guest, nil
+}
+
+// ResolveGuestToken looks up the guest and session a token grants
+// Returns nil if the token is unknown or has expired
+func (r *GuestTokenRepository) ResolveGuestToken(ctx context.Context, token string) (*GuestToken, error) {
+	query := `
+		SELECT session_id, guest_id, expires_at
+		FROM session_guest_tokens
+		WHERE token_hash = $1 AND expires_at > NOW()
+	`
+
+	var guest GuestToken
+	err := r.db.QueryRowContext(ctx, query, hashGuestToken(token)).Scan(
+		&guest.SessionID,
+		&guest.GuestID,
+		&guest.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve guest token: %w", err)
+	}
+
+	return &guest, nil
+}
+
+// newGuestToken returns a random URL-safe token
+func newGuestToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate guest token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashGuestToken returns the stored form of a token, so a database leak does not expose live links
+func hashGuestToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
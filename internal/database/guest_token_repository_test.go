@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+)
+
+func TestGuestTokenRepository(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewGuestTokenRepository(testDB.DB)
+	sessionRepo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	hostID := uuid.New()
+	testDB.SeedProfile(t, hostID, "guest_token_host")
+
+	sessionID := testDB.SeedWatchSession(t, hostID, "Guest Night", false)
+
+	guest, err := repo.CreateGuestToken(ctx, sessionID, hostID)
+	if err != nil {
+		t.Fatalf("CreateGuestToken failed: %v", err)
+	}
+
+	t.Run("mints a token for a new guest voter without an account", func(t *testing.T) {
+		if guest.Token == "" {
+			t.Fatal("Expected a token")
+		}
+		if guest.ExpiresAt.Before(time.Now().Add(DefaultGuestTokenTTL - time.Minute)) {
+			t.Errorf("Expected expiry about %v from now, got %v", DefaultGuestTokenTTL, guest.ExpiresAt)
+		}
+
+		var guestSessionID uuid.UUID
+		if err := testDB.DB.QueryRow(`SELECT session_id FROM guest_voters WHERE id = $1`, guest.GuestID).Scan(&guestSessionID); err != nil {
+			t.Fatalf("Expected a guest voter row: %v", err)
+		}
+		if guestSessionID != sessionID {
+			t.Errorf("Expected the guest to belong to session %s, got %s", sessionID, guestSessionID)
+		}
+
+		var accounts int
+		if err := testDB.DB.QueryRow(`SELECT COUNT(*) FROM auth.users WHERE id = $1`, guest.GuestID).Scan(&accounts); err != nil {
+			t.Fatalf("Failed to count accounts: %v", err)
+		}
+		if accounts != 0 {
+			t.Error("Expected no auth user for the guest")
+		}
+
+		isParticipant, err := sessionRepo.IsSessionParticipant(ctx, sessionID, guest.GuestID)
+		if err != nil {
+			t.Fatalf("IsSessionParticipant failed: %v", err)
+		}
+		if isParticipant {
+			t.Error("Expected the guest not to be added as a session participant")
+		}
+	})
+
+	t.Run("resolves a valid token", func(t *testing.T) {
+		resolved, err := repo.ResolveGuestToken(ctx, guest.Token)
+		if err != nil {
+			t.Fatalf("ResolveGuestToken failed: %v", err)
+		}
+		if resolved == nil {
+			t.Fatal("Expected the token to resolve")
+		}
+		if resolved.GuestID != guest.GuestID || resolved.SessionID != sessionID {
+			t.Errorf("Expected guest %s in session %s, got %+v", guest.GuestID, sessionID, resolved)
+		}
+	})
+
+	t.Run("does not resolve an unknown token", func(t *testing.T) {
+		resolved, err := repo.ResolveGuestToken(ctx, "unknown")
+		if err != nil {
+			t.Fatalf("ResolveGuestToken failed: %v", err)
+		}
+		if resolved != nil {
+			t.Errorf("Expected nil, got %+v", resolved)
+		}
+	})
+
+	t.Run("does not resolve an expired token", func(t *testing.T) {
+		if _, err := testDB.DB.Exec(`UPDATE session_guest_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE guest_id = $1`, guest.GuestID); err != nil {
+			t.Fatalf("Failed to expire token: %v", err)
+		}
+
+		resolved, err := repo.ResolveGuestToken(ctx, guest.Token)
+		if err != nil {
+			t.Fatalf("ResolveGuestToken failed: %v", err)
+		}
+		if resolved != nil {
+			t.Errorf("Expected nil for an expired token, got %+v", resolved)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Idempotency scopes keep keys sent to different creation endpoints apart
+const (
+	IdempotencyScopeRoom    = "room"
+	IdempotencyScopeSession = "session"
+)
+
+// ErrIdempotencyKeyUsed is returned by a create when another request already holds its idempotency key
+// The create is rolled back; the caller should replay what the other request created
+var ErrIdempotencyKeyUsed = errors.New("idempotency key already used")
+
+// idempotentCreateStatus is the status code recorded for keys reserved by a create and replayed on retries
+const idempotentCreateStatus = http.StatusCreated
+
+// IdempotencyKey records what the first request carrying a key created
+type IdempotencyKey struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Scope      string    `json:"scope"`
+	Key        string    `json:"key"`
+	ResourceID uuid.UUID `json:"resource_id"`
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IdempotencyRepository handles idempotency key database operations
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// GetIdempotencyKey retrieves the record for a user's key in a scope
+// Returns nil if the key has not been used, or its resource has since been deleted
+func (r *IdempotencyRepository) GetIdempotencyKey(ctx context.Context, userID uuid.UUID, scope, key string) (*IdempotencyKey, error) {
+	query := `
+		SELECT user_id, scope, key, resource_id, status_code, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND scope = $2 AND key = $3
+	`
+
+	var record IdempotencyKey
+	err := r.db.QueryRowContext(ctx, query, userID, scope, key).Scan(
+		&record.UserID,
+		&record.Scope,
+		&record.Key,
+		&record.ResourceID,
+		&record.StatusCode,
+		&record.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// reserveIdempotencyKey claims key for the resource being created within tx, so the key is only taken if the create commits
+// A concurrent request holding the key blocks this insert until it finishes; ErrIdempotencyKeyUsed is returned
+// if it committed, and the caller must roll back. An empty key reserves nothing
+func reserveIdempotencyKey(ctx context.Context, tx *sql.Tx, userID uuid.UUID, scope, key string, resourceID uuid.UUID) error {
+	if key == "" {
+		return nil
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, scope, key, resource_id, status_code)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, scope, key) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, userID, scope, key, resourceID, idempotentCreateStatus)
+	if err != nil {
+		return fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check idempotency key reservation: %w", err)
+	}
+	if rows == 0 {
+		return ErrIdempotencyKeyUsed
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+)
+
+func TestIdempotencyRepository(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewIdempotencyRepository(testDB.DB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "idempotent_user")
+
+	sessionID := testDB.SeedWatchSession(t, userID, "Idempotent Room", false)
+
+	t.Run("returns nil for an unused key", func(t *testing.T) {
+		record, err := repo.GetIdempotencyKey(ctx, userID, IdempotencyScopeRoom, "unused")
+		if err != nil {
+			t.Fatalf("GetIdempotencyKey failed: %v", err)
+		}
+		if record != nil {
+			t.Errorf("Expected nil for an unused key, got %+v", record)
+		}
+	})
+
+	t.Run("returns the reserved resource and status", func(t *testing.T) {
+		tx, err := testDB.DB.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+		defer tx.Rollback()
+
+		if err := reserveIdempotencyKey(ctx, tx, userID, IdempotencyScopeRoom, "key-1", sessionID); err != nil {
+			t.Fatalf("reserveIdempotencyKey failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+
+		record, err := repo.GetIdempotencyKey(ctx, userID, IdempotencyScopeRoom, "key-1")
+		if err != nil {
+			t.Fatalf("GetIdempotencyKey failed: %v", err)
+		}
+		if record == nil {
+			t.Fatal("Expected a saved record")
+		}
+		if record.ResourceID != sessionID || record.StatusCode != http.StatusCreated {
+			t.Errorf("Expected %s with status 201, got %s with status %d", sessionID, record.ResourceID, record.StatusCode)
+		}
+	})
+
+	t.Run("a used key cannot be reserved again", func(t *testing.T) {
+		tx, err := testDB.DB.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+		defer tx.Rollback()
+
+		err = reserveIdempotencyKey(ctx, tx, userID, IdempotencyScopeRoom, "key-1", sessionID)
+		if !errors.Is(err, ErrIdempotencyKeyUsed) {
+			t.Errorf("Expected ErrIdempotencyKeyUsed, got %v", err)
+		}
+	})
+
+	t.Run("keeps scopes apart", func(t *testing.T) {
+		record, err := repo.GetIdempotencyKey(ctx, userID, IdempotencyScopeSession, "key-1")
+		if err != nil {
+			t.Fatalf("GetIdempotencyKey failed: %v", err)
+		}
+		if record != nil {
+			t.Errorf("Expected a room key not to match a session lookup, got %+v", record)
+		}
+	})
+
+	t.Run("forgets keys whose resource was deleted", func(t *testing.T) {
+		if _, err := testDB.DB.Exec("DELETE FROM watch_sessions WHERE id = $1", sessionID); err != nil {
+			t.Fatalf("Failed to delete session: %v", err)
+		}
+
+		record, err := repo.GetIdempotencyKey(ctx, userID, IdempotencyScopeRoom, "key-1")
+		if err != nil {
+			t.Fatalf("GetIdempotencyKey failed: %v", err)
+		}
+		if record != nil {
+			t.Errorf("Expected the key to be removed with its resource, got %+v", record)
+		}
+	})
+}
+
+func TestIdempotencyRepository_ConcurrentCreates(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	roomRepo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "concurrent_retrier")
+
+	const attempts = 5
+
+	rooms := make([]*Room, attempts)
+	errs := make([]error, attempts)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			rooms[i], errs[i] = roomRepo.CreateRoomWithIdempotencyKey(ctx, userID, "Retried Room", false, nil, "same-key")
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i := range errs {
+		switch {
+		case errs[i] == nil:
+			created++
+		case !errors.Is(errs[i], ErrIdempotencyKeyUsed):
+			t.Fatalf("CreateRoomWithIdempotencyKey failed: %v", errs[i])
+		}
+	}
+	if created != 1 {
+		t.Errorf("Expected exactly 1 request to create the room, got %d", created)
+	}
+
+	var count int
+	if err := testDB.DB.QueryRow(`SELECT COUNT(*) FROM watch_sessions WHERE creator_id = $1`, userID).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rooms: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 room, got %d", count)
+	}
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MovieMetadata is the typed form of the TMDB fields CacheMovie stores in MediaItem.Metadata
+type MovieMetadata struct {
+	OriginalTitle string  `json:"original_title"`
+	Overview      string  `json:"overview"`
+	PosterPath    string  `json:"poster_path"`
+	BackdropPath  string  `json:"backdrop_path"`
+	ReleaseDate   string  `json:"release_date"`
+	VoteAverage   float64 `json:"vote_average"`
+	Popularity    float64 `json:"popularity"`
+	GenreIDs      []int   `json:"genre_ids"`
+}
+
+// DecodeMetadata unmarshals the item's raw metadata JSON into typed fields
+// Missing metadata decodes to the zero value
+func (m MediaItem) DecodeMetadata() (MovieMetadata, error) {
+	var metadata MovieMetadata
+	if len(m.Metadata) == 0 {
+		return metadata, nil
+	}
+
+	if err := json.Unmarshal(m.Metadata, &metadata); err != nil {
+		return MovieMetadata{}, fmt.Errorf("failed to decode metadata for media %s: %w", m.ID, err)
+	}
+
+	return metadata, nil
+}
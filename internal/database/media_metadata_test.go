@@ -0,0 +1,38 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMediaItem_DecodeMetadata(t *testing.T) {
+	t.Run("decodes typed fields", func(t *testing.T) {
+		item := MediaItem{Metadata: json.RawMessage(`{"poster_path":"/p.jpg","vote_average":7.5,"genre_ids":[18],"extra":"ignored"}`)}
+
+		metadata, err := item.DecodeMetadata()
+		if err != nil {
+			t.Fatalf("DecodeMetadata failed: %v", err)
+		}
+		if metadata.PosterPath != "/p.jpg" || metadata.VoteAverage != 7.5 || len(metadata.GenreIDs) != 1 {
+			t.Errorf("Unexpected metadata: %+v", metadata)
+		}
+	})
+
+	t.Run("treats missing metadata as empty", func(t *testing.T) {
+		for _, raw := range []json.RawMessage{nil, json.RawMessage(`null`)} {
+			metadata, err := MediaItem{Metadata: raw}.DecodeMetadata()
+			if err != nil {
+				t.Fatalf("DecodeMetadata(%q) failed: %v", raw, err)
+			}
+			if metadata.PosterPath != "" || metadata.GenreIDs != nil {
+				t.Errorf("Expected zero metadata for %q, got %+v", raw, metadata)
+			}
+		}
+	})
+
+	t.Run("rejects malformed metadata", func(t *testing.T) {
+		if _, err := (MediaItem{Metadata: json.RawMessage(`{"vote_average":"high"}`)}).DecodeMetadata(); err == nil {
+			t.Error("Expected an error for a mistyped field")
+		}
+	})
+}
@@ -5,9 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/tahaburak/would-watch-backend/internal/tmdb"
 	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
 )
 
 // MediaItem represents a media item stored in the database
@@ -17,8 +18,8 @@ type MediaItem struct {
 	MediaType string          `json:"media_type"`
 	Title     string          `json:"title"`
 	Metadata  json.RawMessage `json:"metadata"`
-	CreatedAt string          `json:"created_at"`
-	UpdatedAt string          `json:"updated_at"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 // MediaRepository handles media-related database operations
@@ -31,26 +32,22 @@ func NewMediaRepository(db *sql.DB) *MediaRepository {
 	return &MediaRepository{db: db}
 }
 
-// CacheMovie inserts or updates a movie in the database
-// Uses INSERT ON CONFLICT DO NOTHING to avoid duplicates
-func (r *MediaRepository) CacheMovie(ctx context.Context, movie tmdb.Movie) (*uuid.UUID, error) {
-	// Prepare metadata as JSON
-	metadata := map[string]interface{}{
-		"original_title":    movie.OriginalTitle,
-		"overview":          movie.Overview,
-		"poster_path":       movie.PosterPath,
-		"backdrop_path":     movie.BackdropPath,
-		"release_date":      movie.ReleaseDate,
-		"vote_average":      movie.VoteAverage,
-		"vote_count":        movie.VoteCount,
-		"popularity":        movie.Popularity,
-		"adult":             movie.Adult,
-		"video":             movie.Video,
-		"original_language": movie.OriginalLanguage,
-		"genre_ids":         movie.GenreIDs,
+// MediaInput is a movie or TV show to cache, with the TMDB fields to keep as metadata
+type MediaInput struct {
+	TMDBID    int
+	MediaType string
+	Title     string
+	Metadata  map[string]interface{}
+}
+
+// CacheMedia inserts or updates a media item, keyed by its TMDB ID and media type
+// The same TMDB ID may be cached once as a movie and once as a TV show
+func (r *MediaRepository) CacheMedia(ctx context.Context, item MediaInput) (*uuid.UUID, error) {
+	if item.MediaType != tmdb.MediaTypeMovie && item.MediaType != tmdb.MediaTypeTV {
+		return nil, fmt.Errorf("invalid media type %q", item.MediaType)
 	}
 
-	metadataJSON, err := json.Marshal(metadata)
+	metadataJSON, err := json.Marshal(item.Metadata)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -67,19 +64,66 @@ func (r *MediaRepository) CacheMovie(ctx context.Context, movie tmdb.Movie) (*uu
 
 	var id uuid.UUID
 	err = r.db.QueryRowContext(ctx, query,
-		movie.ID,
-		"movie",
-		movie.Title,
+		item.TMDBID,
+		item.MediaType,
+		item.Title,
 		metadataJSON,
 	).Scan(&id)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to cache movie: %w", err)
+		return nil, fmt.Errorf("failed to cache %s: %w", item.MediaType, err)
 	}
 
 	return &id, nil
 }
 
+// CacheMovie inserts or updates a movie in the database
+func (r *MediaRepository) CacheMovie(ctx context.Context, movie tmdb.Movie) (*uuid.UUID, error) {
+	return r.CacheMedia(ctx, MediaInput{
+		TMDBID:    movie.ID,
+		MediaType: tmdb.MediaTypeMovie,
+		Title:     movie.Title,
+		Metadata: map[string]interface{}{
+			"original_title":    movie.OriginalTitle,
+			"overview":          movie.Overview,
+			"poster_path":       movie.PosterPath,
+			"backdrop_path":     movie.BackdropPath,
+			"release_date":      movie.ReleaseDate,
+			"vote_average":      movie.VoteAverage,
+			"vote_count":        movie.VoteCount,
+			"popularity":        movie.Popularity,
+			"adult":             movie.Adult,
+			"video":             movie.Video,
+			"original_language": movie.OriginalLanguage,
+			"genre_ids":         movie.GenreIDs,
+		},
+	})
+}
+
+// CacheTVShow inserts or updates a TV show in the database
+// The first air date and original name are stored under the movie keys so DecodeMetadata reads both media types
+func (r *MediaRepository) CacheTVShow(ctx context.Context, show tmdb.TVShow) (*uuid.UUID, error) {
+	return r.CacheMedia(ctx, MediaInput{
+		TMDBID:    show.ID,
+		MediaType: tmdb.MediaTypeTV,
+		Title:     show.Name,
+		Metadata: map[string]interface{}{
+			"original_title":    show.OriginalName,
+			"overview":          show.Overview,
+			"poster_path":       show.PosterPath,
+			"backdrop_path":     show.BackdropPath,
+			"release_date":      show.FirstAirDate,
+			"vote_average":      show.VoteAverage,
+			"vote_count":        show.VoteCount,
+			"popularity":        show.Popularity,
+			"adult":             show.Adult,
+			"original_language": show.OriginalLanguage,
+			"origin_country":    show.OriginCountry,
+			"genre_ids":         show.GenreIDs,
+		},
+	})
+}
+
 // GetMediaByTMDBID retrieves a media item by its TMDB ID
 func (r *MediaRepository) GetMediaByTMDBID(ctx context.Context, tmdbID int, mediaType string) (*MediaItem, error) {
 	query := `
@@ -108,3 +152,59 @@ func (r *MediaRepository) GetMediaByTMDBID(ctx context.Context, tmdbID int, medi
 
 	return &item, nil
 }
+
+// GetMediaByID retrieves a media item by its local ID
+// Returns nil if no item has that ID
+func (r *MediaRepository) GetMediaByID(ctx context.Context, id uuid.UUID) (*MediaItem, error) {
+	query := `
+		SELECT id, tmdb_id, media_type, title, metadata, created_at, updated_at
+		FROM media_items
+		WHERE id = $1
+	`
+
+	var item MediaItem
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&item.ID,
+		&item.TMDBID,
+		&item.MediaType,
+		&item.Title,
+		&item.Metadata,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// DefaultUnreferencedMediaMaxAge is how long an unvoted media item is kept after it was last cached
+const DefaultUnreferencedMediaMaxAge = 30 * 24 * time.Hour
+
+// DeleteUnreferencedMedia removes cached media that no vote refers to and that was last cached before olderThan
+// Media queued as a session candidate is kept too, since it is about to be voted on; returns how many rows were deleted
+func (r *MediaRepository) DeleteUnreferencedMedia(ctx context.Context, olderThan time.Time) (int, error) {
+	query := `
+		DELETE FROM media_items m
+		WHERE m.updated_at < $1
+		AND NOT EXISTS (SELECT 1 FROM session_votes sv WHERE sv.media_id = m.id)
+		AND NOT EXISTS (SELECT 1 FROM session_candidates sc WHERE sc.media_id = m.id)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unreferenced media: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
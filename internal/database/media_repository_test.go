@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/testutils"
@@ -368,6 +369,58 @@ func TestMediaRepository_GetMediaByTMDBID(t *testing.T) {
 	})
 }
 
+func TestMediaRepository_DecodeMetadata(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewMediaRepository(testDB.DB)
+	ctx := context.Background()
+
+	movie := tmdb.Movie{
+		ID:          603,
+		Title:       "The Matrix",
+		Overview:    "A hacker learns the truth about reality",
+		PosterPath:  "/matrix.jpg",
+		ReleaseDate: "1999-03-30",
+		VoteAverage: 8.2,
+		GenreIDs:    []int{28, 878},
+	}
+
+	if _, err := repo.CacheMovie(ctx, movie); err != nil {
+		t.Fatalf("CacheMovie failed: %v", err)
+	}
+
+	item, err := repo.GetMediaByTMDBID(ctx, 603, "movie")
+	if err != nil {
+		t.Fatalf("GetMediaByTMDBID failed: %v", err)
+	}
+	if item == nil {
+		t.Fatal("Expected cached movie, got nil")
+	}
+
+	metadata, err := item.DecodeMetadata()
+	if err != nil {
+		t.Fatalf("DecodeMetadata failed: %v", err)
+	}
+
+	if metadata.PosterPath != movie.PosterPath {
+		t.Errorf("Expected poster path '%s', got '%s'", movie.PosterPath, metadata.PosterPath)
+	}
+	if metadata.Overview != movie.Overview {
+		t.Errorf("Expected overview '%s', got '%s'", movie.Overview, metadata.Overview)
+	}
+	if metadata.ReleaseDate != movie.ReleaseDate {
+		t.Errorf("Expected release date '%s', got '%s'", movie.ReleaseDate, metadata.ReleaseDate)
+	}
+	if metadata.VoteAverage != movie.VoteAverage {
+		t.Errorf("Expected vote average %.1f, got %.1f", movie.VoteAverage, metadata.VoteAverage)
+	}
+	if len(metadata.GenreIDs) != 2 || metadata.GenreIDs[0] != 28 || metadata.GenreIDs[1] != 878 {
+		t.Errorf("Expected genre IDs [28 878], got %v", metadata.GenreIDs)
+	}
+}
+
 func TestMediaRepository_Integration(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
@@ -472,3 +525,195 @@ func TestMediaRepository_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestMediaRepository_GetMediaByID(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewMediaRepository(testDB.DB)
+	ctx := context.Background()
+
+	mediaID := testDB.SeedMediaItem(t, 603, "movie", "The Matrix")
+
+	t.Run("retrieves an item by local ID", func(t *testing.T) {
+		item, err := repo.GetMediaByID(ctx, mediaID)
+		if err != nil {
+			t.Fatalf("GetMediaByID failed: %v", err)
+		}
+		if item == nil {
+			t.Fatal("Expected item, got nil")
+		}
+		if item.TMDBID != 603 || item.Title != "The Matrix" || item.MediaType != "movie" {
+			t.Errorf("Expected The Matrix (603), got %+v", item)
+		}
+	})
+
+	t.Run("returns nil for a missing ID", func(t *testing.T) {
+		item, err := repo.GetMediaByID(ctx, uuid.New())
+		if err != nil {
+			t.Fatalf("GetMediaByID failed: %v", err)
+		}
+		if item != nil {
+			t.Errorf("Expected nil, got %+v", item)
+		}
+	})
+}
+
+func TestMediaRepository_CacheTVShow(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewMediaRepository(testDB.DB)
+	ctx := context.Background()
+
+	show := tmdb.TVShow{
+		ID:           1396,
+		Name:         "Breaking Bad",
+		OriginalName: "Breaking Bad",
+		PosterPath:   "/bb.jpg",
+		FirstAirDate: "2008-01-20",
+		VoteAverage:  8.9,
+		GenreIDs:     []int{18, 80},
+	}
+
+	id, err := repo.CacheTVShow(ctx, show)
+	if err != nil {
+		t.Fatalf("CacheTVShow failed: %v", err)
+	}
+
+	t.Run("stores the show as tv media", func(t *testing.T) {
+		item, err := repo.GetMediaByTMDBID(ctx, 1396, tmdb.MediaTypeTV)
+		if err != nil {
+			t.Fatalf("GetMediaByTMDBID failed: %v", err)
+		}
+		if item == nil || item.ID != *id {
+			t.Fatalf("Expected cached show %s, got %+v", id, item)
+		}
+		if item.Title != "Breaking Bad" {
+			t.Errorf("Expected title 'Breaking Bad', got '%s'", item.Title)
+		}
+
+		metadata, err := item.DecodeMetadata()
+		if err != nil {
+			t.Fatalf("DecodeMetadata failed: %v", err)
+		}
+		if metadata.ReleaseDate != "2008-01-20" || metadata.PosterPath != "/bb.jpg" {
+			t.Errorf("Expected first air date and poster in metadata, got %+v", metadata)
+		}
+	})
+
+	t.Run("keeps a movie with the same TMDB ID separate", func(t *testing.T) {
+		movieID, err := repo.CacheMovie(ctx, tmdb.Movie{ID: 1396, Title: "Same ID Movie"})
+		if err != nil {
+			t.Fatalf("CacheMovie failed: %v", err)
+		}
+		if *movieID == *id {
+			t.Error("Expected the movie and TV show to be cached as separate items")
+		}
+	})
+}
+
+func TestMediaRepository_CacheMedia(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewMediaRepository(testDB.DB)
+	ctx := context.Background()
+
+	t.Run("same TMDB ID as movie and tv creates two rows", func(t *testing.T) {
+		movieID, err := repo.CacheMedia(ctx, MediaInput{TMDBID: 4242, MediaType: tmdb.MediaTypeMovie, Title: "Shared Movie"})
+		if err != nil {
+			t.Fatalf("CacheMedia movie failed: %v", err)
+		}
+		tvID, err := repo.CacheMedia(ctx, MediaInput{TMDBID: 4242, MediaType: tmdb.MediaTypeTV, Title: "Shared Show"})
+		if err != nil {
+			t.Fatalf("CacheMedia tv failed: %v", err)
+		}
+		if *movieID == *tvID {
+			t.Error("Expected distinct IDs for the movie and the TV show")
+		}
+
+		// Re-caching the show updates its row rather than adding one
+		if _, err := repo.CacheMedia(ctx, MediaInput{TMDBID: 4242, MediaType: tmdb.MediaTypeTV, Title: "Renamed Show"}); err != nil {
+			t.Fatalf("CacheMedia tv update failed: %v", err)
+		}
+
+		var count int
+		if err := testDB.DB.QueryRow("SELECT COUNT(*) FROM media_items WHERE tmdb_id = 4242").Scan(&count); err != nil {
+			t.Fatalf("Failed to count records: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 records, got %d", count)
+		}
+
+		show, err := repo.GetMediaByTMDBID(ctx, 4242, tmdb.MediaTypeTV)
+		if err != nil {
+			t.Fatalf("GetMediaByTMDBID failed: %v", err)
+		}
+		if show.Title != "Renamed Show" {
+			t.Errorf("Expected the TV row to be updated, got title '%s'", show.Title)
+		}
+	})
+
+	t.Run("rejects an unknown media type", func(t *testing.T) {
+		if _, err := repo.CacheMedia(ctx, MediaInput{TMDBID: 1, MediaType: "podcast", Title: "Nope"}); err == nil {
+			t.Error("Expected an error for an unknown media type")
+		}
+	})
+}
+
+func TestMediaRepository_DeleteUnreferencedMedia(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewMediaRepository(testDB.DB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "voter")
+	sessionID := testDB.SeedWatchSession(t, userID, "Cleanup Session", false)
+
+	referencedID := testDB.SeedMediaItem(t, 7001, "movie", "Voted Movie")
+	unreferencedID := testDB.SeedMediaItem(t, 7002, "movie", "Unvoted Movie")
+	candidateID := testDB.SeedMediaItem(t, 7003, "movie", "Queued Movie")
+
+	testDB.SeedVote(t, sessionID, userID, referencedID, "no")
+	if _, err := testDB.DB.ExecContext(ctx, "INSERT INTO session_candidates (session_id, media_id) VALUES ($1, $2)", sessionID, candidateID); err != nil {
+		t.Fatalf("Failed to seed candidate: %v", err)
+	}
+
+	t.Run("keeps media cached after the cutoff", func(t *testing.T) {
+		deleted, err := repo.DeleteUnreferencedMedia(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("DeleteUnreferencedMedia failed: %v", err)
+		}
+		if deleted != 0 {
+			t.Errorf("Expected nothing deleted, got %d", deleted)
+		}
+	})
+
+	t.Run("deletes only the unreferenced old media", func(t *testing.T) {
+		// A cutoff in the future makes every seeded row old enough
+		deleted, err := repo.DeleteUnreferencedMedia(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("DeleteUnreferencedMedia failed: %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("Expected 1 deleted, got %d", deleted)
+		}
+
+		for id, wantKept := range map[uuid.UUID]bool{referencedID: true, candidateID: true, unreferencedID: false} {
+			item, err := repo.GetMediaByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetMediaByID failed: %v", err)
+			}
+			if (item != nil) != wantKept {
+				t.Errorf("Expected media %s kept=%v, got kept=%v", id, wantKept, item != nil)
+			}
+		}
+	})
+}
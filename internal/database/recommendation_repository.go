@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RecommendationRepository handles the record of recommendations shown in each session
+type RecommendationRepository struct {
+	db *sql.DB
+}
+
+// NewRecommendationRepository creates a new recommendation repository
+func NewRecommendationRepository(db *sql.DB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// GetRecommendedTMDBIDs retrieves the TMDB IDs already recommended in a session, oldest first
+func (r *RecommendationRepository) GetRecommendedTMDBIDs(ctx context.Context, sessionID uuid.UUID) ([]int, error) {
+	query := `
+		SELECT tmdb_id
+		FROM session_recommendations
+		WHERE session_id = $1
+		ORDER BY created_at, tmdb_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommended ids: %w", err)
+	}
+	defer rows.Close()
+
+	var tmdbIDs []int
+	for rows.Next() {
+		var tmdbID int
+		if err := rows.Scan(&tmdbID); err != nil {
+			return nil, fmt.Errorf("failed to scan recommended id: %w", err)
+		}
+		tmdbIDs = append(tmdbIDs, tmdbID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recommended ids: %w", err)
+	}
+
+	return tmdbIDs, nil
+}
+
+// SaveRecommendations adds TMDB IDs to a session's recommended set; IDs already recorded are kept as they were
+func (r *RecommendationRepository) SaveRecommendations(ctx context.Context, sessionID uuid.UUID, tmdbIDs []int) error {
+	if len(tmdbIDs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(tmdbIDs))
+	args := make([]interface{}, 0, len(tmdbIDs)+1)
+	args = append(args, sessionID)
+	for i, tmdbID := range tmdbIDs {
+		placeholders = append(placeholders, fmt.Sprintf("($1, $%d)", i+2))
+		args = append(args, tmdbID)
+	}
+
+	query := `
+		INSERT INTO session_recommendations (session_id, tmdb_id)
+		VALUES ` + strings.Join(placeholders, ", ") + `
+		ON CONFLICT (session_id, tmdb_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to save recommendations: %w", err)
+	}
+
+	return nil
+}
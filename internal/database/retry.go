@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// DefaultRetryAttempts is how many times WithRetry runs a read before giving up
+	DefaultRetryAttempts = 3
+	// DefaultRetryBackoff is the delay before the first retry; it doubles on each attempt
+	DefaultRetryBackoff = 50 * time.Millisecond
+)
+
+// transientSQLStates lists server error codes that mean "try again shortly" rather than a bad query
+var transientSQLStates = map[string]bool{
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsTransient reports whether err looks like a temporary database availability problem
+// (dropped connection, pooler restart, network failure) rather than a query or data error
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Both pgx and lib/pq errors expose the SQLSTATE code
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		code := stateErr.SQLState()
+		// Class 08 is connection exception
+		return strings.HasPrefix(code, "08") || transientSQLStates[code]
+	}
+
+	return false
+}
+
+// foreignKeyViolation is the SQLSTATE for a row referencing a missing parent
+const foreignKeyViolation = "23503"
+
+// IsForeignKeyViolation reports whether err is a foreign-key violation, which usually means
+// the caller referenced a user, room or media item that does not exist
+func IsForeignKeyViolation(err error) bool {
+	var stateErr interface{ SQLState() string }
+	return errors.As(err, &stateErr) && stateErr.SQLState() == foreignKeyViolation
+}
+
+// WithRetry runs fn, retrying with exponential backoff while it fails with a transient error
+// Non-transient errors are returned immediately; the last error is returned once attempts run out
+func WithRetry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil || !IsTransient(err) || attempt == attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"bad connection", fmt.Errorf("query: %w", driver.ErrBadConn), true},
+		{"pgx connection exception", &pgconn.PgError{Code: "08006"}, true},
+		{"pgx cannot connect now", &pgconn.PgError{Code: "57P03"}, true},
+		{"pq too many connections", &pq.Error{Code: "53300"}, true},
+		{"pgx unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTransient(tc.err); got != tc.expected {
+				t.Errorf("Expected IsTransient=%v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsForeignKeyViolation(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"pgx foreign key violation", &pgconn.PgError{Code: "23503"}, true},
+		{"wrapped pq foreign key violation", fmt.Errorf("failed to follow user: %w", &pq.Error{Code: "23503"}), true},
+		{"pgx unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsForeignKeyViolation(tc.err); got != tc.expected {
+				t.Errorf("Expected IsForeignKeyViolation=%v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		calls := 0
+		err := WithRetry(ctx, 3, time.Millisecond, func() error {
+			calls++
+			if calls < 3 {
+				return driver.ErrBadConn
+			}
+			return nil
+		})
+
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("stops immediately on non-transient errors", func(t *testing.T) {
+		calls := 0
+		err := WithRetry(ctx, 3, time.Millisecond, func() error {
+			calls++
+			return errors.New("boom")
+		})
+
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got %d", calls)
+		}
+	})
+}
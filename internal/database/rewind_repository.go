@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rewind summarizes a user's activity over a time window
+type Rewind struct {
+	SessionCount int     `json:"session_count"`
+	MatchCount   int     `json:"match_count"`
+	NewFollowers int     `json:"new_followers"`
+	NewFollowing int     `json:"new_following"`
+	TopGenreID   *int    `json:"top_genre_id"`
+	TopGenre     *string `json:"top_genre"`
+}
+
+// GenreAgreement is how often a genre's candidates became matches across a user's sessions
+type GenreAgreement struct {
+	GenreID        int     `json:"genre_id"`
+	Genre          *string `json:"genre"`
+	CandidateCount int     `json:"candidate_count"`
+	MatchCount     int     `json:"match_count"`
+	MatchRate      float64 `json:"match_rate"`
+}
+
+// UserStats holds a user's all-time activity totals
+type UserStats struct {
+	SessionsCreated int `json:"sessions_created"`
+	MatchCount      int `json:"match_count"`
+	LikedCount      int `json:"liked_count"`
+	FollowingCount  int `json:"following_count"`
+	FollowerCount   int `json:"follower_count"`
+}
+
+// RewindRepository handles activity summary queries
+type RewindRepository struct {
+	db *sql.DB
+}
+
+// NewRewindRepository creates a new rewind repository
+func NewRewindRepository(db *sql.DB) *RewindRepository {
+	return &RewindRepository{db: db}
+}
+
+// GetRewind summarizes a user's activity between start (inclusive) and end (exclusive)
+// Sessions count rooms the user created or voted in; matches count titles the user liked
+// that matched inside the window; the top genre comes from the user's "yes" votes
+func (r *RewindRepository) GetRewind(ctx context.Context, userID uuid.UUID, start, end time.Time) (*Rewind, error) {
+	countsQuery := `
+		WITH matches AS (
+			SELECT session_id, media_id, MAX(created_at) AS matched_at
+			FROM session_votes
+			WHERE vote = 'yes'
+			GROUP BY session_id, media_id
+			HAVING COUNT(DISTINCT user_id) >= 2
+		)
+		SELECT
+			(
+				SELECT COUNT(*)
+				FROM (
+					SELECT ws.id
+					FROM watch_sessions ws
+					WHERE ws.creator_id = $1
+					AND ws.created_at >= $2 AND ws.created_at < $3
+					UNION
+					SELECT sv.session_id
+					FROM session_votes sv
+					WHERE sv.user_id = $1
+					AND sv.created_at >= $2 AND sv.created_at < $3
+				) sessions
+			),
+			(
+				SELECT COUNT(*)
+				FROM matches mt
+				WHERE mt.matched_at >= $2 AND mt.matched_at < $3
+				AND EXISTS (
+					SELECT 1 FROM session_votes sv
+					WHERE sv.session_id = mt.session_id
+					AND sv.media_id = mt.media_id
+					AND sv.user_id = $1
+					AND sv.vote = 'yes'
+				)
+			),
+			(
+				SELECT COUNT(*)
+				FROM user_follows uf
+				WHERE uf.following_id = $1
+				AND uf.created_at >= $2 AND uf.created_at < $3
+				AND uf.deleted_at IS NULL
+			),
+			(
+				SELECT COUNT(*)
+				FROM user_follows uf
+				WHERE uf.follower_id = $1
+				AND uf.created_at >= $2 AND uf.created_at < $3
+				AND uf.deleted_at IS NULL
+			)
+	`
+
+	var rewind Rewind
+	err := r.db.QueryRowContext(ctx, countsQuery, userID, start, end).Scan(
+		&rewind.SessionCount,
+		&rewind.MatchCount,
+		&rewind.NewFollowers,
+		&rewind.NewFollowing,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rewind counts: %w", err)
+	}
+
+	genreQuery := `
+		SELECT genre.id::int
+		FROM session_votes sv
+		JOIN media_items m ON sv.media_id = m.id
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(m.metadata->'genre_ids') = 'array'
+				THEN m.metadata->'genre_ids'
+				ELSE '[]'::jsonb
+			END
+		) AS genre(id)
+		WHERE sv.user_id = $1
+		AND sv.vote = 'yes'
+		AND sv.created_at >= $2 AND sv.created_at < $3
+		GROUP BY genre.id
+		ORDER BY COUNT(*) DESC, genre.id::int
+		LIMIT 1
+	`
+
+	var topGenreID int
+	err = r.db.QueryRowContext(ctx, genreQuery, userID, start, end).Scan(&topGenreID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get rewind top genre: %w", err)
+	}
+	if err == nil {
+		rewind.TopGenreID = &topGenreID
+	}
+
+	return &rewind, nil
+}
+
+// GetUserStats counts a user's activity since they joined
+// Matches count titles the user liked that someone else in the same session also liked;
+// each count is an index lookup on the user's ID
+func (r *RewindRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	query := `
+		SELECT
+			(
+				SELECT COUNT(*)
+				FROM watch_sessions
+				WHERE creator_id = $1
+			),
+			(
+				SELECT COUNT(*)
+				FROM session_votes mine
+				WHERE mine.user_id = $1
+				AND mine.vote = 'yes'
+				AND EXISTS (
+					SELECT 1 FROM session_votes other
+					WHERE other.session_id = mine.session_id
+					AND other.media_id = mine.media_id
+					AND other.user_id <> mine.user_id
+					AND other.vote = 'yes'
+				)
+			),
+			(
+				SELECT COUNT(*)
+				FROM session_votes
+				WHERE user_id = $1 AND vote = 'yes'
+			),
+			(
+				SELECT COUNT(*)
+				FROM user_follows
+				WHERE follower_id = $1 AND deleted_at IS NULL
+			),
+			(
+				SELECT COUNT(*)
+				FROM user_follows
+				WHERE following_id = $1 AND deleted_at IS NULL
+			)
+	`
+
+	var stats UserStats
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&stats.SessionsCreated,
+		&stats.MatchCount,
+		&stats.LikedCount,
+		&stats.FollowingCount,
+		&stats.FollowerCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// GetGenreAgreement computes per-genre match rates over every session the user created, joined, or voted in
+// A session's candidates are its queued titles plus any title voted on there; a candidate counts once per
+// genre it carries. Genres are ordered by match rate, then match count
+func (r *RewindRepository) GetGenreAgreement(ctx context.Context, userID uuid.UUID) ([]GenreAgreement, error) {
+	query := `
+		WITH user_sessions AS (
+			SELECT id AS session_id FROM watch_sessions WHERE creator_id = $1
+			UNION
+			SELECT room_id FROM room_participants WHERE user_id = $1 AND status = 'joined'
+			UNION
+			SELECT session_id FROM session_votes WHERE user_id = $1
+		),
+		candidates AS (
+			SELECT session_id, media_id FROM session_candidates
+			WHERE session_id IN (SELECT session_id FROM user_sessions)
+			UNION
+			SELECT session_id, media_id FROM session_votes
+			WHERE session_id IN (SELECT session_id FROM user_sessions)
+		),
+		matches AS (
+			SELECT session_id, media_id
+			FROM session_votes
+			WHERE vote = 'yes'
+			AND session_id IN (SELECT session_id FROM user_sessions)
+			GROUP BY session_id, media_id
+			HAVING COUNT(DISTINCT user_id) >= 2
+		)
+		SELECT genre.id::int, COUNT(*) AS candidate_count, COUNT(mt.media_id) AS match_count
+		FROM candidates c
+		JOIN media_items m ON m.id = c.media_id
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(m.metadata->'genre_ids') = 'array'
+				THEN m.metadata->'genre_ids'
+				ELSE '[]'::jsonb
+			END
+		) AS genre(id)
+		LEFT JOIN matches mt ON mt.session_id = c.session_id AND mt.media_id = c.media_id
+		GROUP BY genre.id
+		ORDER BY COUNT(mt.media_id)::float / COUNT(*) DESC, COUNT(mt.media_id) DESC, genre.id::int
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query genre agreement: %w", err)
+	}
+	defer rows.Close()
+
+	var agreement []GenreAgreement
+	for rows.Next() {
+		var genre GenreAgreement
+		err := rows.Scan(
+			&genre.GenreID,
+			&genre.CandidateCount,
+			&genre.MatchCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan genre agreement: %w", err)
+		}
+		genre.MatchRate = float64(genre.MatchCount) / float64(genre.CandidateCount)
+		agreement = append(agreement, genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return agreement, nil
+}
@@ -0,0 +1,323 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+)
+
+func TestRewindRepository_GetRewind(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRewindRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "rewind_user")
+
+	friendID := uuid.New()
+	testDB.SeedProfile(t, friendID, "rewind_friend")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "rewind_other")
+
+	setGenres := func(mediaID uuid.UUID, genres string) {
+		t.Helper()
+		_, err := testDB.DB.Exec(`UPDATE media_items SET metadata = jsonb_build_object('genre_ids', $2::jsonb) WHERE id = $1`, mediaID, genres)
+		if err != nil {
+			t.Fatalf("Failed to set genres: %v", err)
+		}
+	}
+
+	backdate := func(query string, args ...interface{}) {
+		t.Helper()
+		if _, err := testDB.DB.Exec(query, args...); err != nil {
+			t.Fatalf("Failed to backdate row: %v", err)
+		}
+	}
+
+	// In-window activity: one session with a match and two action likes
+	recentSession := testDB.SeedWatchSession(t, userID, "This Week", false)
+
+	actionAdventure := testDB.SeedMediaItem(t, 9001, "movie", "Action Adventure")
+	setGenres(actionAdventure, "[28, 12]")
+	testDB.SeedVote(t, recentSession, userID, actionAdventure, "yes")
+	testDB.SeedVote(t, recentSession, friendID, actionAdventure, "yes")
+
+	action := testDB.SeedMediaItem(t, 9002, "movie", "Action Only")
+	setGenres(action, "[28]")
+	testDB.SeedVote(t, recentSession, userID, action, "yes")
+
+	testDB.SeedFollow(t, otherID, userID)
+
+	// Out-of-window activity: an older session full of comedy matches
+	oldSession := testDB.SeedWatchSession(t, userID, "Last Month", false)
+	backdate(`UPDATE watch_sessions SET created_at = NOW() - interval '10 days' WHERE id = $1`, oldSession)
+
+	for i, tmdbID := range []int{9101, 9102, 9103} {
+		comedy := testDB.SeedMediaItem(t, tmdbID, "movie", fmt.Sprintf("Old Comedy %d", i))
+		setGenres(comedy, "[35]")
+		testDB.SeedVote(t, oldSession, userID, comedy, "yes")
+		testDB.SeedVote(t, oldSession, friendID, comedy, "yes")
+		setVoteTime(t, testDB, oldSession, userID, comedy, "10 days")
+		setVoteTime(t, testDB, oldSession, friendID, comedy, "10 days")
+	}
+
+	testDB.SeedFollow(t, userID, otherID)
+	backdate(`UPDATE user_follows SET created_at = NOW() - interval '10 days' WHERE follower_id = $1 AND following_id = $2`, userID, otherID)
+
+	testDB.SeedFollow(t, friendID, userID)
+	backdate(`UPDATE user_follows SET created_at = NOW() - interval '10 days' WHERE follower_id = $1 AND following_id = $2`, friendID, userID)
+
+	end := time.Now().Add(time.Minute)
+	start := end.Add(-7 * 24 * time.Hour)
+
+	t.Run("counts only in-window activity", func(t *testing.T) {
+		rewind, err := repo.GetRewind(ctx, userID, start, end)
+		if err != nil {
+			t.Fatalf("GetRewind failed: %v", err)
+		}
+
+		if rewind.SessionCount != 1 {
+			t.Errorf("Expected 1 session, got %d", rewind.SessionCount)
+		}
+		if rewind.MatchCount != 1 {
+			t.Errorf("Expected 1 match, got %d", rewind.MatchCount)
+		}
+		if rewind.NewFollowers != 1 {
+			t.Errorf("Expected 1 new follower, got %d", rewind.NewFollowers)
+		}
+		if rewind.NewFollowing != 0 {
+			t.Errorf("Expected 0 new following, got %d", rewind.NewFollowing)
+		}
+	})
+
+	t.Run("picks top genre from in-window likes", func(t *testing.T) {
+		rewind, err := repo.GetRewind(ctx, userID, start, end)
+		if err != nil {
+			t.Fatalf("GetRewind failed: %v", err)
+		}
+
+		if rewind.TopGenreID == nil {
+			t.Fatal("Expected a top genre, got nil")
+		}
+		if *rewind.TopGenreID != 28 {
+			t.Errorf("Expected top genre 28, got %d", *rewind.TopGenreID)
+		}
+	})
+
+	t.Run("returns zeros for an inactive week", func(t *testing.T) {
+		idleID := uuid.New()
+		testDB.SeedProfile(t, idleID, "rewind_idle")
+
+		rewind, err := repo.GetRewind(ctx, idleID, start, end)
+		if err != nil {
+			t.Fatalf("GetRewind failed: %v", err)
+		}
+
+		if rewind.SessionCount != 0 || rewind.MatchCount != 0 || rewind.NewFollowers != 0 || rewind.NewFollowing != 0 {
+			t.Errorf("Expected all counts to be 0, got %+v", rewind)
+		}
+		if rewind.TopGenreID != nil {
+			t.Errorf("Expected no top genre, got %d", *rewind.TopGenreID)
+		}
+	})
+}
+
+func TestRewindRepository_GetGenreAgreement(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRewindRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "agreement_user")
+
+	friendID := uuid.New()
+	testDB.SeedProfile(t, friendID, "agreement_friend")
+
+	strangerID := uuid.New()
+	testDB.SeedProfile(t, strangerID, "agreement_stranger")
+
+	setGenres := func(mediaID uuid.UUID, genres string) {
+		t.Helper()
+		_, err := testDB.DB.Exec(`UPDATE media_items SET metadata = jsonb_build_object('genre_ids', $2::jsonb) WHERE id = $1`, mediaID, genres)
+		if err != nil {
+			t.Fatalf("Failed to set genres: %v", err)
+		}
+	}
+
+	t.Run("returns empty list without sessions", func(t *testing.T) {
+		agreement, err := repo.GetGenreAgreement(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetGenreAgreement failed: %v", err)
+		}
+
+		if len(agreement) != 0 {
+			t.Errorf("Expected no genres, got %d", len(agreement))
+		}
+	})
+
+	sessionID := testDB.SeedWatchSession(t, userID, "Movie Night", false)
+
+	// Comedy: two of three candidates matched
+	comedyMatch := testDB.SeedMediaItem(t, 12001, "movie", "Comedy Match")
+	setGenres(comedyMatch, "[35]")
+	testDB.SeedVote(t, sessionID, userID, comedyMatch, "yes")
+	testDB.SeedVote(t, sessionID, friendID, comedyMatch, "yes")
+
+	comedyMiss := testDB.SeedMediaItem(t, 12002, "movie", "Comedy Miss")
+	setGenres(comedyMiss, "[35]")
+	testDB.SeedVote(t, sessionID, userID, comedyMiss, "yes")
+
+	// Drama comedy: matched, counts toward both genres
+	dramedy := testDB.SeedMediaItem(t, 12003, "movie", "Dramedy")
+	setGenres(dramedy, "[18, 35]")
+	testDB.SeedVote(t, sessionID, userID, dramedy, "yes")
+	testDB.SeedVote(t, sessionID, friendID, dramedy, "yes")
+
+	// Action: never matched
+	action := testDB.SeedMediaItem(t, 12004, "movie", "Action Miss")
+	setGenres(action, "[28]")
+	testDB.SeedVote(t, sessionID, userID, action, "yes")
+	testDB.SeedVote(t, sessionID, friendID, action, "no")
+
+	// A stranger's session with a horror match is not counted
+	otherSession := testDB.SeedWatchSession(t, strangerID, "Not Mine", false)
+	horror := testDB.SeedMediaItem(t, 12005, "movie", "Horror Match")
+	setGenres(horror, "[27]")
+	testDB.SeedVote(t, otherSession, strangerID, horror, "yes")
+	testDB.SeedVote(t, otherSession, friendID, horror, "yes")
+
+	agreement, err := repo.GetGenreAgreement(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetGenreAgreement failed: %v", err)
+	}
+
+	t.Run("returns per-genre counts and rates", func(t *testing.T) {
+		expected := []struct {
+			genreID    int
+			candidates int
+			matches    int
+			rate       float64
+		}{
+			{18, 1, 1, 1.0},
+			{35, 3, 2, 2.0 / 3.0},
+			{28, 1, 0, 0},
+		}
+
+		if len(agreement) != len(expected) {
+			t.Fatalf("Expected %d genres, got %d: %+v", len(expected), len(agreement), agreement)
+		}
+
+		for i, want := range expected {
+			got := agreement[i]
+			if got.GenreID != want.genreID {
+				t.Errorf("Expected genre %d at position %d, got %d", want.genreID, i, got.GenreID)
+				continue
+			}
+			if got.CandidateCount != want.candidates || got.MatchCount != want.matches {
+				t.Errorf("Genre %d: expected %d/%d, got %d/%d", want.genreID, want.matches, want.candidates, got.MatchCount, got.CandidateCount)
+			}
+			if diff := got.MatchRate - want.rate; diff > 0.0001 || diff < -0.0001 {
+				t.Errorf("Genre %d: expected rate %.4f, got %.4f", want.genreID, want.rate, got.MatchRate)
+			}
+		}
+	})
+
+	t.Run("excludes sessions the user is not part of", func(t *testing.T) {
+		for _, genre := range agreement {
+			if genre.GenreID == 27 {
+				t.Error("Expected horror from a stranger's session to be excluded")
+			}
+		}
+	})
+}
+
+func TestRewindRepository_GetUserStats(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRewindRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "stats_user")
+
+	friendID := uuid.New()
+	testDB.SeedProfile(t, friendID, "stats_friend")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "stats_other")
+
+	t.Run("returns zeroes for a new user", func(t *testing.T) {
+		stats, err := repo.GetUserStats(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetUserStats failed: %v", err)
+		}
+
+		if *stats != (UserStats{}) {
+			t.Errorf("Expected all counts to be 0, got %+v", stats)
+		}
+	})
+
+	// Two sessions created by the user, one by a friend
+	sessionID := testDB.SeedWatchSession(t, userID, "Stats Night", false)
+	testDB.SeedWatchSession(t, userID, "Stats Encore", false)
+	friendSession := testDB.SeedWatchSession(t, friendID, "Friend Night", false)
+
+	// Three likes: two matched with the friend, one not; a dislike is ignored
+	matched := testDB.SeedMediaItem(t, 13001, "movie", "Matched")
+	testDB.SeedVote(t, sessionID, userID, matched, "yes")
+	testDB.SeedVote(t, sessionID, friendID, matched, "yes")
+
+	unmatched := testDB.SeedMediaItem(t, 13002, "movie", "Unmatched")
+	testDB.SeedVote(t, sessionID, userID, unmatched, "yes")
+	testDB.SeedVote(t, sessionID, friendID, unmatched, "no")
+
+	disliked := testDB.SeedMediaItem(t, 13003, "movie", "Disliked")
+	testDB.SeedVote(t, sessionID, userID, disliked, "no")
+
+	testDB.SeedVote(t, friendSession, userID, matched, "yes")
+	testDB.SeedVote(t, friendSession, friendID, matched, "yes")
+
+	// Following the friend, followed by both; the unfollow of other is excluded
+	testDB.SeedFollow(t, userID, friendID)
+	testDB.SeedFollow(t, userID, otherID)
+	testDB.SeedFollow(t, friendID, userID)
+	testDB.SeedFollow(t, otherID, userID)
+	_, err := testDB.DB.Exec(`UPDATE user_follows SET deleted_at = NOW() WHERE follower_id = $1 AND following_id = $2`, userID, otherID)
+	if err != nil {
+		t.Fatalf("Failed to soft-delete follow: %v", err)
+	}
+
+	t.Run("counts seeded activity", func(t *testing.T) {
+		stats, err := repo.GetUserStats(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetUserStats failed: %v", err)
+		}
+
+		want := UserStats{
+			SessionsCreated: 2,
+			MatchCount:      2,
+			LikedCount:      3,
+			FollowingCount:  1,
+			FollowerCount:   2,
+		}
+		if *stats != want {
+			t.Errorf("Expected %+v, got %+v", want, *stats)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,9 +17,29 @@ type Room struct {
 	Name        *string    `json:"name,omitempty"`
 	IsPublic    bool       `json:"is_public"`
 	Status      string     `json:"status"`
-	CreatedAt   string     `json:"created_at"`
-	UpdatedAt   string     `json:"updated_at"`
-	CompletedAt *string    `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Participant roles stored in room_participants.role
+const (
+	// RoleOwner is given to the room creator and may change other participants' roles
+	RoleOwner = "owner"
+	// RoleModerator moderates a room and may invite users alongside the owner
+	RoleModerator = "moderator"
+	// RoleMember is the default role for invited members
+	RoleMember = "member"
+)
+
+// IsValidParticipantRole reports whether role is a known participant role
+func IsValidParticipantRole(role string) bool {
+	return role == RoleOwner || role == RoleModerator || role == RoleMember
+}
+
+// CanInvite reports whether a participant with role may invite users to the room
+func CanInvite(role string) bool {
+	return role == RoleOwner || role == RoleModerator
 }
 
 // RoomRepository handles room-related database operations
@@ -32,6 +54,12 @@ func NewRoomRepository(db *sql.DB) *RoomRepository {
 
 // CreateRoom creates a new room with initial participants
 func (r *RoomRepository) CreateRoom(ctx context.Context, creatorID uuid.UUID, name string, isPublic bool, initialMembers []uuid.UUID) (*Room, error) {
+	return r.CreateRoomWithIdempotencyKey(ctx, creatorID, name, isPublic, initialMembers, "")
+}
+
+// CreateRoomWithIdempotencyKey creates a room like CreateRoom, reserving the creator's idempotency key in the same transaction
+// Returns ErrIdempotencyKeyUsed, creating nothing, when another request already used the key
+func (r *RoomRepository) CreateRoomWithIdempotencyKey(ctx context.Context, creatorID uuid.UUID, name string, isPublic bool, initialMembers []uuid.UUID, idempotencyKey string) (*Room, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -60,25 +88,33 @@ func (r *RoomRepository) CreateRoom(ctx context.Context, creatorID uuid.UUID, na
 		return nil, fmt.Errorf("failed to create room: %w", err)
 	}
 
-	// Add creator as first participant
+	// Add the creator as owner and initial members as viewers in a single statement
+	// Everyone chosen at creation starts out joined; later invites wait for the invitee to accept
+	participantIDs := dedupeParticipants(creatorID, initialMembers)
+	placeholders := make([]string, 0, len(participantIDs))
+	args := make([]interface{}, 0, 2*len(participantIDs)+1)
+	args = append(args, room.ID)
+	for i, participantID := range participantIDs {
+		placeholders = append(placeholders, fmt.Sprintf("($1, $%d, $%d, 'joined', NOW())", 2*i+2, 2*i+3))
+		role := RoleMember
+		if participantID == creatorID {
+			role = RoleOwner
+		}
+		args = append(args, participantID, role)
+	}
+
 	participantQuery := `
-		INSERT INTO room_participants (room_id, user_id)
-		VALUES ($1, $2)
+		INSERT INTO room_participants (room_id, user_id, role, status, joined_at)
+		VALUES ` + strings.Join(placeholders, ", ") + `
+		ON CONFLICT (room_id, user_id) DO NOTHING
 	`
-	_, err = tx.ExecContext(ctx, participantQuery, room.ID, creatorID)
+	_, err = tx.ExecContext(ctx, participantQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add creator to room: %w", err)
+		return nil, fmt.Errorf("failed to add participants to room: %w", err)
 	}
 
-	// Add initial members
-	for _, memberID := range initialMembers {
-		if memberID == creatorID {
-			continue // Skip creator as already added
-		}
-		_, err = tx.ExecContext(ctx, participantQuery, room.ID, memberID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add member to room: %w", err)
-		}
+	if err = reserveIdempotencyKey(ctx, tx, creatorID, IdempotencyScopeRoom, idempotencyKey, room.ID); err != nil {
+		return nil, err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -88,15 +124,38 @@ func (r *RoomRepository) CreateRoom(ctx context.Context, creatorID uuid.UUID, na
 	return &room, nil
 }
 
-// AddParticipant adds a user to a room
-func (r *RoomRepository) AddParticipant(ctx context.Context, roomID, userID uuid.UUID) error {
+// dedupeParticipants returns the creator followed by the unique initial members,
+// preserving the order in which members were given
+func dedupeParticipants(creatorID uuid.UUID, initialMembers []uuid.UUID) []uuid.UUID {
+	seen := map[uuid.UUID]bool{creatorID: true}
+	participants := []uuid.UUID{creatorID}
+	for _, memberID := range initialMembers {
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+		participants = append(participants, memberID)
+	}
+	return participants
+}
+
+// AddParticipant invites a user to a room with the given role, or RoleMember when role is empty
+// The invite stays pending until the user accepts it. An existing participant keeps their current role and status
+func (r *RoomRepository) AddParticipant(ctx context.Context, roomID, userID uuid.UUID, role string) error {
+	if role == "" {
+		role = RoleMember
+	}
+	if !IsValidParticipantRole(role) {
+		return fmt.Errorf("invalid participant role %q", role)
+	}
+
 	query := `
-		INSERT INTO room_participants (room_id, user_id)
-		VALUES ($1, $2)
+		INSERT INTO room_participants (room_id, user_id, role)
+		VALUES ($1, $2, $3)
 		ON CONFLICT (room_id, user_id) DO NOTHING
 	`
 
-	_, err := r.db.ExecContext(ctx, query, roomID, userID)
+	_, err := r.db.ExecContext(ctx, query, roomID, userID, role)
 	if err != nil {
 		return fmt.Errorf("failed to add participant: %w", err)
 	}
@@ -104,19 +163,158 @@ func (r *RoomRepository) AddParticipant(ctx context.Context, roomID, userID uuid
 	return nil
 }
 
-// GetRoomsByUser retrieves all rooms a user is part of
-func (r *RoomRepository) GetRoomsByUser(ctx context.Context, userID uuid.UUID) ([]Room, error) {
+// JoinRoom adds a user to a room as a joined member on their own behalf
+// A pending or declined invite is accepted; a participant who already joined keeps their role and join time
+func (r *RoomRepository) JoinRoom(ctx context.Context, roomID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO room_participants (room_id, user_id, role, status, joined_at)
+		VALUES ($1, $2, $3, 'joined', NOW())
+		ON CONFLICT (room_id, user_id) DO UPDATE
+		SET status = 'joined', joined_at = NOW()
+		WHERE room_participants.status <> 'joined'
+	`
+
+	_, err := r.db.ExecContext(ctx, query, roomID, userID, RoleMember)
+	if err != nil {
+		return fmt.Errorf("failed to join room: %w", err)
+	}
+
+	return nil
+}
+
+// ReInvite invites a user again, resetting a declined or still-pending invite to invited
+// A fresh row is inserted when none exists. The target's current invite preference is checked
+// against the room creator; returns false when the preference forbids the invite or the user already joined
+func (r *RoomRepository) ReInvite(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO room_participants (room_id, user_id, status, joined_at)
+		SELECT ws.id, p.id, 'invited', NULL
+		FROM watch_sessions ws
+		JOIN profiles p ON p.id = $2
+		WHERE ws.id = $1
+		AND (
+			p.invite_preference = 'everyone'
+			OR (
+				p.invite_preference = 'following'
+				AND EXISTS (
+					SELECT 1 FROM user_follows uf
+					WHERE uf.follower_id = p.id AND uf.following_id = ws.creator_id
+					AND uf.deleted_at IS NULL
+				)
+			)
+		)
+		ON CONFLICT (room_id, user_id)
+		DO UPDATE SET status = 'invited', joined_at = NULL, invited_at = NOW()
+		WHERE room_participants.status <> 'joined'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, roomID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to re-invite participant: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check re-invite result: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+const (
+	// DefaultRoomsLimit is the page size used when GetRoomsByUser gets no limit
+	DefaultRoomsLimit = 20
+	// MaxRoomsLimit caps the page size of GetRoomsByUser
+	MaxRoomsLimit = 100
+)
+
+// GetRoomsByUser retrieves a page of the rooms a user has joined, newest first
+// Pending invites are not included, and closed rooms are skipped unless includeClosed is set.
+// total is the number of matching rooms across all pages
+func (r *RoomRepository) GetRoomsByUser(ctx context.Context, userID uuid.UUID, includeClosed bool, limit, offset int) ([]Room, int, error) {
+	if limit <= 0 {
+		limit = DefaultRoomsLimit
+	}
+	if limit > MaxRoomsLimit {
+		limit = MaxRoomsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM watch_sessions ws
+		INNER JOIN room_participants rp ON ws.id = rp.room_id
+		WHERE rp.user_id = $1
+		AND rp.status = 'joined'
+		AND ($2 OR ws.status <> 'closed')
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, userID, includeClosed).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count rooms: %w", err)
+	}
+
+	// The id tiebreaker keeps pages disjoint when rooms share a created_at
 	query := `
 		SELECT DISTINCT ws.id, ws.creator_id, ws.name, ws.is_public, ws.status, ws.created_at, ws.updated_at, ws.completed_at
 		FROM watch_sessions ws
 		INNER JOIN room_participants rp ON ws.id = rp.room_id
 		WHERE rp.user_id = $1
-		ORDER BY ws.created_at DESC
+		AND rp.status = 'joined'
+		AND ($2 OR ws.status <> 'closed')
+		ORDER BY ws.created_at DESC, ws.id
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, includeClosed, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []Room
+	for rows.Next() {
+		var room Room
+		err := rows.Scan(
+			&room.ID,
+			&room.CreatorID,
+			&room.Name,
+			&room.IsPublic,
+			&room.Status,
+			&room.CreatedAt,
+			&room.UpdatedAt,
+			&room.CompletedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rooms: %w", err)
+	}
+
+	return rooms, total, nil
+}
+
+// GetPendingInvites retrieves the open rooms a user has been invited to but not yet joined, latest invite first
+func (r *RoomRepository) GetPendingInvites(ctx context.Context, userID uuid.UUID) ([]Room, error) {
+	query := `
+		SELECT ws.id, ws.creator_id, ws.name, ws.is_public, ws.status, ws.created_at, ws.updated_at, ws.completed_at
+		FROM watch_sessions ws
+		INNER JOIN room_participants rp ON ws.id = rp.room_id
+		WHERE rp.user_id = $1
+		AND rp.status = 'invited'
+		AND ws.status <> 'closed'
+		ORDER BY rp.invited_at DESC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rooms: %w", err)
+		return nil, fmt.Errorf("failed to get pending invites: %w", err)
 	}
 	defer rows.Close()
 
@@ -140,12 +338,41 @@ func (r *RoomRepository) GetRoomsByUser(ctx context.Context, userID uuid.UUID) (
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rooms: %w", err)
+		return nil, fmt.Errorf("error iterating pending invites: %w", err)
 	}
 
 	return rooms, nil
 }
 
+// RespondToInvite accepts or declines a user's pending invite to a room
+// Accepting marks the user as joined; declining removes the invite.
+// Returns false when the user has no pending invite to the room
+func (r *RoomRepository) RespondToInvite(ctx context.Context, roomID, userID uuid.UUID, accept bool) (bool, error) {
+	query := `
+		DELETE FROM room_participants
+		WHERE room_id = $1 AND user_id = $2 AND status = 'invited'
+	`
+	if accept {
+		query = `
+			UPDATE room_participants
+			SET status = 'joined', joined_at = NOW()
+			WHERE room_id = $1 AND user_id = $2 AND status = 'invited'
+		`
+	}
+
+	result, err := r.db.ExecContext(ctx, query, roomID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to respond to invite: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check invite response result: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
 // GetRoomByID retrieves a room by its ID
 func (r *RoomRepository) GetRoomByID(ctx context.Context, roomID uuid.UUID) (*Room, error) {
 	query := `
@@ -176,12 +403,59 @@ func (r *RoomRepository) GetRoomByID(ctx context.Context, roomID uuid.UUID) (*Ro
 	return &room, nil
 }
 
-// IsParticipant checks if a user is a participant in a room
+// CloseRoom archives a room by setting its status to closed
+func (r *RoomRepository) CloseRoom(ctx context.Context, roomID uuid.UUID) (*Room, error) {
+	query := `
+		UPDATE watch_sessions
+		SET status = 'closed'
+		WHERE id = $1
+		RETURNING id, creator_id, name, is_public, status, created_at, updated_at, completed_at
+	`
+
+	var room Room
+	err := r.db.QueryRowContext(ctx, query, roomID).Scan(
+		&room.ID,
+		&room.CreatorID,
+		&room.Name,
+		&room.IsPublic,
+		&room.Status,
+		&room.CreatedAt,
+		&room.UpdatedAt,
+		&room.CompletedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close room: %w", err)
+	}
+
+	return &room, nil
+}
+
+// SetRoomVisibility makes a room public or private
+func (r *RoomRepository) SetRoomVisibility(ctx context.Context, roomID uuid.UUID, isPublic bool) error {
+	query := `
+		UPDATE watch_sessions
+		SET is_public = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, roomID, isPublic); err != nil {
+		return fmt.Errorf("failed to set room visibility: %w", err)
+	}
+
+	return nil
+}
+
+// IsParticipant checks if a user may see a room: they joined it or have a pending invite to it
+// Only joined participants may vote; see SessionRepository.IsSessionParticipant
 func (r *RoomRepository) IsParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM room_participants
-			WHERE room_id = $1 AND user_id = $2
+			WHERE room_id = $1 AND user_id = $2 AND status <> 'declined'
 		)
 	`
 
@@ -193,3 +467,66 @@ func (r *RoomRepository) IsParticipant(ctx context.Context, roomID, userID uuid.
 
 	return exists, nil
 }
+
+// CountParticipants returns how many users are in a room, invited or joined
+func (r *RoomRepository) CountParticipants(ctx context.Context, roomID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM room_participants
+		WHERE room_id = $1
+		AND status <> 'declined'
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, roomID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count participants: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetParticipantRole returns a user's role in a room, or an empty string when they are not a participant
+func (r *RoomRepository) GetParticipantRole(ctx context.Context, roomID, userID uuid.UUID) (string, error) {
+	query := `
+		SELECT role
+		FROM room_participants
+		WHERE room_id = $1 AND user_id = $2
+	`
+
+	var role string
+	err := r.db.QueryRowContext(ctx, query, roomID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get participant role: %w", err)
+	}
+
+	return role, nil
+}
+
+// SetParticipantRole changes a participant's role; returns false when the user is not a participant
+func (r *RoomRepository) SetParticipantRole(ctx context.Context, roomID, userID uuid.UUID, role string) (bool, error) {
+	if !IsValidParticipantRole(role) {
+		return false, fmt.Errorf("invalid participant role %q", role)
+	}
+
+	query := `
+		UPDATE room_participants
+		SET role = $3
+		WHERE room_id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, roomID, userID, role)
+	if err != nil {
+		return false, fmt.Errorf("failed to set participant role: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check role update result: %w", err)
+	}
+
+	return rows > 0, nil
+}
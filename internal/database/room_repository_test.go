@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/google/uuid"
@@ -118,6 +120,57 @@ func TestRoomRepository_CreateRoom(t *testing.T) {
 			t.Errorf("Expected 1 participant, got %d", count)
 		}
 	})
+
+	t.Run("adds a large member list in one insert", func(t *testing.T) {
+		var memberIDs []uuid.UUID
+		for i := 0; i < 40; i++ {
+			id := uuid.New()
+			testDB.SeedProfile(t, id, fmt.Sprintf("bulk_member_%d", i))
+			memberIDs = append(memberIDs, id)
+		}
+
+		room, err := repo.CreateRoom(ctx, creatorID, "Big Room", false, memberIDs)
+		if err != nil {
+			t.Fatalf("CreateRoom failed: %v", err)
+		}
+
+		var count int
+		err = testDB.DB.QueryRow("SELECT COUNT(*) FROM room_participants WHERE room_id = $1", room.ID).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to count participants: %v", err)
+		}
+
+		if count != len(memberIDs)+1 {
+			t.Errorf("Expected %d participants, got %d", len(memberIDs)+1, count)
+		}
+
+		for _, id := range memberIDs {
+			isParticipant, err := repo.IsParticipant(ctx, room.ID, id)
+			if err != nil {
+				t.Fatalf("IsParticipant failed: %v", err)
+			}
+			if !isParticipant {
+				t.Errorf("Expected %v to be a participant", id)
+			}
+		}
+	})
+
+	t.Run("dedupes creator and repeated members in the list", func(t *testing.T) {
+		room, err := repo.CreateRoom(ctx, creatorID, "Dedup Room", false, []uuid.UUID{memberID, creatorID, memberID})
+		if err != nil {
+			t.Fatalf("CreateRoom failed: %v", err)
+		}
+
+		var count int
+		err = testDB.DB.QueryRow("SELECT COUNT(*) FROM room_participants WHERE room_id = $1", room.ID).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to count participants: %v", err)
+		}
+
+		if count != 2 {
+			t.Errorf("Expected 2 participants, got %d", count)
+		}
+	})
 }
 
 func TestRoomRepository_AddParticipant(t *testing.T) {
@@ -141,7 +194,7 @@ func TestRoomRepository_AddParticipant(t *testing.T) {
 	}
 
 	t.Run("successfully adds participant", func(t *testing.T) {
-		err := repo.AddParticipant(ctx, room.ID, memberID)
+		err := repo.AddParticipant(ctx, room.ID, memberID, "")
 		if err != nil {
 			t.Fatalf("AddParticipant failed: %v", err)
 		}
@@ -158,7 +211,7 @@ func TestRoomRepository_AddParticipant(t *testing.T) {
 
 	t.Run("handles duplicate participant gracefully", func(t *testing.T) {
 		// Try adding the same participant again
-		err := repo.AddParticipant(ctx, room.ID, memberID)
+		err := repo.AddParticipant(ctx, room.ID, memberID, "")
 		if err != nil {
 			t.Errorf("AddParticipant should handle duplicates: %v", err)
 		}
@@ -166,7 +219,7 @@ func TestRoomRepository_AddParticipant(t *testing.T) {
 
 	t.Run("fails when room doesn't exist", func(t *testing.T) {
 		nonExistentRoomID := uuid.New()
-		err := repo.AddParticipant(ctx, nonExistentRoomID, memberID)
+		err := repo.AddParticipant(ctx, nonExistentRoomID, memberID, "")
 		if err == nil {
 			t.Error("Expected AddParticipant to fail with non-existent room")
 		}
@@ -174,11 +227,152 @@ func TestRoomRepository_AddParticipant(t *testing.T) {
 
 	t.Run("fails when user doesn't exist", func(t *testing.T) {
 		nonExistentUserID := uuid.New()
-		err := repo.AddParticipant(ctx, room.ID, nonExistentUserID)
+		err := repo.AddParticipant(ctx, room.ID, nonExistentUserID, "")
 		if err == nil {
 			t.Error("Expected AddParticipant to fail with non-existent user")
 		}
 	})
+
+	t.Run("defaults to the viewer role", func(t *testing.T) {
+		role, err := repo.GetParticipantRole(ctx, room.ID, memberID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != RoleMember {
+			t.Errorf("Expected role '%s', got '%s'", RoleMember, role)
+		}
+	})
+
+	t.Run("adds a participant with an explicit role", func(t *testing.T) {
+		adminID := uuid.New()
+		testDB.SeedProfile(t, adminID, "admin")
+
+		if err := repo.AddParticipant(ctx, room.ID, adminID, RoleModerator); err != nil {
+			t.Fatalf("AddParticipant failed: %v", err)
+		}
+
+		role, err := repo.GetParticipantRole(ctx, room.ID, adminID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != RoleModerator {
+			t.Errorf("Expected role '%s', got '%s'", RoleModerator, role)
+		}
+	})
+
+	t.Run("rejects an unknown role", func(t *testing.T) {
+		if err := repo.AddParticipant(ctx, room.ID, memberID, "superuser"); err == nil {
+			t.Error("Expected AddParticipant to fail with an unknown role")
+		}
+	})
+}
+
+func TestCanInvite(t *testing.T) {
+	tests := []struct {
+		role string
+		want bool
+	}{
+		{RoleOwner, true},
+		{RoleModerator, true},
+		{RoleMember, false},
+		{"admin", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanInvite(tt.role); got != tt.want {
+			t.Errorf("CanInvite(%q) = %v, want %v", tt.role, got, tt.want)
+		}
+		if tt.want && !IsValidParticipantRole(tt.role) {
+			t.Errorf("expected %q to be a valid participant role", tt.role)
+		}
+	}
+
+	if IsValidParticipantRole("viewer") {
+		t.Error("expected the legacy viewer role to be rejected")
+	}
+}
+
+func TestRoomRepository_ParticipantRoles(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create a room with one initial member
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	memberID := uuid.New()
+	testDB.SeedProfile(t, memberID, "member")
+
+	outsiderID := uuid.New()
+	testDB.SeedProfile(t, outsiderID, "outsider")
+
+	room, err := repo.CreateRoom(ctx, creatorID, "Roles Room", false, []uuid.UUID{memberID})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	t.Run("creator is the owner", func(t *testing.T) {
+		role, err := repo.GetParticipantRole(ctx, room.ID, creatorID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != RoleOwner {
+			t.Errorf("Expected role '%s', got '%s'", RoleOwner, role)
+		}
+	})
+
+	t.Run("initial members are viewers", func(t *testing.T) {
+		role, err := repo.GetParticipantRole(ctx, room.ID, memberID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != RoleMember {
+			t.Errorf("Expected role '%s', got '%s'", RoleMember, role)
+		}
+	})
+
+	t.Run("returns empty role for non-participant", func(t *testing.T) {
+		role, err := repo.GetParticipantRole(ctx, room.ID, outsiderID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != "" {
+			t.Errorf("Expected empty role, got '%s'", role)
+		}
+	})
+
+	t.Run("promotes a participant", func(t *testing.T) {
+		updated, err := repo.SetParticipantRole(ctx, room.ID, memberID, RoleModerator)
+		if err != nil {
+			t.Fatalf("SetParticipantRole failed: %v", err)
+		}
+		if !updated {
+			t.Fatal("Expected the role to be updated")
+		}
+
+		role, err := repo.GetParticipantRole(ctx, room.ID, memberID)
+		if err != nil {
+			t.Fatalf("GetParticipantRole failed: %v", err)
+		}
+		if role != RoleModerator {
+			t.Errorf("Expected role '%s', got '%s'", RoleModerator, role)
+		}
+	})
+
+	t.Run("reports a non-participant", func(t *testing.T) {
+		updated, err := repo.SetParticipantRole(ctx, room.ID, outsiderID, RoleModerator)
+		if err != nil {
+			t.Fatalf("SetParticipantRole failed: %v", err)
+		}
+		if updated {
+			t.Error("Expected no update for a non-participant")
+		}
+	})
 }
 
 func TestRoomRepository_GetRoomsByUser(t *testing.T) {
@@ -197,7 +391,7 @@ func TestRoomRepository_GetRoomsByUser(t *testing.T) {
 	testDB.SeedProfile(t, user2ID, "user2")
 
 	t.Run("returns empty list for user with no rooms", func(t *testing.T) {
-		rooms, err := repo.GetRoomsByUser(ctx, user1ID)
+		rooms, _, err := repo.GetRoomsByUser(ctx, user1ID, false, 0, 0)
 		if err != nil {
 			t.Fatalf("GetRoomsByUser failed: %v", err)
 		}
@@ -213,7 +407,7 @@ func TestRoomRepository_GetRoomsByUser(t *testing.T) {
 		room2, _ := repo.CreateRoom(ctx, user2ID, "User2's Room", true, []uuid.UUID{user1ID})
 
 		// Get rooms for user1
-		rooms, err := repo.GetRoomsByUser(ctx, user1ID)
+		rooms, _, err := repo.GetRoomsByUser(ctx, user1ID, false, 0, 0)
 		if err != nil {
 			t.Fatalf("GetRoomsByUser failed: %v", err)
 		}
@@ -235,7 +429,7 @@ func TestRoomRepository_GetRoomsByUser(t *testing.T) {
 		// Create room without user1
 		repo.CreateRoom(ctx, user2ID, "Private Room", false, []uuid.UUID{})
 
-		rooms, err := repo.GetRoomsByUser(ctx, user1ID)
+		rooms, _, err := repo.GetRoomsByUser(ctx, user1ID, false, 0, 0)
 		if err != nil {
 			t.Fatalf("GetRoomsByUser failed: %v", err)
 		}
@@ -245,6 +439,318 @@ func TestRoomRepository_GetRoomsByUser(t *testing.T) {
 			t.Errorf("Expected 2 rooms, got %d", len(rooms))
 		}
 	})
+
+	t.Run("doesn't return rooms with a pending invite", func(t *testing.T) {
+		room, _ := repo.CreateRoom(ctx, user2ID, "Invite Room", false, []uuid.UUID{})
+		if err := repo.AddParticipant(ctx, room.ID, user1ID, RoleMember); err != nil {
+			t.Fatalf("AddParticipant failed: %v", err)
+		}
+
+		rooms, _, err := repo.GetRoomsByUser(ctx, user1ID, false, 0, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+
+		if len(rooms) != 2 {
+			t.Errorf("Expected 2 rooms, got %d", len(rooms))
+		}
+	})
+}
+
+func TestRoomRepository_GetRoomsByUser_Pagination(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "busy_host")
+
+	// Room i was created i minutes ago, except that rooms 10 and 11 share a timestamp
+	// so the id tiebreaker decides their order
+	type seededRoom struct {
+		id         uuid.UUID
+		minutesAgo int
+	}
+	var seeded []seededRoom
+	for i := 0; i < 25; i++ {
+		room, err := repo.CreateRoom(ctx, userID, fmt.Sprintf("Room %d", i), false, nil)
+		if err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+
+		minutesAgo := i
+		if i == 11 {
+			minutesAgo = 10
+		}
+		_, err = testDB.DB.Exec(
+			`UPDATE watch_sessions SET created_at = date_trunc('second', NOW()) - make_interval(mins => $2) WHERE id = $1`,
+			room.ID, minutesAgo,
+		)
+		if err != nil {
+			t.Fatalf("Failed to set created_at: %v", err)
+		}
+		seeded = append(seeded, seededRoom{id: room.ID, minutesAgo: minutesAgo})
+	}
+
+	sort.Slice(seeded, func(i, j int) bool {
+		if seeded[i].minutesAgo != seeded[j].minutesAgo {
+			return seeded[i].minutesAgo < seeded[j].minutesAgo
+		}
+		return seeded[i].id.String() < seeded[j].id.String()
+	})
+
+	firstPage, total, err := repo.GetRoomsByUser(ctx, userID, false, 20, 0)
+	if err != nil {
+		t.Fatalf("GetRoomsByUser failed: %v", err)
+	}
+	secondPage, secondTotal, err := repo.GetRoomsByUser(ctx, userID, false, 20, 20)
+	if err != nil {
+		t.Fatalf("GetRoomsByUser failed: %v", err)
+	}
+
+	if total != 25 || secondTotal != 25 {
+		t.Errorf("Expected total 25 on both pages, got %d and %d", total, secondTotal)
+	}
+	if len(firstPage) != 20 || len(secondPage) != 5 {
+		t.Fatalf("Expected pages of 20 and 5, got %d and %d", len(firstPage), len(secondPage))
+	}
+
+	// Together the pages list every room exactly once, newest first
+	pages := append(firstPage, secondPage...)
+	for i, room := range pages {
+		if room.ID != seeded[i].id {
+			t.Errorf("Position %d: expected room %s, got %s", i, seeded[i].id, room.ID)
+		}
+	}
+
+	t.Run("defaults and caps the page size", func(t *testing.T) {
+		rooms, _, err := repo.GetRoomsByUser(ctx, userID, false, 0, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+		if len(rooms) != DefaultRoomsLimit {
+			t.Errorf("Expected %d rooms by default, got %d", DefaultRoomsLimit, len(rooms))
+		}
+
+		rooms, _, err = repo.GetRoomsByUser(ctx, userID, false, MaxRoomsLimit+1, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+		if len(rooms) != 25 {
+			t.Errorf("Expected all 25 rooms under the cap, got %d", len(rooms))
+		}
+	})
+}
+
+func TestRoomRepository_RespondToInvite(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	inviteeID := uuid.New()
+	testDB.SeedProfile(t, inviteeID, "invitee")
+
+	room, err := repo.CreateRoom(ctx, creatorID, "Invite Room", false, []uuid.UUID{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	t.Run("pending invite is listed until accepted", func(t *testing.T) {
+		if err := repo.AddParticipant(ctx, room.ID, inviteeID, RoleMember); err != nil {
+			t.Fatalf("AddParticipant failed: %v", err)
+		}
+
+		invites, err := repo.GetPendingInvites(ctx, inviteeID)
+		if err != nil {
+			t.Fatalf("GetPendingInvites failed: %v", err)
+		}
+		if len(invites) != 1 || invites[0].ID != room.ID {
+			t.Fatalf("Expected one pending invite to the room, got %v", invites)
+		}
+
+		responded, err := repo.RespondToInvite(ctx, room.ID, inviteeID, true)
+		if err != nil {
+			t.Fatalf("RespondToInvite failed: %v", err)
+		}
+		if !responded {
+			t.Fatal("Expected the pending invite to be accepted")
+		}
+
+		invites, err = repo.GetPendingInvites(ctx, inviteeID)
+		if err != nil {
+			t.Fatalf("GetPendingInvites failed: %v", err)
+		}
+		if len(invites) != 0 {
+			t.Errorf("Expected no pending invites, got %d", len(invites))
+		}
+
+		rooms, _, err := repo.GetRoomsByUser(ctx, inviteeID, false, 0, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+		if len(rooms) != 1 {
+			t.Errorf("Expected 1 room after accepting, got %d", len(rooms))
+		}
+	})
+
+	t.Run("joined user has nothing to respond to", func(t *testing.T) {
+		responded, err := repo.RespondToInvite(ctx, room.ID, inviteeID, false)
+		if err != nil {
+			t.Fatalf("RespondToInvite failed: %v", err)
+		}
+		if responded {
+			t.Error("Expected no pending invite for a joined user")
+		}
+
+		isParticipant, err := repo.IsParticipant(ctx, room.ID, inviteeID)
+		if err != nil {
+			t.Fatalf("IsParticipant failed: %v", err)
+		}
+		if !isParticipant {
+			t.Error("Expected joined user to remain a participant")
+		}
+	})
+
+	t.Run("declining removes the invite", func(t *testing.T) {
+		declinerID := uuid.New()
+		testDB.SeedProfile(t, declinerID, "decliner")
+
+		if err := repo.AddParticipant(ctx, room.ID, declinerID, RoleMember); err != nil {
+			t.Fatalf("AddParticipant failed: %v", err)
+		}
+
+		responded, err := repo.RespondToInvite(ctx, room.ID, declinerID, false)
+		if err != nil {
+			t.Fatalf("RespondToInvite failed: %v", err)
+		}
+		if !responded {
+			t.Fatal("Expected the pending invite to be declined")
+		}
+
+		isParticipant, err := repo.IsParticipant(ctx, room.ID, declinerID)
+		if err != nil {
+			t.Fatalf("IsParticipant failed: %v", err)
+		}
+		if isParticipant {
+			t.Error("Expected declined invite to be removed")
+		}
+	})
+}
+
+func TestRoomRepository_CloseRoom(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create user and rooms
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	openRoom, err := repo.CreateRoom(ctx, creatorID, "Open Room", false, []uuid.UUID{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	closedRoom, err := repo.CreateRoom(ctx, creatorID, "Closed Room", false, []uuid.UUID{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	t.Run("sets status to closed", func(t *testing.T) {
+		room, err := repo.CloseRoom(ctx, closedRoom.ID)
+		if err != nil {
+			t.Fatalf("CloseRoom failed: %v", err)
+		}
+
+		if room == nil {
+			t.Fatal("Expected room to be returned")
+		}
+
+		if room.Status != "closed" {
+			t.Errorf("Expected status 'closed', got %s", room.Status)
+		}
+	})
+
+	t.Run("excludes closed rooms by default", func(t *testing.T) {
+		rooms, _, err := repo.GetRoomsByUser(ctx, creatorID, false, 0, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+
+		if len(rooms) != 1 {
+			t.Fatalf("Expected 1 room, got %d", len(rooms))
+		}
+
+		if rooms[0].ID != openRoom.ID {
+			t.Errorf("Expected open room %v, got %v", openRoom.ID, rooms[0].ID)
+		}
+	})
+
+	t.Run("includes closed rooms when requested", func(t *testing.T) {
+		rooms, _, err := repo.GetRoomsByUser(ctx, creatorID, true, 0, 0)
+		if err != nil {
+			t.Fatalf("GetRoomsByUser failed: %v", err)
+		}
+
+		if len(rooms) != 2 {
+			t.Errorf("Expected 2 rooms, got %d", len(rooms))
+		}
+	})
+
+	t.Run("returns nil for non-existent room", func(t *testing.T) {
+		room, err := repo.CloseRoom(ctx, uuid.New())
+		if err != nil {
+			t.Fatalf("CloseRoom failed: %v", err)
+		}
+
+		if room != nil {
+			t.Error("Expected nil for non-existent room")
+		}
+	})
+}
+
+func TestRoomRepository_SetRoomVisibility(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	room, err := repo.CreateRoom(ctx, creatorID, "Private Room", false, []uuid.UUID{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	for _, isPublic := range []bool{true, false} {
+		if err := repo.SetRoomVisibility(ctx, room.ID, isPublic); err != nil {
+			t.Fatalf("SetRoomVisibility(%v) failed: %v", isPublic, err)
+		}
+
+		found, err := repo.GetRoomByID(ctx, room.ID)
+		if err != nil {
+			t.Fatalf("GetRoomByID failed: %v", err)
+		}
+
+		if found.IsPublic != isPublic {
+			t.Errorf("Expected is_public %v, got %v", isPublic, found.IsPublic)
+		}
+	}
 }
 
 func TestRoomRepository_GetRoomByID(t *testing.T) {
@@ -368,3 +874,157 @@ func TestRoomRepository_IsParticipant(t *testing.T) {
 		}
 	})
 }
+
+func TestRoomRepository_CountParticipants(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	roomID := testDB.SeedWatchSession(t, creatorID, "Counted Room", false)
+	testDB.SeedRoomParticipant(t, roomID, creatorID, "owner", "joined")
+
+	for status, username := range map[string]string{"joined": "joined_user", "invited": "invited_user", "declined": "declined_user"} {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, username)
+		testDB.SeedRoomParticipant(t, roomID, userID, "member", status)
+	}
+
+	count, err := repo.CountParticipants(ctx, roomID)
+	if err != nil {
+		t.Fatalf("CountParticipants failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 invited or joined participants, got %d", count)
+	}
+}
+
+func TestRoomRepository_ReInvite(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewRoomRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create creator and room
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	roomID := testDB.SeedWatchSession(t, creatorID, "Movie Night", false)
+
+	participantStatus := func(userID uuid.UUID) string {
+		t.Helper()
+		var status string
+		err := testDB.DB.QueryRow(
+			`SELECT status FROM room_participants WHERE room_id = $1 AND user_id = $2`,
+			roomID, userID,
+		).Scan(&status)
+		if err != nil {
+			t.Fatalf("Failed to read participant status: %v", err)
+		}
+		return status
+	}
+
+	setPreference := func(userID uuid.UUID, preference string) {
+		t.Helper()
+		_, err := testDB.DB.Exec(`UPDATE profiles SET invite_preference = $2 WHERE id = $1`, userID, preference)
+		if err != nil {
+			t.Fatalf("Failed to set invite preference: %v", err)
+		}
+	}
+
+	t.Run("re-invites a declined user after they open their preferences", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "declined_user")
+		testDB.SeedRoomParticipant(t, roomID, userID, "member", "declined")
+		setPreference(userID, "none")
+
+		reinvited, err := repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if reinvited {
+			t.Error("Expected re-invite to be refused while preference is 'none'")
+		}
+		if status := participantStatus(userID); status != "declined" {
+			t.Errorf("Expected status to stay 'declined', got '%s'", status)
+		}
+
+		setPreference(userID, "everyone")
+
+		reinvited, err = repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if !reinvited {
+			t.Fatal("Expected re-invite to succeed after opening preferences")
+		}
+		if status := participantStatus(userID); status != "invited" {
+			t.Errorf("Expected status 'invited', got '%s'", status)
+		}
+	})
+
+	t.Run("respects following-only preference", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "picky_user")
+		testDB.SeedRoomParticipant(t, roomID, userID, "member", "declined")
+		setPreference(userID, "following")
+
+		reinvited, err := repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if reinvited {
+			t.Error("Expected re-invite to be refused when user doesn't follow the creator")
+		}
+
+		testDB.SeedFollow(t, userID, creatorID)
+
+		reinvited, err = repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if !reinvited {
+			t.Error("Expected re-invite to succeed once user follows the creator")
+		}
+	})
+
+	t.Run("inserts a fresh invite when none exists", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "new_user")
+
+		reinvited, err := repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if !reinvited {
+			t.Fatal("Expected a fresh invite to be created")
+		}
+		if status := participantStatus(userID); status != "invited" {
+			t.Errorf("Expected status 'invited', got '%s'", status)
+		}
+	})
+
+	t.Run("does not reset a user who already joined", func(t *testing.T) {
+		userID := uuid.New()
+		testDB.SeedProfile(t, userID, "joined_user")
+		testDB.SeedRoomParticipant(t, roomID, userID, "member", "joined")
+
+		reinvited, err := repo.ReInvite(ctx, roomID, userID)
+		if err != nil {
+			t.Fatalf("ReInvite failed: %v", err)
+		}
+		if reinvited {
+			t.Error("Expected joined user not to be re-invited")
+		}
+		if status := participantStatus(userID); status != "joined" {
+			t.Errorf("Expected status to stay 'joined', got '%s'", status)
+		}
+	})
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,11 +15,35 @@ type WatchSession struct {
 	ID          uuid.UUID  `json:"id"`
 	CreatorID   uuid.UUID  `json:"creator_id"`
 	Status      string     `json:"status"`
-	CreatedAt   string     `json:"created_at"`
-	UpdatedAt   string     `json:"updated_at"`
-	CompletedAt *string    `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 }
 
+// IsExpired reports whether a session's voting deadline has passed
+// Sessions without a deadline never expire
+func IsExpired(session *WatchSession) bool {
+	return session.ExpiresAt != nil && !time.Now().Before(*session.ExpiresAt)
+}
+
+// UnfinishedSession is an active session with candidates the user has not voted on yet
+type UnfinishedSession struct {
+	SessionID      uuid.UUID `json:"session_id"`
+	Name           *string   `json:"name,omitempty"`
+	RemainingCount int       `json:"remaining_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ErrSessionNotFound is returned when a session does not exist
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrNotSessionCreator is returned when someone other than the creator tries to delete a session
+var ErrNotSessionCreator = errors.New("only the session creator can delete the session")
+
+// ErrUserNotFound is returned when the user being added to a session has no profile
+var ErrUserNotFound = errors.New("user not found")
+
 // SessionRepository handles session-related database operations
 type SessionRepository struct {
 	db *sql.DB
@@ -29,34 +55,65 @@ func NewSessionRepository(db *sql.DB) *SessionRepository {
 }
 
 // CreateSession creates a new watch session for a user
-func (r *SessionRepository) CreateSession(ctx context.Context, creatorID uuid.UUID) (*WatchSession, error) {
+// A nil expiresAt creates a session that never expires
+func (r *SessionRepository) CreateSession(ctx context.Context, creatorID uuid.UUID, expiresAt *time.Time) (*WatchSession, error) {
+	session, _, err := r.CreateSessionWithIdempotencyKey(ctx, creatorID, expiresAt, nil, "")
+	return session, err
+}
+
+// CreateSessionWithIdempotencyKey creates a session like CreateSession, reserving the creator's idempotency key in the same transaction
+// When cloneFrom is set, that session's candidate deck is copied in the same transaction and the number copied is returned.
+// Returns ErrIdempotencyKeyUsed, creating nothing, when another request already used the key
+func (r *SessionRepository) CreateSessionWithIdempotencyKey(ctx context.Context, creatorID uuid.UUID, expiresAt *time.Time, cloneFrom *uuid.UUID, idempotencyKey string) (*WatchSession, int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO watch_sessions (creator_id, status)
-		VALUES ($1, 'active')
-		RETURNING id, creator_id, status, created_at, updated_at, completed_at
+		INSERT INTO watch_sessions (creator_id, status, expires_at)
+		VALUES ($1, 'active', $2)
+		RETURNING id, creator_id, status, created_at, updated_at, completed_at, expires_at
 	`
 
 	var session WatchSession
-	err := r.db.QueryRowContext(ctx, query, creatorID).Scan(
+	err = tx.QueryRowContext(ctx, query, creatorID, expiresAt).Scan(
 		&session.ID,
 		&session.CreatorID,
 		&session.Status,
 		&session.CreatedAt,
 		&session.UpdatedAt,
 		&session.CompletedAt,
+		&session.ExpiresAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+		return nil, 0, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return &session, nil
+	cloned := 0
+	if cloneFrom != nil {
+		if cloned, err = cloneCandidates(ctx, tx, *cloneFrom, session.ID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err = reserveIdempotencyKey(ctx, tx, creatorID, IdempotencyScopeSession, idempotencyKey, session.ID); err != nil {
+		return nil, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &session, cloned, nil
 }
 
 // GetSessionByID retrieves a session by its ID
 func (r *SessionRepository) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*WatchSession, error) {
 	query := `
-		SELECT id, creator_id, status, created_at, updated_at, completed_at
+		SELECT id, creator_id, status, created_at, updated_at, completed_at, expires_at
 		FROM watch_sessions
 		WHERE id = $1
 	`
@@ -69,6 +126,7 @@ func (r *SessionRepository) GetSessionByID(ctx context.Context, sessionID uuid.U
 		&session.CreatedAt,
 		&session.UpdatedAt,
 		&session.CompletedAt,
+		&session.ExpiresAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -81,23 +139,30 @@ func (r *SessionRepository) GetSessionByID(ctx context.Context, sessionID uuid.U
 	return &session, nil
 }
 
-// CompleteSession marks a session as completed
+// CompleteSession marks a session as completed and snapshots who took part in it
 func (r *SessionRepository) CompleteSession(ctx context.Context, sessionID uuid.UUID) (*WatchSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE watch_sessions
 		SET status = 'completed'
 		WHERE id = $1
-		RETURNING id, creator_id, status, created_at, updated_at, completed_at
+		RETURNING id, creator_id, status, created_at, updated_at, completed_at, expires_at
 	`
 
 	var session WatchSession
-	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(
+	err = tx.QueryRowContext(ctx, query, sessionID).Scan(
 		&session.ID,
 		&session.CreatorID,
 		&session.Status,
 		&session.CreatedAt,
 		&session.UpdatedAt,
 		&session.CompletedAt,
+		&session.ExpiresAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -107,5 +172,319 @@ func (r *SessionRepository) CompleteSession(ctx context.Context, sessionID uuid.
 		return nil, fmt.Errorf("failed to complete session: %w", err)
 	}
 
+	if _, err := snapshotParticipants(ctx, tx, sessionID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &session, nil
 }
+
+// SnapshotParticipants records everyone who has voted in a session and returns how many were added
+// Users already in the snapshot keep their original snapshot time
+func (r *SessionRepository) SnapshotParticipants(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added, err := snapshotParticipants(ctx, tx, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added, nil
+}
+
+// snapshotParticipants copies the session's voters into session_snapshots within tx
+// Guests have no profile to list, so only account holders are recorded
+func snapshotParticipants(ctx context.Context, tx *sql.Tx, sessionID uuid.UUID) (int, error) {
+	query := `
+		INSERT INTO session_snapshots (session_id, user_id)
+		SELECT DISTINCT sv.session_id, sv.user_id
+		FROM session_votes sv
+		INNER JOIN profiles p ON p.id = sv.user_id
+		WHERE sv.session_id = $1
+		ON CONFLICT (session_id, user_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot participants: %w", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(added), nil
+}
+
+// GetSnapshot retrieves the participants recorded for a session, ordered by username
+// Returns an empty list if the session has not been snapshotted
+func (r *SessionRepository) GetSnapshot(ctx context.Context, sessionID uuid.UUID) ([]Profile, error) {
+	query := `
+		SELECT p.id, p.username, p.invite_preference, p.created_at, p.updated_at
+		FROM session_snapshots ss
+		INNER JOIN profiles p ON p.id = ss.user_id
+		WHERE ss.session_id = $1
+		ORDER BY p.username NULLS LAST, p.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []Profile{}
+	for rows.Next() {
+		var profile Profile
+		err := rows.Scan(
+			&profile.UserID,
+			&profile.Username,
+			&profile.InvitePreference,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot participant: %w", err)
+		}
+		participants = append(participants, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot participants: %w", err)
+	}
+
+	return participants, nil
+}
+
+// DeleteSession removes a session and its votes if creatorID created it
+// Candidates, participants and idempotency keys go with the session via ON DELETE CASCADE
+func (r *SessionRepository) DeleteSession(ctx context.Context, sessionID, creatorID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the session so a concurrent vote can't land between the two deletes
+	var ownerID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT creator_id
+		FROM watch_sessions
+		WHERE id = $1
+		FOR UPDATE
+	`, sessionID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if ownerID != creatorID {
+		return ErrNotSessionCreator
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM session_votes WHERE session_id = $1`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session votes: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM watch_sessions WHERE id = $1`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnfinishedSessions lists the user's active sessions that still have candidates they haven't voted on
+// A session's candidates are its queued titles plus any title its members have voted on; sessions
+// without candidates, or where the user has voted on all of them, are left out
+func (r *SessionRepository) GetUnfinishedSessions(ctx context.Context, userID uuid.UUID) ([]UnfinishedSession, error) {
+	query := `
+		SELECT ws.id, ws.name, COUNT(DISTINCT candidate.media_id) AS remaining, ws.created_at
+		FROM watch_sessions ws
+		JOIN (
+			SELECT session_id, media_id FROM session_candidates
+			UNION
+			SELECT session_id, media_id FROM session_votes
+		) candidate ON candidate.session_id = ws.id
+		WHERE ws.status = 'active'
+		AND (
+			ws.creator_id = $1
+			OR EXISTS (
+				SELECT 1 FROM room_participants rp
+				WHERE rp.room_id = ws.id AND rp.user_id = $1 AND rp.status = 'joined'
+			)
+			OR EXISTS (
+				SELECT 1 FROM session_votes own
+				WHERE own.session_id = ws.id AND own.user_id = $1
+			)
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM session_votes mine
+			WHERE mine.session_id = candidate.session_id
+			AND mine.media_id = candidate.media_id
+			AND mine.user_id = $1
+		)
+		GROUP BY ws.id, ws.name, ws.created_at
+		ORDER BY remaining DESC, ws.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unfinished sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []UnfinishedSession
+	for rows.Next() {
+		var session UnfinishedSession
+		err := rows.Scan(
+			&session.SessionID,
+			&session.Name,
+			&session.RemainingCount,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unfinished session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// IsSessionMember reports whether the user created the session, joined it, or has voted in it
+func (r *SessionRepository) IsSessionMember(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM watch_sessions
+			WHERE id = $1 AND creator_id = $2
+		) OR EXISTS(
+			SELECT 1 FROM room_participants
+			WHERE room_id = $1 AND user_id = $2 AND status = 'joined'
+		) OR EXISTS(
+			SELECT 1 FROM session_votes
+			WHERE session_id = $1 AND user_id = $2
+		)
+	`
+
+	var member bool
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&member)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session membership: %w", err)
+	}
+
+	return member, nil
+}
+
+// AddSessionParticipant invites a user to vote in a session on behalf of its creator
+// Sessions share watch_sessions with rooms, so participants live in room_participants. The invite stays
+// pending until the user accepts it, and an existing invited, joined or declined row is left as it is.
+// The target's invite preference is checked against the creator as in RoomRepository.ReInvite; returns false
+// when it forbids the invite, and ErrUserNotFound when the user has no profile
+func (r *SessionRepository) AddSessionParticipant(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	query := `
+		WITH target AS (
+			SELECT ws.id AS room_id, p.id AS user_id, (
+				p.invite_preference = 'everyone'
+				OR (
+					p.invite_preference = 'following'
+					AND EXISTS (
+						SELECT 1 FROM user_follows uf
+						WHERE uf.follower_id = p.id AND uf.following_id = ws.creator_id
+						AND uf.deleted_at IS NULL
+					)
+				)
+			) AS allowed
+			FROM watch_sessions ws
+			JOIN profiles p ON p.id = $2
+			WHERE ws.id = $1
+		), invited AS (
+			INSERT INTO room_participants (room_id, user_id, status)
+			SELECT room_id, user_id, 'invited'
+			FROM target
+			WHERE allowed
+			ON CONFLICT (room_id, user_id) DO NOTHING
+		)
+		SELECT allowed FROM target
+	`
+
+	var allowed bool
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&allowed)
+	if err == sql.ErrNoRows {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to add session participant: %w", err)
+	}
+
+	return allowed, nil
+}
+
+// IsSessionParticipant reports whether the user may vote in a session:
+// they created it or joined it. A pending invite must be accepted first
+func (r *SessionRepository) IsSessionParticipant(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM watch_sessions
+			WHERE id = $1 AND creator_id = $2
+		) OR EXISTS(
+			SELECT 1 FROM room_participants
+			WHERE room_id = $1 AND user_id = $2 AND status = 'joined'
+		)
+	`
+
+	var participant bool
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&participant)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session participant: %w", err)
+	}
+
+	return participant, nil
+}
+
+// CanAccessSession reports whether a user may read a session: it is public or they are a member
+// Unlike voting, a pending invite is enough, so invitees can look before accepting
+func (r *SessionRepository) CanAccessSession(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM watch_sessions
+			WHERE id = $1 AND (is_public = true OR creator_id = $2)
+		) OR EXISTS(
+			SELECT 1 FROM room_participants
+			WHERE room_id = $1 AND user_id = $2 AND status <> 'declined'
+		) OR EXISTS(
+			SELECT 1 FROM session_votes
+			WHERE session_id = $1 AND user_id = $2
+		)
+	`
+
+	var canAccess bool
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&canAccess)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session access: %w", err)
+	}
+
+	return canAccess, nil
+}
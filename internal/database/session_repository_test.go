@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/testutils"
@@ -21,7 +23,7 @@ func TestSessionRepository_CreateSession(t *testing.T) {
 	testDB.SeedProfile(t, creatorID, "session_creator")
 
 	t.Run("successfully creates a session", func(t *testing.T) {
-		session, err := repo.CreateSession(ctx, creatorID)
+		session, err := repo.CreateSession(ctx, creatorID, nil)
 		if err != nil {
 			t.Fatalf("CreateSession failed: %v", err)
 		}
@@ -42,34 +44,38 @@ func TestSessionRepository_CreateSession(t *testing.T) {
 			t.Errorf("Expected status 'active', got '%s'", session.Status)
 		}
 
-		if session.CreatedAt == "" {
+		if session.CreatedAt.IsZero() {
 			t.Error("Expected created_at to be set")
 		}
 
-		if session.UpdatedAt == "" {
+		if session.UpdatedAt.IsZero() {
 			t.Error("Expected updated_at to be set")
 		}
 
 		if session.CompletedAt != nil {
 			t.Error("Expected completed_at to be nil for new session")
 		}
+
+		if session.ExpiresAt != nil {
+			t.Error("Expected expires_at to be nil without a deadline")
+		}
 	})
 
 	t.Run("fails when creator doesn't exist", func(t *testing.T) {
 		nonExistentID := uuid.New()
-		_, err := repo.CreateSession(ctx, nonExistentID)
+		_, err := repo.CreateSession(ctx, nonExistentID, nil)
 		if err == nil {
 			t.Error("Expected CreateSession to fail with non-existent creator")
 		}
 	})
 
 	t.Run("allows creating multiple sessions for same creator", func(t *testing.T) {
-		session1, err := repo.CreateSession(ctx, creatorID)
+		session1, err := repo.CreateSession(ctx, creatorID, nil)
 		if err != nil {
 			t.Fatalf("CreateSession failed: %v", err)
 		}
 
-		session2, err := repo.CreateSession(ctx, creatorID)
+		session2, err := repo.CreateSession(ctx, creatorID, nil)
 		if err != nil {
 			t.Fatalf("CreateSession failed: %v", err)
 		}
@@ -78,6 +84,82 @@ func TestSessionRepository_CreateSession(t *testing.T) {
 			t.Error("Expected different session IDs for multiple sessions")
 		}
 	})
+
+	t.Run("stores the voting deadline", func(t *testing.T) {
+		expiresAt := time.Now().Add(90 * time.Minute)
+
+		session, err := repo.CreateSession(ctx, creatorID, &expiresAt)
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+
+		if session.ExpiresAt == nil {
+			t.Fatal("Expected expires_at to be set")
+		}
+		if !session.ExpiresAt.Round(time.Second).Equal(expiresAt.Round(time.Second)) {
+			t.Errorf("Expected expires_at %s, got %s", expiresAt, session.ExpiresAt)
+		}
+		if IsExpired(session) {
+			t.Error("Expected a session with a future deadline not to be expired")
+		}
+
+		fetched, err := repo.GetSessionByID(ctx, session.ID)
+		if err != nil {
+			t.Fatalf("GetSessionByID failed: %v", err)
+		}
+		if fetched.ExpiresAt == nil {
+			t.Error("Expected expires_at to be read back")
+		}
+	})
+
+	t.Run("clones a candidate deck with the session", func(t *testing.T) {
+		sourceID := testDB.SeedWatchSession(t, creatorID, "Last Week", false)
+		mediaID := testDB.SeedMediaItem(t, 7101, "movie", "Cloned With Session")
+		testDB.SeedVote(t, sourceID, creatorID, mediaID, "yes")
+
+		session, cloned, err := repo.CreateSessionWithIdempotencyKey(ctx, creatorID, nil, &sourceID, "")
+		if err != nil {
+			t.Fatalf("CreateSessionWithIdempotencyKey failed: %v", err)
+		}
+		if cloned != 1 {
+			t.Errorf("Expected 1 candidate cloned, got %d", cloned)
+		}
+
+		var count int
+		err = testDB.DB.QueryRow(
+			"SELECT COUNT(*) FROM session_candidates WHERE session_id = $1 AND media_id = $2",
+			session.ID, mediaID,
+		).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to count candidates: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected the voted title to be a candidate in the new session, got %d", count)
+		}
+	})
+}
+
+func TestIsExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		expected  bool
+	}{
+		{"no deadline never expires", nil, false},
+		{"future deadline is not expired", &future, false},
+		{"past deadline is expired", &past, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsExpired(&WatchSession{ExpiresAt: tt.expiresAt}); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
 }
 
 func TestSessionRepository_GetSessionByID(t *testing.T) {
@@ -92,7 +174,7 @@ func TestSessionRepository_GetSessionByID(t *testing.T) {
 	creatorID := uuid.New()
 	testDB.SeedProfile(t, creatorID, "session_creator")
 
-	createdSession, err := repo.CreateSession(ctx, creatorID)
+	createdSession, err := repo.CreateSession(ctx, creatorID, nil)
 	if err != nil {
 		t.Fatalf("Failed to create test session: %v", err)
 	}
@@ -145,7 +227,7 @@ func TestSessionRepository_CompleteSession(t *testing.T) {
 	creatorID := uuid.New()
 	testDB.SeedProfile(t, creatorID, "session_creator")
 
-	createdSession, err := repo.CreateSession(ctx, creatorID)
+	createdSession, err := repo.CreateSession(ctx, creatorID, nil)
 	if err != nil {
 		t.Fatalf("Failed to create test session: %v", err)
 	}
@@ -192,7 +274,7 @@ func TestSessionRepository_CompleteSession(t *testing.T) {
 
 	t.Run("can complete already completed session", func(t *testing.T) {
 		// Create another session
-		newSession, err := repo.CreateSession(ctx, creatorID)
+		newSession, err := repo.CreateSession(ctx, creatorID, nil)
 		if err != nil {
 			t.Fatalf("Failed to create session: %v", err)
 		}
@@ -219,6 +301,91 @@ func TestSessionRepository_CompleteSession(t *testing.T) {
 	})
 }
 
+func TestSessionRepository_SnapshotParticipants(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	aliceID := uuid.New()
+	testDB.SeedProfile(t, aliceID, "snapshot_alice")
+
+	bobID := uuid.New()
+	testDB.SeedProfile(t, bobID, "snapshot_bob")
+
+	carolID := uuid.New()
+	testDB.SeedProfile(t, carolID, "snapshot_carol")
+
+	// Joined but never voted, so not part of the snapshot
+	lurkerID := uuid.New()
+	testDB.SeedProfile(t, lurkerID, "snapshot_lurker")
+
+	sessionID := testDB.SeedWatchSession(t, aliceID, "Snapshot Session", false)
+	testDB.SeedRoomParticipant(t, sessionID, lurkerID, "member", "joined")
+
+	firstID := testDB.SeedMediaItem(t, 9301, "movie", "First Movie")
+	secondID := testDB.SeedMediaItem(t, 9302, "movie", "Second Movie")
+
+	testDB.SeedVote(t, sessionID, aliceID, firstID, "yes")
+	testDB.SeedVote(t, sessionID, aliceID, secondID, "no")
+	testDB.SeedVote(t, sessionID, bobID, firstID, "yes")
+	testDB.SeedVote(t, sessionID, carolID, secondID, "maybe")
+
+	t.Run("is empty before the session is completed", func(t *testing.T) {
+		participants, err := repo.GetSnapshot(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSnapshot failed: %v", err)
+		}
+		if len(participants) != 0 {
+			t.Errorf("Expected no participants, got %d", len(participants))
+		}
+	})
+
+	t.Run("completing the session snapshots every voter once", func(t *testing.T) {
+		if _, err := repo.CompleteSession(ctx, sessionID); err != nil {
+			t.Fatalf("CompleteSession failed: %v", err)
+		}
+
+		participants, err := repo.GetSnapshot(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSnapshot failed: %v", err)
+		}
+
+		if len(participants) != 3 {
+			t.Fatalf("Expected 3 participants, got %d", len(participants))
+		}
+
+		expected := []uuid.UUID{aliceID, bobID, carolID}
+		for i, participant := range participants {
+			if participant.UserID != expected[i] {
+				t.Errorf("Expected participant %d to be %s, got %s", i, expected[i], participant.UserID)
+			}
+		}
+	})
+
+	t.Run("snapshotting again adds only new voters", func(t *testing.T) {
+		added, err := repo.SnapshotParticipants(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("SnapshotParticipants failed: %v", err)
+		}
+		if added != 0 {
+			t.Errorf("Expected 0 participants added, got %d", added)
+		}
+
+		testDB.SeedVote(t, sessionID, lurkerID, firstID, "no")
+
+		added, err = repo.SnapshotParticipants(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("SnapshotParticipants failed: %v", err)
+		}
+		if added != 1 {
+			t.Errorf("Expected 1 participant added, got %d", added)
+		}
+	})
+}
+
 func TestSessionRepository_SessionLifecycle(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
@@ -233,7 +400,7 @@ func TestSessionRepository_SessionLifecycle(t *testing.T) {
 
 	t.Run("full session lifecycle", func(t *testing.T) {
 		// Step 1: Create session
-		session, err := repo.CreateSession(ctx, creatorID)
+		session, err := repo.CreateSession(ctx, creatorID, nil)
 		if err != nil {
 			t.Fatalf("Failed to create session: %v", err)
 		}
@@ -281,3 +448,395 @@ func TestSessionRepository_SessionLifecycle(t *testing.T) {
 		// Note: completed_at is not automatically set by the current implementation
 	})
 }
+
+func TestSessionRepository_DeleteSession(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "other")
+
+	sessionID := testDB.SeedWatchSession(t, creatorID, "Doomed Session", false)
+	mediaID := testDB.SeedMediaItem(t, 550, "movie", "Fight Club")
+	testDB.SeedVote(t, sessionID, creatorID, mediaID, "yes")
+	testDB.SeedVote(t, sessionID, otherID, mediaID, "yes")
+
+	t.Run("non-creator cannot delete", func(t *testing.T) {
+		err := repo.DeleteSession(ctx, sessionID, otherID)
+		if !errors.Is(err, ErrNotSessionCreator) {
+			t.Fatalf("Expected ErrNotSessionCreator, got %v", err)
+		}
+
+		session, err := repo.GetSessionByID(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSessionByID failed: %v", err)
+		}
+		if session == nil {
+			t.Error("Expected session to survive a non-creator delete")
+		}
+	})
+
+	t.Run("creator deletes the session and its votes", func(t *testing.T) {
+		if err := repo.DeleteSession(ctx, sessionID, creatorID); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+
+		session, err := repo.GetSessionByID(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSessionByID failed: %v", err)
+		}
+		if session != nil {
+			t.Error("Expected session to be deleted")
+		}
+
+		var votes int
+		if err := testDB.DB.QueryRow("SELECT COUNT(*) FROM session_votes WHERE session_id = $1", sessionID).Scan(&votes); err != nil {
+			t.Fatalf("Failed to count votes: %v", err)
+		}
+		if votes != 0 {
+			t.Errorf("Expected votes to be deleted, got %d", votes)
+		}
+	})
+
+	t.Run("returns not found for a missing session", func(t *testing.T) {
+		err := repo.DeleteSession(ctx, uuid.New(), creatorID)
+		if !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSessionRepository_GetUnfinishedSessions(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and media
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "user1")
+
+	friendID := uuid.New()
+	testDB.SeedProfile(t, friendID, "user2")
+
+	media1ID := testDB.SeedMediaItem(t, 11001, "movie", "Movie One")
+	media2ID := testDB.SeedMediaItem(t, 11002, "movie", "Movie Two")
+	media3ID := testDB.SeedMediaItem(t, 11003, "movie", "Movie Three")
+
+	// Fully voted: the user has voted on every candidate
+	doneSessionID := testDB.SeedWatchSession(t, userID, "Done", false)
+	testDB.SeedVote(t, doneSessionID, friendID, media1ID, "yes")
+	testDB.SeedVote(t, doneSessionID, userID, media1ID, "no")
+
+	// Partially voted: two of three candidates are still open
+	partialSessionID := testDB.SeedWatchSession(t, friendID, "Partial", false)
+	testDB.SeedRoomParticipant(t, partialSessionID, userID, "member", "joined")
+	testDB.SeedVote(t, partialSessionID, friendID, media1ID, "yes")
+	testDB.SeedVote(t, partialSessionID, friendID, media2ID, "yes")
+	testDB.SeedVote(t, partialSessionID, friendID, media3ID, "no")
+	testDB.SeedVote(t, partialSessionID, userID, media1ID, "yes")
+
+	// No candidates yet
+	testDB.SeedWatchSession(t, userID, "Empty", false)
+
+	sessions, err := repo.GetUnfinishedSessions(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUnfinishedSessions failed: %v", err)
+	}
+
+	found := make(map[uuid.UUID]UnfinishedSession)
+	for _, session := range sessions {
+		found[session.SessionID] = session
+	}
+
+	t.Run("excludes fully voted sessions", func(t *testing.T) {
+		if _, ok := found[doneSessionID]; ok {
+			t.Error("Expected fully voted session to be excluded")
+		}
+	})
+
+	t.Run("includes partially voted sessions with remaining count", func(t *testing.T) {
+		session, ok := found[partialSessionID]
+		if !ok {
+			t.Fatal("Expected partially voted session to be included")
+		}
+
+		if session.RemainingCount != 2 {
+			t.Errorf("Expected 2 remaining candidates, got %d", session.RemainingCount)
+		}
+	})
+
+	t.Run("excludes sessions without candidates", func(t *testing.T) {
+		if len(sessions) != 1 {
+			t.Errorf("Expected only 1 unfinished session, got %d", len(sessions))
+		}
+	})
+
+	t.Run("ignores sessions the user is not part of", func(t *testing.T) {
+		strangerID := uuid.New()
+		testDB.SeedProfile(t, strangerID, "user3")
+
+		others, err := repo.GetUnfinishedSessions(ctx, strangerID)
+		if err != nil {
+			t.Fatalf("GetUnfinishedSessions failed: %v", err)
+		}
+
+		if len(others) != 0 {
+			t.Errorf("Expected 0 sessions for a non-member, got %d", len(others))
+		}
+	})
+}
+
+func TestSessionRepository_IsSessionMember(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	participantID := uuid.New()
+	testDB.SeedProfile(t, participantID, "participant")
+
+	declinedID := uuid.New()
+	testDB.SeedProfile(t, declinedID, "declined")
+
+	voterID := uuid.New()
+	testDB.SeedProfile(t, voterID, "voter")
+
+	strangerID := uuid.New()
+	testDB.SeedProfile(t, strangerID, "stranger")
+
+	sessionID := testDB.SeedWatchSession(t, creatorID, "Members", false)
+	testDB.SeedRoomParticipant(t, sessionID, participantID, "member", "joined")
+	testDB.SeedRoomParticipant(t, sessionID, declinedID, "member", "declined")
+	mediaID := testDB.SeedMediaItem(t, 13101, "movie", "Voted Movie")
+	testDB.SeedVote(t, sessionID, voterID, mediaID, "no")
+
+	cases := []struct {
+		name     string
+		userID   uuid.UUID
+		expected bool
+	}{
+		{"creator", creatorID, true},
+		{"participant", participantID, true},
+		{"voter", voterID, true},
+		{"declined participant", declinedID, false},
+		{"stranger", strangerID, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			member, err := repo.IsSessionMember(ctx, sessionID, tc.userID)
+			if err != nil {
+				t.Fatalf("IsSessionMember failed: %v", err)
+			}
+			if member != tc.expected {
+				t.Errorf("Expected member=%v, got %v", tc.expected, member)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_SessionParticipants(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "creator")
+
+	invitedID := uuid.New()
+	testDB.SeedProfile(t, invitedID, "invited")
+
+	declinedID := uuid.New()
+	testDB.SeedProfile(t, declinedID, "declined")
+
+	pendingID := uuid.New()
+	testDB.SeedProfile(t, pendingID, "pending")
+
+	voterID := uuid.New()
+	testDB.SeedProfile(t, voterID, "voter")
+
+	sessionID := testDB.SeedWatchSession(t, creatorID, "Participants", false)
+	testDB.SeedRoomParticipant(t, sessionID, declinedID, "member", "declined")
+	testDB.SeedRoomParticipant(t, sessionID, pendingID, "member", "invited")
+	// A stray vote does not make someone a participant
+	mediaID := testDB.SeedMediaItem(t, 13201, "movie", "Stray Vote Movie")
+	testDB.SeedVote(t, sessionID, voterID, mediaID, "yes")
+
+	setPreference := func(userID uuid.UUID, preference string) {
+		t.Helper()
+		_, err := testDB.DB.Exec(`UPDATE profiles SET invite_preference = $2 WHERE id = $1`, userID, preference)
+		if err != nil {
+			t.Fatalf("Failed to set invite preference: %v", err)
+		}
+	}
+
+	participantStatus := func(userID uuid.UUID) string {
+		t.Helper()
+		var status string
+		err := testDB.DB.QueryRow(
+			`SELECT status FROM room_participants WHERE room_id = $1 AND user_id = $2`,
+			sessionID, userID,
+		).Scan(&status)
+		if err != nil {
+			t.Fatalf("Failed to read participant status: %v", err)
+		}
+		return status
+	}
+
+	setPreference(invitedID, "everyone")
+	invited, err := repo.AddSessionParticipant(ctx, sessionID, invitedID)
+	if err != nil {
+		t.Fatalf("AddSessionParticipant failed: %v", err)
+	}
+	if !invited {
+		t.Fatal("Expected the user to be invited")
+	}
+
+	t.Run("adds a pending invite", func(t *testing.T) {
+		if status := participantStatus(invitedID); status != "invited" {
+			t.Errorf("Expected status 'invited', got '%s'", status)
+		}
+	})
+
+	t.Run("adding twice is a no-op", func(t *testing.T) {
+		if _, err := repo.AddSessionParticipant(ctx, sessionID, invitedID); err != nil {
+			t.Errorf("AddSessionParticipant should handle duplicates: %v", err)
+		}
+		if status := participantStatus(invitedID); status != "invited" {
+			t.Errorf("Expected status to stay 'invited', got '%s'", status)
+		}
+	})
+
+	t.Run("leaves a declined invite declined", func(t *testing.T) {
+		setPreference(declinedID, "everyone")
+		if _, err := repo.AddSessionParticipant(ctx, sessionID, declinedID); err != nil {
+			t.Fatalf("AddSessionParticipant failed: %v", err)
+		}
+		if status := participantStatus(declinedID); status != "declined" {
+			t.Errorf("Expected status to stay 'declined', got '%s'", status)
+		}
+	})
+
+	t.Run("respects the invite preference", func(t *testing.T) {
+		closedID := uuid.New()
+		testDB.SeedProfile(t, closedID, "closed")
+		setPreference(closedID, "none")
+
+		invited, err := repo.AddSessionParticipant(ctx, sessionID, closedID)
+		if err != nil {
+			t.Fatalf("AddSessionParticipant failed: %v", err)
+		}
+		if invited {
+			t.Error("Expected the invite to be refused while preference is 'none'")
+		}
+
+		var exists bool
+		err = testDB.DB.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM room_participants WHERE room_id = $1 AND user_id = $2)`,
+			sessionID, closedID,
+		).Scan(&exists)
+		if err != nil {
+			t.Fatalf("Failed to check participant: %v", err)
+		}
+		if exists {
+			t.Error("Expected no participant row for a refused invite")
+		}
+	})
+
+	t.Run("fails when user doesn't exist", func(t *testing.T) {
+		_, err := repo.AddSessionParticipant(ctx, sessionID, uuid.New())
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		userID   uuid.UUID
+		expected bool
+	}{
+		{"creator", creatorID, true},
+		{"added participant who has not accepted", invitedID, false},
+		{"declined participant", declinedID, false},
+		{"invitee who has not accepted", pendingID, false},
+		{"voter without invite", voterID, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			participant, err := repo.IsSessionParticipant(ctx, sessionID, tc.userID)
+			if err != nil {
+				t.Fatalf("IsSessionParticipant failed: %v", err)
+			}
+			if participant != tc.expected {
+				t.Errorf("Expected participant=%v, got %v", tc.expected, participant)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_CanAccessSession(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSessionRepository(testDB.DB)
+	ctx := context.Background()
+
+	creatorID := uuid.New()
+	testDB.SeedProfile(t, creatorID, "access_creator")
+	participantID := uuid.New()
+	testDB.SeedProfile(t, participantID, "access_participant")
+	strangerID := uuid.New()
+	testDB.SeedProfile(t, strangerID, "access_stranger")
+	inviteeID := uuid.New()
+	testDB.SeedProfile(t, inviteeID, "access_invitee")
+
+	privateID := testDB.SeedWatchSession(t, creatorID, "Private", false)
+	testDB.SeedRoomParticipant(t, privateID, participantID, "member", "joined")
+	testDB.SeedRoomParticipant(t, privateID, inviteeID, "member", "invited")
+	publicID := testDB.SeedWatchSession(t, creatorID, "Public", true)
+
+	cases := []struct {
+		name      string
+		sessionID uuid.UUID
+		userID    uuid.UUID
+		expected  bool
+	}{
+		{"creator of private session", privateID, creatorID, true},
+		{"participant of private session", privateID, participantID, true},
+		{"pending invitee of private session", privateID, inviteeID, true},
+		{"stranger to private session", privateID, strangerID, false},
+		{"stranger to public session", publicID, strangerID, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			canAccess, err := repo.CanAccessSession(ctx, tc.sessionID, tc.userID)
+			if err != nil {
+				t.Fatalf("CanAccessSession failed: %v", err)
+			}
+			if canAccess != tc.expected {
+				t.Errorf("Expected canAccess=%v, got %v", tc.expected, canAccess)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,24 +15,40 @@ type Profile struct {
 	UserID           uuid.UUID `json:"id"`
 	Username         *string   `json:"username,omitempty"`
 	InvitePreference string    `json:"invite_preference"`
-	CreatedAt        string    `json:"created_at"`
-	UpdatedAt        string    `json:"updated_at"`
+	// IncludeAdult is only loaded by GetProfile, so it is not exposed in other users' listings
+	IncludeAdult *bool     `json:"include_adult,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// UserSearchResult is a profile returned from a follow search, annotated with the searcher's follow state
+type UserSearchResult struct {
+	Profile
+	IsFollowing bool `json:"is_following"`
+}
+
+// DefaultFollowRestoreWindow is how long an unfollow can be undone
+const DefaultFollowRestoreWindow = 5 * time.Minute
+
 // SocialRepository handles social-related database operations
 type SocialRepository struct {
 	db *sql.DB
+	// SearchByEmail lets user searches find an account by its exact email as well as by username
+	// Emails are never matched partially or returned, so the lookup can't be used to enumerate them
+	SearchByEmail bool
+	// FollowRestoreWindow is how long after an unfollow RestoreFollow can bring the follow back
+	FollowRestoreWindow time.Duration
 }
 
 // NewSocialRepository creates a new social repository
 func NewSocialRepository(db *sql.DB) *SocialRepository {
-	return &SocialRepository{db: db}
+	return &SocialRepository{db: db, FollowRestoreWindow: DefaultFollowRestoreWindow}
 }
 
 // GetProfile retrieves a user's profile
 func (r *SocialRepository) GetProfile(ctx context.Context, userID uuid.UUID) (*Profile, error) {
 	query := `
-		SELECT id, username, invite_preference, created_at, updated_at
+		SELECT id, username, invite_preference, include_adult, created_at, updated_at
 		FROM profiles
 		WHERE id = $1
 	`
@@ -40,6 +58,7 @@ func (r *SocialRepository) GetProfile(ctx context.Context, userID uuid.UUID) (*P
 		&profile.UserID,
 		&profile.Username,
 		&profile.InvitePreference,
+		&profile.IncludeAdult,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 	)
@@ -54,19 +73,78 @@ func (r *SocialRepository) GetProfile(ctx context.Context, userID uuid.UUID) (*P
 	return &profile, nil
 }
 
+// GetProfilesByIDs retrieves the profiles for userIDs in one query
+// Profiles come back in the order of userIDs; unknown IDs are omitted and repeated IDs appear once
+func (r *SocialRepository) GetProfilesByIDs(ctx context.Context, userIDs []uuid.UUID) ([]Profile, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(userIDs))
+	args := make([]interface{}, 0, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, userID)
+	}
+
+	query := `
+		SELECT id, username, invite_preference, created_at, updated_at
+		FROM profiles
+		WHERE id IN (` + strings.Join(placeholders, ", ") + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[uuid.UUID]Profile, len(userIDs))
+	for rows.Next() {
+		var profile Profile
+		err := rows.Scan(
+			&profile.UserID,
+			&profile.Username,
+			&profile.InvitePreference,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		found[profile.UserID] = profile
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating profiles: %w", err)
+	}
+
+	profiles := make([]Profile, 0, len(found))
+	for _, userID := range userIDs {
+		if profile, ok := found[userID]; ok {
+			profiles = append(profiles, profile)
+			delete(found, userID)
+		}
+	}
+
+	return profiles, nil
+}
+
 // CreateOrUpdateProfile creates or updates a user's profile
-func (r *SocialRepository) CreateOrUpdateProfile(ctx context.Context, userID uuid.UUID, username string, invitePreference string) error {
+// A nil includeAdult keeps the current setting, which is false for a new profile
+func (r *SocialRepository) CreateOrUpdateProfile(ctx context.Context, userID uuid.UUID, username string, invitePreference string, includeAdult *bool) error {
 	query := `
-		INSERT INTO profiles (id, username, invite_preference)
-		VALUES ($1, $2, $3)
+		INSERT INTO profiles (id, username, invite_preference, include_adult)
+		VALUES ($1, $2, $3, COALESCE($4, false))
 		ON CONFLICT (id)
 		DO UPDATE SET
 			username = EXCLUDED.username,
 			invite_preference = EXCLUDED.invite_preference,
+			include_adult = COALESCE($4, profiles.include_adult),
 			updated_at = NOW()
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID, username, invitePreference)
+	_, err := r.db.ExecContext(ctx, query, userID, username, invitePreference, includeAdult)
 	if err != nil {
 		return fmt.Errorf("failed to create/update profile: %w", err)
 	}
@@ -75,11 +153,14 @@ func (r *SocialRepository) CreateOrUpdateProfile(ctx context.Context, userID uui
 }
 
 // FollowUser creates a follow relationship
+// Following someone again after an unfollow starts a new follow rather than restoring the old one
 func (r *SocialRepository) FollowUser(ctx context.Context, followerID, followingID uuid.UUID) error {
 	query := `
 		INSERT INTO user_follows (follower_id, following_id)
 		VALUES ($1, $2)
-		ON CONFLICT (follower_id, following_id) DO NOTHING
+		ON CONFLICT (follower_id, following_id)
+		DO UPDATE SET deleted_at = NULL, created_at = NOW()
+		WHERE user_follows.deleted_at IS NOT NULL
 	`
 
 	_, err := r.db.ExecContext(ctx, query, followerID, followingID)
@@ -91,10 +172,16 @@ func (r *SocialRepository) FollowUser(ctx context.Context, followerID, following
 }
 
 // UnfollowUser removes a follow relationship
+// The row is only marked deleted so RestoreFollow can undo it within FollowRestoreWindow
 func (r *SocialRepository) UnfollowUser(ctx context.Context, followerID, followingID uuid.UUID) error {
+	if err := r.purgeUnfollows(ctx, followerID); err != nil {
+		return err
+	}
+
 	query := `
-		DELETE FROM user_follows
-		WHERE follower_id = $1 AND following_id = $2
+		UPDATE user_follows
+		SET deleted_at = NOW()
+		WHERE follower_id = $1 AND following_id = $2 AND deleted_at IS NULL
 	`
 
 	_, err := r.db.ExecContext(ctx, query, followerID, followingID)
@@ -105,43 +192,154 @@ func (r *SocialRepository) UnfollowUser(ctx context.Context, followerID, followi
 	return nil
 }
 
-// GetFollowing retrieves users that a user is following
-func (r *SocialRepository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]Profile, error) {
+// RestoreFollow undoes an unfollow made within FollowRestoreWindow, keeping the original follow date
+// It returns false when there is no recent unfollow to restore
+func (r *SocialRepository) RestoreFollow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE user_follows
+		SET deleted_at = NULL
+		WHERE follower_id = $1 AND following_id = $2
+		AND deleted_at > NOW() - make_interval(secs => $3)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, followerID, followingID, r.FollowRestoreWindow.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to restore follow: %w", err)
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return restored > 0, nil
+}
+
+// purgeUnfollows deletes the follower's unfollows that can no longer be restored
+// Reads already ignore them, so this only keeps the table from accumulating dead rows
+func (r *SocialRepository) purgeUnfollows(ctx context.Context, followerID uuid.UUID) error {
+	query := `
+		DELETE FROM user_follows
+		WHERE follower_id = $1
+		AND deleted_at <= NOW() - make_interval(secs => $2)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, followerID, r.FollowRestoreWindow.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to purge unfollows: %w", err)
+	}
+
+	return nil
+}
+
+// UnfollowUsers removes the follower's follows of every user in followingIDs in one statement
+// IDs the follower does not follow are ignored; it returns the number of follows removed
+// Like UnfollowUser, each follow can be restored within FollowRestoreWindow
+func (r *SocialRepository) UnfollowUsers(ctx context.Context, followerID uuid.UUID, followingIDs []uuid.UUID) (int, error) {
+	if len(followingIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := r.purgeUnfollows(ctx, followerID); err != nil {
+		return 0, err
+	}
+
+	placeholders := make([]string, 0, len(followingIDs))
+	args := make([]interface{}, 0, len(followingIDs)+1)
+	args = append(args, followerID)
+	for i, followingID := range followingIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
+		args = append(args, followingID)
+	}
+
+	query := `
+		UPDATE user_follows
+		SET deleted_at = NOW()
+		WHERE follower_id = $1 AND following_id IN (` + strings.Join(placeholders, ", ") + `)
+		AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unfollow users: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(removed), nil
+}
+
+const (
+	// DefaultFollowingLimit is the page size used when GetFollowing gets no limit
+	DefaultFollowingLimit = 50
+	// MaxFollowingLimit caps the page size of GetFollowing
+	MaxFollowingLimit = 100
+)
+
+// GetFollowing retrieves a page of users that a user is following
+// Results are ordered by username, with users who have none last and ties broken by ID so pages are stable;
+// they are always flagged as followed and total is the full following count
+func (r *SocialRepository) GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]UserSearchResult, int, error) {
+	if limit <= 0 {
+		limit = DefaultFollowingLimit
+	}
+	if limit > MaxFollowingLimit {
+		limit = MaxFollowingLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM user_follows
+		WHERE follower_id = $1 AND deleted_at IS NULL
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count following: %w", err)
+	}
+
 	query := `
 		SELECT p.id, p.username, p.invite_preference, p.created_at, p.updated_at
 		FROM profiles p
 		INNER JOIN user_follows uf ON p.id = uf.following_id
-		WHERE uf.follower_id = $1
-		ORDER BY p.username
+		WHERE uf.follower_id = $1 AND uf.deleted_at IS NULL
+		ORDER BY p.username NULLS LAST, p.id
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get following: %w", err)
+		return nil, 0, fmt.Errorf("failed to get following: %w", err)
 	}
 	defer rows.Close()
 
-	var following []Profile
+	var following []UserSearchResult
 	for rows.Next() {
-		var profile Profile
+		result := UserSearchResult{IsFollowing: true}
 		err := rows.Scan(
-			&profile.UserID,
-			&profile.Username,
-			&profile.InvitePreference,
-			&profile.CreatedAt,
-			&profile.UpdatedAt,
+			&result.UserID,
+			&result.Username,
+			&result.InvitePreference,
+			&result.CreatedAt,
+			&result.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan profile: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan profile: %w", err)
 		}
-		following = append(following, profile)
+		following = append(following, result)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating following: %w", err)
+		return nil, 0, fmt.Errorf("error iterating following: %w", err)
 	}
 
-	return following, nil
+	return following, total, nil
 }
 
 // IsFollowing checks if followerID is following followingID
@@ -149,7 +347,7 @@ func (r *SocialRepository) IsFollowing(ctx context.Context, followerID, followin
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM user_follows
-			WHERE follower_id = $1 AND following_id = $2
+			WHERE follower_id = $1 AND following_id = $2 AND deleted_at IS NULL
 		)
 	`
 
@@ -162,18 +360,116 @@ func (r *SocialRepository) IsFollowing(ctx context.Context, followerID, followin
 	return exists, nil
 }
 
-// SearchUsers searches for users by username or email
-func (r *SocialRepository) SearchUsers(ctx context.Context, query string) ([]Profile, error) {
+// AreMutuals checks if userA and userB follow each other
+func (r *SocialRepository) AreMutuals(ctx context.Context, userA, userB uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM user_follows
+			WHERE follower_id = $1 AND following_id = $2 AND deleted_at IS NULL
+		) AND EXISTS(
+			SELECT 1 FROM user_follows
+			WHERE follower_id = $2 AND following_id = $1 AND deleted_at IS NULL
+		)
+	`
+
+	var mutual bool
+	err := r.db.QueryRowContext(ctx, query, userA, userB).Scan(&mutual)
+	if err != nil {
+		return false, fmt.Errorf("failed to check mutual follow: %w", err)
+	}
+
+	return mutual, nil
+}
+
+// GetMutuals retrieves the profiles of users who follow userID and are followed back
+func (r *SocialRepository) GetMutuals(ctx context.Context, userID uuid.UUID) ([]Profile, error) {
+	query := `
+		SELECT p.id, p.username, p.invite_preference, p.created_at, p.updated_at
+		FROM profiles p
+		INNER JOIN user_follows outgoing ON p.id = outgoing.following_id
+		INNER JOIN user_follows incoming ON p.id = incoming.follower_id
+		WHERE outgoing.follower_id = $1
+		AND incoming.following_id = $1
+		AND outgoing.deleted_at IS NULL
+		AND incoming.deleted_at IS NULL
+		ORDER BY p.username
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mutuals: %w", err)
+	}
+	defer rows.Close()
+
+	var mutuals []Profile
+	for rows.Next() {
+		var profile Profile
+		err := rows.Scan(
+			&profile.UserID,
+			&profile.Username,
+			&profile.InvitePreference,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		mutuals = append(mutuals, profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating mutuals: %w", err)
+	}
+
+	return mutuals, nil
+}
+
+const (
+	// DefaultSearchLimit is the page size used when SearchUsers gets no limit
+	DefaultSearchLimit = 20
+	// MaxSearchLimit caps the page size of SearchUsers
+	MaxSearchLimit = 50
+)
+
+// SearchUsers searches for users by username, or by exact, case-insensitive email when SearchByEmail is set
+// Results are ordered by username; total is the number of matches across all pages
+func (r *SocialRepository) SearchUsers(ctx context.Context, query string, limit, offset int) ([]Profile, int, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	pattern := "%" + query + "%"
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM profiles p
+		LEFT JOIN auth.users u ON u.id = p.id
+		WHERE (p.username ILIKE $1 OR ($2 AND lower(u.email) = lower($3)))
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, pattern, r.SearchByEmail, query).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
 	searchQuery := `
 		SELECT p.id, p.username, p.invite_preference, p.created_at, p.updated_at
 		FROM profiles p
-		WHERE p.username ILIKE $1
-		LIMIT 20
+		LEFT JOIN auth.users u ON u.id = p.id
+		WHERE (p.username ILIKE $1 OR ($2 AND lower(u.email) = lower($3)))
+		ORDER BY p.username, p.id
+		LIMIT $4 OFFSET $5
 	`
 
-	rows, err := r.db.QueryContext(ctx, searchQuery, "%"+query+"%")
+	rows, err := r.db.QueryContext(ctx, searchQuery, pattern, r.SearchByEmail, query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search users: %w", err)
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
 	}
 	defer rows.Close()
 
@@ -188,14 +484,87 @@ func (r *SocialRepository) SearchUsers(ctx context.Context, query string) ([]Pro
 			&profile.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan profile: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan profile: %w", err)
 		}
 		users = append(users, profile)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating users: %w", err)
+		return nil, 0, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// SearchUsersForFollow searches for users the searcher could follow, matching like SearchUsers
+// The searcher and anyone they already follow are excluded; total counts matches across all pages
+func (r *SocialRepository) SearchUsersForFollow(ctx context.Context, searcherID uuid.UUID, query string, limit, offset int) ([]UserSearchResult, int, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	pattern := "%" + query + "%"
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM profiles p
+		LEFT JOIN user_follows uf ON uf.follower_id = $2 AND uf.following_id = p.id AND uf.deleted_at IS NULL
+		LEFT JOIN auth.users u ON u.id = p.id
+		WHERE (p.username ILIKE $1 OR ($3 AND lower(u.email) = lower($4)))
+		AND p.id <> $2
+		AND uf.follower_id IS NULL
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, pattern, searcherID, r.SearchByEmail, query).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	searchQuery := `
+		SELECT p.id, p.username, p.invite_preference, p.created_at, p.updated_at,
+			uf.follower_id IS NOT NULL AS is_following
+		FROM profiles p
+		LEFT JOIN user_follows uf ON uf.follower_id = $2 AND uf.following_id = p.id AND uf.deleted_at IS NULL
+		LEFT JOIN auth.users u ON u.id = p.id
+		WHERE (p.username ILIKE $1 OR ($3 AND lower(u.email) = lower($4)))
+		AND p.id <> $2
+		AND uf.follower_id IS NULL
+		ORDER BY p.username, p.id
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, searchQuery, pattern, searcherID, r.SearchByEmail, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []UserSearchResult
+	for rows.Next() {
+		var result UserSearchResult
+		err := rows.Scan(
+			&result.UserID,
+			&result.Username,
+			&result.InvitePreference,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.IsFollowing,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		users = append(users, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating users: %w", err)
 	}
 
-	return users, nil
+	return users, total, nil
 }
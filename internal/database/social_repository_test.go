@@ -82,6 +82,59 @@ func TestSocialRepository_FollowUser(t *testing.T) {
 	})
 }
 
+func TestSocialRepository_GetProfilesByIDs(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	aliceID := uuid.New()
+	bobID := uuid.New()
+	carolID := uuid.New()
+	testDB.SeedProfile(t, aliceID, "alice")
+	testDB.SeedProfile(t, bobID, "bob")
+	testDB.SeedProfile(t, carolID, "carol")
+
+	t.Run("returns existing profiles in request order and omits unknown IDs", func(t *testing.T) {
+		profiles, err := repo.GetProfilesByIDs(ctx, []uuid.UUID{carolID, uuid.New(), aliceID})
+		if err != nil {
+			t.Fatalf("GetProfilesByIDs failed: %v", err)
+		}
+
+		if len(profiles) != 2 {
+			t.Fatalf("Expected 2 profiles, got %d", len(profiles))
+		}
+		if profiles[0].UserID != carolID || profiles[1].UserID != aliceID {
+			t.Errorf("Expected carol then alice, got %s then %s", profiles[0].UserID, profiles[1].UserID)
+		}
+		if profiles[0].Username == nil || *profiles[0].Username != "carol" {
+			t.Errorf("Expected username carol, got %v", profiles[0].Username)
+		}
+	})
+
+	t.Run("returns repeated IDs once", func(t *testing.T) {
+		profiles, err := repo.GetProfilesByIDs(ctx, []uuid.UUID{bobID, bobID})
+		if err != nil {
+			t.Fatalf("GetProfilesByIDs failed: %v", err)
+		}
+		if len(profiles) != 1 {
+			t.Errorf("Expected 1 profile, got %d", len(profiles))
+		}
+	})
+
+	t.Run("returns nothing when no IDs exist", func(t *testing.T) {
+		profiles, err := repo.GetProfilesByIDs(ctx, []uuid.UUID{uuid.New()})
+		if err != nil {
+			t.Fatalf("GetProfilesByIDs failed: %v", err)
+		}
+		if len(profiles) != 0 {
+			t.Errorf("Expected no profiles, got %d", len(profiles))
+		}
+	})
+}
+
 func TestSocialRepository_UnfollowUser(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
@@ -151,6 +204,219 @@ func TestSocialRepository_UnfollowUser(t *testing.T) {
 	})
 }
 
+func TestSocialRepository_RestoreFollow(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	t.Run("an unfollow restored within the window is active again", func(t *testing.T) {
+		testDB.SeedFollow(t, user1ID, user2ID)
+
+		if err := repo.UnfollowUser(ctx, user1ID, user2ID); err != nil {
+			t.Fatalf("UnfollowUser failed: %v", err)
+		}
+
+		restored, err := repo.RestoreFollow(ctx, user1ID, user2ID)
+		if err != nil {
+			t.Fatalf("RestoreFollow failed: %v", err)
+		}
+		if !restored {
+			t.Fatal("Expected the follow to be restored")
+		}
+
+		isFollowing, err := repo.IsFollowing(ctx, user1ID, user2ID)
+		if err != nil {
+			t.Fatalf("IsFollowing failed: %v", err)
+		}
+		if !isFollowing {
+			t.Error("Expected user1 to follow user2 again")
+		}
+
+		following, total, err := repo.GetFollowing(ctx, user1ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+		if total != 1 || len(following) != 1 || following[0].UserID != user2ID {
+			t.Errorf("Expected user1 to follow only user2, got %d: %v", total, following)
+		}
+	})
+
+	t.Run("an active follow has nothing to restore", func(t *testing.T) {
+		restored, err := repo.RestoreFollow(ctx, user1ID, user2ID)
+		if err != nil {
+			t.Fatalf("RestoreFollow failed: %v", err)
+		}
+		if restored {
+			t.Error("Expected nothing to restore for an active follow")
+		}
+	})
+
+	t.Run("an unfollow older than the window is treated as gone", func(t *testing.T) {
+		testDB.SeedFollow(t, user1ID, user3ID)
+
+		if err := repo.UnfollowUser(ctx, user1ID, user3ID); err != nil {
+			t.Fatalf("UnfollowUser failed: %v", err)
+		}
+
+		// Backdate the unfollow past the restore window
+		_, err := testDB.DB.Exec(`
+			UPDATE user_follows SET deleted_at = NOW() - INTERVAL '1 hour'
+			WHERE follower_id = $1 AND following_id = $2
+		`, user1ID, user3ID)
+		if err != nil {
+			t.Fatalf("Failed to backdate unfollow: %v", err)
+		}
+
+		restored, err := repo.RestoreFollow(ctx, user1ID, user3ID)
+		if err != nil {
+			t.Fatalf("RestoreFollow failed: %v", err)
+		}
+		if restored {
+			t.Error("Expected an old unfollow not to be restorable")
+		}
+
+		isFollowing, err := repo.IsFollowing(ctx, user1ID, user3ID)
+		if err != nil {
+			t.Fatalf("IsFollowing failed: %v", err)
+		}
+		if isFollowing {
+			t.Error("Expected user1 not to follow user3")
+		}
+
+		_, total, err := repo.GetFollowing(ctx, user1ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+		if total != 1 {
+			t.Errorf("Expected the old unfollow to be left out of following, got total %d", total)
+		}
+	})
+
+	t.Run("later unfollows purge rows past the window", func(t *testing.T) {
+		if err := repo.UnfollowUser(ctx, user1ID, user2ID); err != nil {
+			t.Fatalf("UnfollowUser failed: %v", err)
+		}
+
+		var rows int
+		err := testDB.DB.QueryRow(`
+			SELECT COUNT(*) FROM user_follows
+			WHERE follower_id = $1 AND following_id = $2
+		`, user1ID, user3ID).Scan(&rows)
+		if err != nil {
+			t.Fatalf("Failed to count follows: %v", err)
+		}
+		if rows != 0 {
+			t.Errorf("Expected the old unfollow to be purged, found %d rows", rows)
+		}
+	})
+
+	t.Run("following again after an unfollow starts a new follow", func(t *testing.T) {
+		if err := repo.FollowUser(ctx, user1ID, user2ID); err != nil {
+			t.Fatalf("FollowUser failed: %v", err)
+		}
+
+		isFollowing, err := repo.IsFollowing(ctx, user1ID, user2ID)
+		if err != nil {
+			t.Fatalf("IsFollowing failed: %v", err)
+		}
+		if !isFollowing {
+			t.Error("Expected user1 to follow user2 after following again")
+		}
+	})
+}
+
+func TestSocialRepository_UnfollowUsers(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	user4ID := uuid.New()
+	testDB.SeedProfile(t, user4ID, "user4")
+
+	t.Run("removes several follows at once", func(t *testing.T) {
+		testDB.SeedFollow(t, user1ID, user2ID)
+		testDB.SeedFollow(t, user1ID, user3ID)
+		testDB.SeedFollow(t, user1ID, user4ID)
+
+		removed, err := repo.UnfollowUsers(ctx, user1ID, []uuid.UUID{user2ID, user3ID})
+		if err != nil {
+			t.Fatalf("UnfollowUsers failed: %v", err)
+		}
+
+		if removed != 2 {
+			t.Errorf("Expected 2 follows removed, got %d", removed)
+		}
+
+		following, _, err := repo.GetFollowing(ctx, user1ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+		if len(following) != 1 || following[0].UserID != user4ID {
+			t.Errorf("Expected user1 to only follow user4, got %v", following)
+		}
+	})
+
+	t.Run("ignores IDs not currently followed", func(t *testing.T) {
+		testDB.SeedFollow(t, user2ID, user3ID)
+
+		// user2 was already unfollowed; user3 is followed by someone else, not user1
+		removed, err := repo.UnfollowUsers(ctx, user1ID, []uuid.UUID{user2ID, user3ID, uuid.New()})
+		if err != nil {
+			t.Fatalf("UnfollowUsers failed: %v", err)
+		}
+
+		if removed != 0 {
+			t.Errorf("Expected 0 follows removed, got %d", removed)
+		}
+
+		isFollowing, _ := repo.IsFollowing(ctx, user2ID, user3ID)
+		if !isFollowing {
+			t.Error("Expected user2 to still be following user3")
+		}
+
+		isFollowing, _ = repo.IsFollowing(ctx, user1ID, user4ID)
+		if !isFollowing {
+			t.Error("Expected user1 to still be following user4")
+		}
+	})
+
+	t.Run("handles an empty list", func(t *testing.T) {
+		removed, err := repo.UnfollowUsers(ctx, user1ID, nil)
+		if err != nil {
+			t.Fatalf("UnfollowUsers failed: %v", err)
+		}
+
+		if removed != 0 {
+			t.Errorf("Expected 0 follows removed, got %d", removed)
+		}
+	})
+}
+
 func TestSocialRepository_GetFollowing(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
@@ -173,7 +439,7 @@ func TestSocialRepository_GetFollowing(t *testing.T) {
 	testDB.SeedProfile(t, user4ID, "diana")
 
 	t.Run("returns empty list when not following anyone", func(t *testing.T) {
-		following, err := repo.GetFollowing(ctx, user1ID)
+		following, _, err := repo.GetFollowing(ctx, user1ID, 0, 0)
 		if err != nil {
 			t.Fatalf("GetFollowing failed: %v", err)
 		}
@@ -189,7 +455,7 @@ func TestSocialRepository_GetFollowing(t *testing.T) {
 		testDB.SeedFollow(t, user1ID, user3ID)
 		testDB.SeedFollow(t, user1ID, user4ID)
 
-		following, err := repo.GetFollowing(ctx, user1ID)
+		following, _, err := repo.GetFollowing(ctx, user1ID, 0, 0)
 		if err != nil {
 			t.Fatalf("GetFollowing failed: %v", err)
 		}
@@ -212,7 +478,7 @@ func TestSocialRepository_GetFollowing(t *testing.T) {
 
 	t.Run("doesn't return users not being followed", func(t *testing.T) {
 		// User2 doesn't follow user1
-		following, err := repo.GetFollowing(ctx, user2ID)
+		following, _, err := repo.GetFollowing(ctx, user2ID, 0, 0)
 		if err != nil {
 			t.Fatalf("GetFollowing failed: %v", err)
 		}
@@ -221,6 +487,104 @@ func TestSocialRepository_GetFollowing(t *testing.T) {
 			t.Errorf("Expected 0 following for user2, got %d", len(following))
 		}
 	})
+
+	t.Run("paginates large following lists", func(t *testing.T) {
+		followerID := uuid.New()
+		testDB.SeedProfile(t, followerID, "paginator")
+
+		for i := 0; i < 30; i++ {
+			followedID := uuid.New()
+			testDB.SeedProfile(t, followedID, fmt.Sprintf("followed%02d", i))
+			testDB.SeedFollow(t, followerID, followedID)
+		}
+
+		firstPage, total, err := repo.GetFollowing(ctx, followerID, 15, 0)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+		secondPage, _, err := repo.GetFollowing(ctx, followerID, 15, 15)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+
+		if total != 30 {
+			t.Errorf("Expected total 30, got %d", total)
+		}
+		if len(firstPage) != 15 || len(secondPage) != 15 {
+			t.Fatalf("Expected two pages of 15, got %d and %d", len(firstPage), len(secondPage))
+		}
+
+		seen := make(map[uuid.UUID]bool)
+		all := append(firstPage, secondPage...)
+		for i, user := range all {
+			if seen[user.UserID] {
+				t.Errorf("Expected pages to be disjoint, %s appears twice", *user.Username)
+			}
+			seen[user.UserID] = true
+
+			if !user.IsFollowing {
+				t.Errorf("Expected %s to be flagged as followed", *user.Username)
+			}
+			if want := fmt.Sprintf("followed%02d", i); *user.Username != want {
+				t.Errorf("Expected position %d to be '%s', got '%s'", i, want, *user.Username)
+			}
+		}
+	})
+
+	t.Run("orders users without a username last and repeatably", func(t *testing.T) {
+		followerID := uuid.New()
+		testDB.SeedProfile(t, followerID, "nullfollower")
+
+		namedIDs := make([]uuid.UUID, 0, 2)
+		for _, username := range []string{"zed", "amy"} {
+			id := uuid.New()
+			testDB.SeedProfile(t, id, username)
+			testDB.SeedFollow(t, followerID, id)
+			namedIDs = append(namedIDs, id)
+		}
+
+		unnamedIDs := make(map[uuid.UUID]bool)
+		for i := 0; i < 2; i++ {
+			id := uuid.New()
+			testDB.SeedProfile(t, id, fmt.Sprintf("unnamed%d", i))
+			if _, err := testDB.DB.ExecContext(ctx, "UPDATE profiles SET username = NULL WHERE id = $1", id); err != nil {
+				t.Fatalf("Failed to clear username: %v", err)
+			}
+			testDB.SeedFollow(t, followerID, id)
+			unnamedIDs[id] = true
+		}
+
+		first, _, err := repo.GetFollowing(ctx, followerID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetFollowing failed: %v", err)
+		}
+		if len(first) != 4 {
+			t.Fatalf("Expected 4 following, got %d", len(first))
+		}
+
+		if first[0].UserID != namedIDs[1] || first[1].UserID != namedIDs[0] {
+			t.Error("Expected 'amy' then 'zed' before users without a username")
+		}
+		for _, user := range first[2:] {
+			if !unnamedIDs[user.UserID] || user.Username != nil {
+				t.Errorf("Expected users without a username last, got %v", user.UserID)
+			}
+		}
+		if first[2].UserID.String() > first[3].UserID.String() {
+			t.Error("Expected users without a username to be ordered by ID")
+		}
+
+		// Paging one at a time must walk the same order
+		for i := range first {
+			page, _, err := repo.GetFollowing(ctx, followerID, 1, i)
+			if err != nil {
+				t.Fatalf("GetFollowing failed: %v", err)
+			}
+			if len(page) != 1 || page[0].UserID != first[i].UserID {
+				t.Errorf("Expected page %d to hold %v", i, first[i].UserID)
+			}
+		}
+	})
 }
 
 func TestSocialRepository_IsFollowing(t *testing.T) {
@@ -287,6 +651,79 @@ func TestSocialRepository_IsFollowing(t *testing.T) {
 	})
 }
 
+func TestSocialRepository_Mutuals(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: A and B follow each other, A follows C one way
+	userAID := uuid.New()
+	testDB.SeedProfile(t, userAID, "alice")
+
+	userBID := uuid.New()
+	testDB.SeedProfile(t, userBID, "bob")
+
+	userCID := uuid.New()
+	testDB.SeedProfile(t, userCID, "carol")
+
+	testDB.SeedFollow(t, userAID, userBID)
+	testDB.SeedFollow(t, userBID, userAID)
+	testDB.SeedFollow(t, userAID, userCID)
+
+	t.Run("mutual follow is detected in both directions", func(t *testing.T) {
+		for _, pair := range [][2]uuid.UUID{{userAID, userBID}, {userBID, userAID}} {
+			mutual, err := repo.AreMutuals(ctx, pair[0], pair[1])
+			if err != nil {
+				t.Fatalf("AreMutuals failed: %v", err)
+			}
+			if !mutual {
+				t.Error("Expected alice and bob to be mutuals")
+			}
+		}
+	})
+
+	t.Run("one-directional follow is not mutual", func(t *testing.T) {
+		mutual, err := repo.AreMutuals(ctx, userAID, userCID)
+		if err != nil {
+			t.Fatalf("AreMutuals failed: %v", err)
+		}
+		if mutual {
+			t.Error("Expected alice and carol not to be mutuals")
+		}
+	})
+
+	t.Run("mutual appears in both friends lists", func(t *testing.T) {
+		aliceFriends, err := repo.GetMutuals(ctx, userAID)
+		if err != nil {
+			t.Fatalf("GetMutuals failed: %v", err)
+		}
+		if len(aliceFriends) != 1 || aliceFriends[0].UserID != userBID {
+			t.Errorf("Expected alice's only friend to be bob, got %v", aliceFriends)
+		}
+
+		bobFriends, err := repo.GetMutuals(ctx, userBID)
+		if err != nil {
+			t.Fatalf("GetMutuals failed: %v", err)
+		}
+		if len(bobFriends) != 1 || bobFriends[0].UserID != userAID {
+			t.Errorf("Expected bob's only friend to be alice, got %v", bobFriends)
+		}
+	})
+
+	t.Run("one-directional follow is not a friend", func(t *testing.T) {
+		carolFriends, err := repo.GetMutuals(ctx, userCID)
+		if err != nil {
+			t.Fatalf("GetMutuals failed: %v", err)
+		}
+		if len(carolFriends) != 0 {
+			t.Errorf("Expected carol to have no friends, got %d", len(carolFriends))
+		}
+	})
+}
+
 func TestSocialRepository_SearchUsers(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
@@ -309,7 +746,7 @@ func TestSocialRepository_SearchUsers(t *testing.T) {
 	testDB.SeedProfile(t, user4ID, "charlie_brown")
 
 	t.Run("finds users by partial username match", func(t *testing.T) {
-		users, err := repo.SearchUsers(ctx, "alice")
+		users, _, err := repo.SearchUsers(ctx, "alice", 20, 0)
 		if err != nil {
 			t.Fatalf("SearchUsers failed: %v", err)
 		}
@@ -343,7 +780,7 @@ func TestSocialRepository_SearchUsers(t *testing.T) {
 	})
 
 	t.Run("search is case-insensitive", func(t *testing.T) {
-		users, err := repo.SearchUsers(ctx, "ALICE")
+		users, _, err := repo.SearchUsers(ctx, "ALICE", 20, 0)
 		if err != nil {
 			t.Fatalf("SearchUsers failed: %v", err)
 		}
@@ -354,7 +791,7 @@ func TestSocialRepository_SearchUsers(t *testing.T) {
 	})
 
 	t.Run("returns empty list for no matches", func(t *testing.T) {
-		users, err := repo.SearchUsers(ctx, "xyz_nonexistent")
+		users, _, err := repo.SearchUsers(ctx, "xyz_nonexistent", 20, 0)
 		if err != nil {
 			t.Fatalf("SearchUsers failed: %v", err)
 		}
@@ -371,7 +808,7 @@ func TestSocialRepository_SearchUsers(t *testing.T) {
 			testDB.SeedProfile(t, userID, fmt.Sprintf("test_user_%d", i))
 		}
 
-		users, err := repo.SearchUsers(ctx, "test_user")
+		users, _, err := repo.SearchUsers(ctx, "test_user", 0, 0)
 		if err != nil {
 			t.Fatalf("SearchUsers failed: %v", err)
 		}
@@ -380,4 +817,242 @@ func TestSocialRepository_SearchUsers(t *testing.T) {
 			t.Errorf("Expected maximum 20 users, got %d", len(users))
 		}
 	})
+
+	t.Run("caps limit at 50", func(t *testing.T) {
+		for i := 0; i < 55; i++ {
+			testDB.SeedProfile(t, uuid.New(), fmt.Sprintf("cap_user_%02d", i))
+		}
+
+		users, total, err := repo.SearchUsers(ctx, "cap_user", 100, 0)
+		if err != nil {
+			t.Fatalf("SearchUsers failed: %v", err)
+		}
+
+		if len(users) != 50 {
+			t.Errorf("Expected limit capped at 50 users, got %d", len(users))
+		}
+		if total != 55 {
+			t.Errorf("Expected total 55, got %d", total)
+		}
+	})
+}
+
+func TestSocialRepository_SearchUsers_Email(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	// The email shares nothing with the username
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "night_owl")
+	if _, err := testDB.DB.Exec(`UPDATE auth.users SET email = 'jordan.reyes@example.org' WHERE id = $1`, userID); err != nil {
+		t.Fatalf("Failed to set email: %v", err)
+	}
+
+	searcherID := uuid.New()
+	testDB.SeedProfile(t, searcherID, "email_searcher")
+
+	t.Run("finds users by exact email when enabled", func(t *testing.T) {
+		repo.SearchByEmail = true
+
+		users, total, err := repo.SearchUsers(ctx, "Jordan.Reyes@example.org", 20, 0)
+		if err != nil {
+			t.Fatalf("SearchUsers failed: %v", err)
+		}
+		if total != 1 || len(users) != 1 || users[0].UserID != userID {
+			t.Fatalf("Expected to find the user by email, got %d users (total %d)", len(users), total)
+		}
+
+		results, total, err := repo.SearchUsersForFollow(ctx, searcherID, "jordan.reyes@example.org", 20, 0)
+		if err != nil {
+			t.Fatalf("SearchUsersForFollow failed: %v", err)
+		}
+		if total != 1 || len(results) != 1 || results[0].UserID != userID {
+			t.Fatalf("Expected to find the user by email, got %d users (total %d)", len(results), total)
+		}
+	})
+
+	t.Run("partial emails match nothing", func(t *testing.T) {
+		repo.SearchByEmail = true
+
+		for _, query := range []string{"jordan.reyes", "@example.org", "reyes@example"} {
+			users, total, err := repo.SearchUsers(ctx, query, 20, 0)
+			if err != nil {
+				t.Fatalf("SearchUsers failed: %v", err)
+			}
+			if total != 0 || len(users) != 0 {
+				t.Errorf("Expected no users for %q, got %d (total %d)", query, len(users), total)
+			}
+		}
+	})
+
+	t.Run("ignores email when disabled", func(t *testing.T) {
+		repo.SearchByEmail = false
+
+		users, total, err := repo.SearchUsers(ctx, "jordan.reyes@example.org", 20, 0)
+		if err != nil {
+			t.Fatalf("SearchUsers failed: %v", err)
+		}
+		if total != 0 || len(users) != 0 {
+			t.Errorf("Expected no users, got %d (total %d)", len(users), total)
+		}
+
+		results, total, err := repo.SearchUsersForFollow(ctx, searcherID, "jordan.reyes@example.org", 20, 0)
+		if err != nil {
+			t.Fatalf("SearchUsersForFollow failed: %v", err)
+		}
+		if total != 0 || len(results) != 0 {
+			t.Errorf("Expected no users, got %d (total %d)", len(results), total)
+		}
+	})
+
+	t.Run("username search is unaffected by the setting", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			repo.SearchByEmail = enabled
+
+			users, _, err := repo.SearchUsers(ctx, "night_owl", 20, 0)
+			if err != nil {
+				t.Fatalf("SearchUsers failed: %v", err)
+			}
+			if len(users) != 1 {
+				t.Errorf("Expected 1 user with SearchByEmail=%v, got %d", enabled, len(users))
+			}
+		}
+	})
+}
+
+func TestSocialRepository_SearchUsers_Pagination(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	for i := 0; i < 25; i++ {
+		testDB.SeedProfile(t, uuid.New(), fmt.Sprintf("page_user_%02d", i))
+	}
+	testDB.SeedProfile(t, uuid.New(), "someone_else")
+
+	firstPage, total, err := repo.SearchUsers(ctx, "page_user", 20, 0)
+	if err != nil {
+		t.Fatalf("SearchUsers failed: %v", err)
+	}
+	secondPage, secondTotal, err := repo.SearchUsers(ctx, "page_user", 20, 20)
+	if err != nil {
+		t.Fatalf("SearchUsers failed: %v", err)
+	}
+
+	t.Run("returns total across pages", func(t *testing.T) {
+		if total != 25 {
+			t.Errorf("Expected total 25, got %d", total)
+		}
+		if secondTotal != 25 {
+			t.Errorf("Expected total 25 on second page, got %d", secondTotal)
+		}
+	})
+
+	t.Run("splits results into full and partial pages", func(t *testing.T) {
+		if len(firstPage) != 20 {
+			t.Errorf("Expected 20 users on first page, got %d", len(firstPage))
+		}
+		if len(secondPage) != 5 {
+			t.Errorf("Expected 5 users on second page, got %d", len(secondPage))
+		}
+	})
+
+	t.Run("pages are disjoint and ordered by username", func(t *testing.T) {
+		all := append(append([]Profile{}, firstPage...), secondPage...)
+		seen := make(map[uuid.UUID]bool)
+		for i, user := range all {
+			if seen[user.UserID] {
+				t.Errorf("User %s returned on more than one page", user.UserID)
+			}
+			seen[user.UserID] = true
+
+			expected := fmt.Sprintf("page_user_%02d", i)
+			if user.Username == nil || *user.Username != expected {
+				t.Errorf("Expected %s at position %d, got %v", expected, i, user.Username)
+			}
+		}
+	})
+}
+
+func TestSocialRepository_SearchUsersForFollow(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewSocialRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: searcher follows one of the matching users
+	searcherID := uuid.New()
+	testDB.SeedProfile(t, searcherID, "movie_fan_me")
+
+	followedID := uuid.New()
+	testDB.SeedProfile(t, followedID, "movie_fan_followed")
+	testDB.SeedFollow(t, searcherID, followedID)
+
+	strangerID := uuid.New()
+	testDB.SeedProfile(t, strangerID, "movie_fan_stranger")
+
+	// Follows the searcher, but is not followed back
+	followerID := uuid.New()
+	testDB.SeedProfile(t, followerID, "movie_fan_follower")
+	testDB.SeedFollow(t, followerID, searcherID)
+
+	users, total, err := repo.SearchUsersForFollow(ctx, searcherID, "movie_fan", 20, 0)
+	if err != nil {
+		t.Fatalf("SearchUsersForFollow failed: %v", err)
+	}
+
+	found := make(map[uuid.UUID]UserSearchResult)
+	for _, user := range users {
+		found[user.UserID] = user
+	}
+
+	t.Run("excludes the searcher", func(t *testing.T) {
+		if _, ok := found[searcherID]; ok {
+			t.Error("Expected searcher to be excluded from results")
+		}
+	})
+
+	t.Run("excludes users already followed", func(t *testing.T) {
+		if _, ok := found[followedID]; ok {
+			t.Error("Expected followed user to be excluded from results")
+		}
+	})
+
+	t.Run("returns remaining users as not followed", func(t *testing.T) {
+		if len(users) != 2 {
+			t.Fatalf("Expected 2 users, got %d", len(users))
+		}
+		if total != 2 {
+			t.Errorf("Expected total 2, got %d", total)
+		}
+
+		for _, id := range []uuid.UUID{followerID, strangerID} {
+			user, ok := found[id]
+			if !ok {
+				t.Errorf("Expected user %s in results", id)
+				continue
+			}
+			if user.IsFollowing {
+				t.Errorf("Expected is_following false for %s", id)
+			}
+		}
+	})
+
+	t.Run("keeps results ordered by username", func(t *testing.T) {
+		if len(users) != 2 {
+			t.Fatalf("Expected 2 users, got %d", len(users))
+		}
+		if *users[0].Username != "movie_fan_follower" || *users[1].Username != "movie_fan_stranger" {
+			t.Errorf("Expected follower then stranger, got %s then %s", *users[0].Username, *users[1].Username)
+		}
+	})
 }
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestModelTimestamps_MarshalRFC3339(t *testing.T) {
+	created := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	updated := created.Add(90 * time.Minute)
+
+	models := map[string]interface{}{
+		"watch session": WatchSession{CreatedAt: created, UpdatedAt: updated},
+		"room":          Room{CreatedAt: created, UpdatedAt: updated},
+		"profile":       Profile{CreatedAt: created, UpdatedAt: updated},
+		"media item":    MediaItem{CreatedAt: created, UpdatedAt: updated},
+	}
+
+	for name, model := range models {
+		t.Run(name, func(t *testing.T) {
+			body, err := json.Marshal(model)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal(body, &fields); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+
+			for key, want := range map[string]time.Time{"created_at": created, "updated_at": updated} {
+				raw, ok := fields[key].(string)
+				if !ok {
+					t.Fatalf("Expected %s to be a string, got %v", key, fields[key])
+				}
+				got, err := time.Parse(time.RFC3339, raw)
+				if err != nil {
+					t.Fatalf("Expected %s to be RFC3339, got %q: %v", key, raw, err)
+				}
+				if !got.Equal(want) {
+					t.Errorf("Expected %s %v, got %v", key, want, got)
+				}
+			}
+
+			if _, ok := fields["completed_at"]; ok {
+				t.Error("Expected nil completed_at to be omitted")
+			}
+		})
+	}
+}
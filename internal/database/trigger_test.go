@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/testutils"
@@ -161,7 +162,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		testDB.SeedProfile(t, userID, "test_user")
 
 		// Get initial updated_at
-		var initialUpdatedAt string
+		var initialUpdatedAt time.Time
 		err := testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM profiles WHERE id = $1",
 			userID,
@@ -180,7 +181,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		}
 
 		// Get new updated_at
-		var newUpdatedAt string
+		var newUpdatedAt time.Time
 		err = testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM profiles WHERE id = $1",
 			userID,
@@ -189,8 +190,8 @@ func TestUpdatedAtTriggers(t *testing.T) {
 			t.Fatalf("Failed to get new updated_at: %v", err)
 		}
 
-		if newUpdatedAt == initialUpdatedAt {
-			t.Error("Expected updated_at to change after update")
+		if !newUpdatedAt.After(initialUpdatedAt) {
+			t.Error("Expected updated_at to advance after update")
 		}
 	})
 
@@ -198,7 +199,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		mediaID := testDB.SeedMediaItem(t, 12345, "movie", "Test Movie")
 
 		// Get initial updated_at
-		var initialUpdatedAt string
+		var initialUpdatedAt time.Time
 		err := testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM media_items WHERE id = $1",
 			mediaID,
@@ -217,7 +218,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		}
 
 		// Get new updated_at
-		var newUpdatedAt string
+		var newUpdatedAt time.Time
 		err = testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM media_items WHERE id = $1",
 			mediaID,
@@ -226,8 +227,8 @@ func TestUpdatedAtTriggers(t *testing.T) {
 			t.Fatalf("Failed to get new updated_at: %v", err)
 		}
 
-		if newUpdatedAt == initialUpdatedAt {
-			t.Error("Expected updated_at to change after update")
+		if !newUpdatedAt.After(initialUpdatedAt) {
+			t.Error("Expected updated_at to advance after update")
 		}
 	})
 
@@ -237,7 +238,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		sessionID := testDB.SeedWatchSession(t, creatorID, "Test Session", false)
 
 		// Get initial updated_at
-		var initialUpdatedAt string
+		var initialUpdatedAt time.Time
 		err := testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM watch_sessions WHERE id = $1",
 			sessionID,
@@ -256,7 +257,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		}
 
 		// Get new updated_at
-		var newUpdatedAt string
+		var newUpdatedAt time.Time
 		err = testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM watch_sessions WHERE id = $1",
 			sessionID,
@@ -265,8 +266,8 @@ func TestUpdatedAtTriggers(t *testing.T) {
 			t.Fatalf("Failed to get new updated_at: %v", err)
 		}
 
-		if newUpdatedAt == initialUpdatedAt {
-			t.Error("Expected updated_at to change after update")
+		if !newUpdatedAt.After(initialUpdatedAt) {
+			t.Error("Expected updated_at to advance after update")
 		}
 	})
 
@@ -281,7 +282,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		testDB.SeedVote(t, sessionID, userID, mediaID, "yes")
 
 		// Get initial updated_at
-		var initialUpdatedAt string
+		var initialUpdatedAt time.Time
 		err := testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
 			sessionID, userID, mediaID,
@@ -300,7 +301,7 @@ func TestUpdatedAtTriggers(t *testing.T) {
 		}
 
 		// Get new updated_at
-		var newUpdatedAt string
+		var newUpdatedAt time.Time
 		err = testDB.DB.QueryRowContext(ctx,
 			"SELECT updated_at FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
 			sessionID, userID, mediaID,
@@ -309,8 +310,8 @@ func TestUpdatedAtTriggers(t *testing.T) {
 			t.Fatalf("Failed to get new updated_at: %v", err)
 		}
 
-		if newUpdatedAt == initialUpdatedAt {
-			t.Error("Expected updated_at to change after update")
+		if !newUpdatedAt.After(initialUpdatedAt) {
+			t.Error("Expected updated_at to advance after update")
 		}
 	})
 }
@@ -3,18 +3,57 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // Vote represents a vote in the database
+// SeasonNumber is set when a vote is for one season of a TV show, and nil for movies
 type Vote struct {
-	SessionID uuid.UUID `json:"session_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	MediaID   uuid.UUID `json:"media_id"`
-	Vote      string    `json:"vote"`
-	CreatedAt string    `json:"created_at"`
+	SessionID    uuid.UUID `json:"session_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	MediaID      uuid.UUID `json:"media_id"`
+	SeasonNumber *int      `json:"season_number,omitempty"`
+	Vote         string    `json:"vote"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// VoteEvent is one entry in the append-only history of votes cast
+type VoteEvent struct {
+	ID           int64     `json:"id"`
+	SessionID    uuid.UUID `json:"session_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	MediaID      uuid.UUID `json:"media_id"`
+	SeasonNumber *int      `json:"season_number,omitempty"`
+	Vote         string    `json:"vote"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SocialMatch represents a match from a public session involving someone the user follows
+type SocialMatch struct {
+	SessionID   uuid.UUID `json:"session_id"`
+	SessionName *string   `json:"session_name,omitempty"`
+	Media       MediaItem `json:"media"`
+	MatchedAt   time.Time `json:"matched_at"`
+}
+
+// VoteInput is one vote in a batch passed to CastVotes
+type VoteInput struct {
+	MediaID      uuid.UUID `json:"media_id"`
+	SeasonNumber *int      `json:"season_number,omitempty"`
+	Vote         string    `json:"vote"`
+}
+
+// ErrInvalidVote is returned when a vote value is not "yes", "no", or "maybe"
+var ErrInvalidVote = errors.New("vote must be 'yes', 'no', or 'maybe'")
+
+// IsValidVote reports whether vote is one of the vote_type values
+func IsValidVote(vote string) bool {
+	return vote == "yes" || vote == "no" || vote == "maybe"
 }
 
 // VoteRepository handles vote-related database operations
@@ -28,26 +67,259 @@ func NewVoteRepository(db *sql.DB) *VoteRepository {
 }
 
 // CastVote inserts or updates a user's vote for a media item in a session
-func (r *VoteRepository) CastVote(ctx context.Context, sessionID, userID, mediaID uuid.UUID, vote string) error {
+// seasonNumber scopes the vote to one season of a TV show; pass nil for movies and whole-show votes
+func (r *VoteRepository) CastVote(ctx context.Context, sessionID, userID, mediaID uuid.UUID, vote string, seasonNumber *int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordVote(ctx, tx, sessionID, userID, mediaID, vote, seasonNumber); err != nil {
+		return fmt.Errorf("failed to cast vote: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CastVoteAndCheckMatch records a vote like CastVote and reports whether this vote created a match (2+ "yes" votes)
+// The vote and the match claim are one statement, so only the vote that claims the match reports it;
+// only a "yes" vote can report a match. seasonNumber scopes the vote as in CastVote
+func (r *VoteRepository) CastVoteAndCheckMatch(ctx context.Context, sessionID, userID, mediaID uuid.UUID, vote string, seasonNumber *int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Votes on the same media queue here, so each one's statement sees every earlier committed vote;
+	// without it two concurrent yes votes could each miss the other and neither would claim the match
+	lockQuery := `SELECT pg_advisory_xact_lock(hashtext($1::text || ':' || $2::text))`
+	if _, err := tx.ExecContext(ctx, lockQuery, sessionID, mediaID); err != nil {
+		return false, fmt.Errorf("failed to lock media votes: %w", err)
+	}
+
+	// The statement cannot see its own upsert, so the claim counts the other users' yes votes plus this one
+	query := `
+		WITH upserted AS (
+			INSERT INTO session_votes (session_id, user_id, media_id, vote, season_number)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (session_id, user_id, media_id, season_number)
+			DO UPDATE SET vote = EXCLUDED.vote, updated_at = NOW()
+		), logged AS (
+			INSERT INTO vote_events (session_id, user_id, media_id, vote, season_number)
+			VALUES ($1, $2, $3, $4, $5)
+		), claimed AS (
+			INSERT INTO session_matches (session_id, media_id)
+			SELECT $1, $3
+			WHERE $4 = 'yes'
+			AND EXISTS (
+				SELECT 1
+				FROM session_votes
+				WHERE session_id = $1
+				AND media_id = $3
+				AND user_id <> $2
+				AND vote = 'yes'
+			)
+			ON CONFLICT (session_id, media_id) DO NOTHING
+			RETURNING media_id
+		)
+		SELECT EXISTS (SELECT 1 FROM claimed)
+	`
+
+	var isMatch bool
+	if err := tx.QueryRowContext(ctx, query, sessionID, userID, mediaID, vote, seasonNumber).Scan(&isMatch); err != nil {
+		return false, fmt.Errorf("failed to cast vote: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return isMatch, nil
+}
+
+// ClaimMatch records that the media matched in the session if it has 2+ "yes" votes
+// Returns true only for the first caller to record it; the claim runs after the vote has committed,
+// so of two concurrent voters the later one always sees both votes and exactly one of them claims the match
+func (r *VoteRepository) ClaimMatch(ctx context.Context, sessionID, mediaID uuid.UUID) (bool, error) {
 	query := `
-		INSERT INTO session_votes (session_id, user_id, media_id, vote)
-		VALUES ($1, $2, $3, $4)
-		ON CONFLICT (session_id, user_id, media_id)
-		DO UPDATE SET vote = EXCLUDED.vote, created_at = NOW()
+		INSERT INTO session_matches (session_id, media_id)
+		SELECT $1, $2
+		WHERE (
+			SELECT COUNT(DISTINCT user_id)
+			FROM session_votes
+			WHERE session_id = $1
+			AND media_id = $2
+			AND vote = 'yes'
+		) >= 2
+		ON CONFLICT (session_id, media_id) DO NOTHING
 	`
 
-	_, err := r.db.ExecContext(ctx, query, sessionID, userID, mediaID, vote)
+	result, err := r.db.ExecContext(ctx, query, sessionID, mediaID)
 	if err != nil {
-		return fmt.Errorf("failed to cast vote: %w", err)
+		return false, fmt.Errorf("failed to claim match: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check match claim result: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// CastVotes inserts or updates a batch of a user's votes in a session in a single transaction
+// Every vote is validated first, so one bad value rejects the batch with ErrInvalidVote before anything is written
+func (r *VoteRepository) CastVotes(ctx context.Context, sessionID, userID uuid.UUID, votes []VoteInput) error {
+	for _, v := range votes {
+		if !IsValidVote(v.Vote) {
+			return fmt.Errorf("invalid vote for media %s: %w", v.MediaID, ErrInvalidVote)
+		}
+	}
+
+	if len(votes) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, v := range votes {
+		if err := recordVote(ctx, tx, sessionID, userID, v.MediaID, v.Vote, v.SeasonNumber); err != nil {
+			return fmt.Errorf("failed to cast vote for media %s: %w", v.MediaID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// recordVote upserts the user's current vote and appends it to vote_events within tx
+func recordVote(ctx context.Context, tx *sql.Tx, sessionID, userID, mediaID uuid.UUID, vote string, seasonNumber *int) error {
+	upsertQuery := `
+		INSERT INTO session_votes (session_id, user_id, media_id, vote, season_number)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (session_id, user_id, media_id, season_number)
+		DO UPDATE SET vote = EXCLUDED.vote, updated_at = NOW()
+	`
+
+	if _, err := tx.ExecContext(ctx, upsertQuery, sessionID, userID, mediaID, vote, seasonNumber); err != nil {
+		return err
+	}
+
+	eventQuery := `
+		INSERT INTO vote_events (session_id, user_id, media_id, vote, season_number)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := tx.ExecContext(ctx, eventQuery, sessionID, userID, mediaID, vote, seasonNumber); err != nil {
+		return fmt.Errorf("failed to record vote event: %w", err)
+	}
+
+	return nil
+}
+
+// GetVoteHistory retrieves every vote a user has cast in a session, oldest first
+// Changed votes appear once per change, unlike session_votes which keeps only the latest
+func (r *VoteRepository) GetVoteHistory(ctx context.Context, sessionID, userID uuid.UUID) ([]VoteEvent, error) {
+	query := `
+		SELECT id, session_id, user_id, media_id, season_number, vote, created_at
+		FROM vote_events
+		WHERE session_id = $1 AND user_id = $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote history: %w", err)
+	}
+	defer rows.Close()
+
+	var events []VoteEvent
+	for rows.Next() {
+		var event VoteEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.SessionID,
+			&event.UserID,
+			&event.MediaID,
+			&event.SeasonNumber,
+			&event.Vote,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vote events: %w", err)
+	}
+
+	return events, nil
+}
+
+// CloneCandidates copies a session's candidate deck into another session and returns how many were added
+// Candidates are the source's queued titles plus any title voted on there; the votes themselves are not copied
+func (r *VoteRepository) CloneCandidates(ctx context.Context, fromSessionID, toSessionID uuid.UUID) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cloned, err := cloneCandidates(ctx, tx, fromSessionID, toSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return cloned, nil
+}
+
+// cloneCandidates copies fromSessionID's candidate deck into toSessionID within tx
+func cloneCandidates(ctx context.Context, tx *sql.Tx, fromSessionID, toSessionID uuid.UUID) (int, error) {
+	query := `
+		INSERT INTO session_candidates (session_id, media_id)
+		SELECT $2, media_id FROM session_candidates WHERE session_id = $1
+		UNION
+		SELECT $2, media_id FROM session_votes WHERE session_id = $1
+		ON CONFLICT (session_id, media_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, fromSessionID, toSessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clone candidates: %w", err)
+	}
+
+	cloned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(cloned), nil
+}
+
 // CheckMatch checks if there's a match (2+ "yes" votes) for a media item in a session
+// Votes are counted per user, so one user voting "yes" on several seasons of a show is not a match
 func (r *VoteRepository) CheckMatch(ctx context.Context, sessionID, mediaID uuid.UUID) (bool, error) {
 	query := `
-		SELECT COUNT(*)
+		SELECT COUNT(DISTINCT user_id)
 		FROM session_votes
 		WHERE session_id = $1
 		AND media_id = $2
@@ -63,24 +335,130 @@ func (r *VoteRepository) CheckMatch(ctx context.Context, sessionID, mediaID uuid
 	return count >= 2, nil
 }
 
+// CheckMatches checks many media items for a match at once, in a single grouped query
+// Every requested ID is present in the result; media without 2+ "yes" votes map to false
+func (r *VoteRepository) CheckMatches(ctx context.Context, sessionID uuid.UUID, mediaIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	matches := make(map[uuid.UUID]bool, len(mediaIDs))
+	if len(mediaIDs) == 0 {
+		return matches, nil
+	}
+
+	placeholders := make([]string, 0, len(mediaIDs))
+	args := make([]interface{}, 0, len(mediaIDs)+1)
+	args = append(args, sessionID)
+	for i, mediaID := range mediaIDs {
+		matches[mediaID] = false
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
+		args = append(args, mediaID)
+	}
+
+	query := `
+		SELECT media_id
+		FROM session_votes
+		WHERE session_id = $1
+		AND media_id IN (` + strings.Join(placeholders, ", ") + `)
+		AND vote = 'yes'
+		GROUP BY media_id
+		HAVING COUNT(DISTINCT user_id) >= 2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check matches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mediaID uuid.UUID
+		if err := rows.Scan(&mediaID); err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		matches[mediaID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// CheckMatchDetail checks for a match like CheckMatch and also returns the media item when the
+// media just crossed the 2 "yes" vote threshold, i.e. the latest vote created the match
+func (r *VoteRepository) CheckMatchDetail(ctx context.Context, sessionID, mediaID uuid.UUID) (*MediaItem, bool, error) {
+	query := `
+		SELECT
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at,
+			(
+				SELECT COUNT(DISTINCT user_id)
+				FROM session_votes
+				WHERE session_id = $1
+				AND media_id = m.id
+				AND vote = 'yes'
+			)
+		FROM media_items m
+		WHERE m.id = $2
+	`
+
+	var item MediaItem
+	var yesCount int
+	err := r.db.QueryRowContext(ctx, query, sessionID, mediaID).Scan(
+		&item.ID,
+		&item.TMDBID,
+		&item.MediaType,
+		&item.Title,
+		&item.Metadata,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&yesCount,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check match: %w", err)
+	}
+
+	if yesCount != 2 {
+		return nil, yesCount > 2, nil
+	}
+
+	return &item, true, nil
+}
+
+// MatchResult is a matched media item with how many participants voted "yes" on it
+type MatchResult struct {
+	MediaItem
+	YesCount int `json:"yes_count"`
+}
+
 // GetMatchesForSession retrieves all media items with 2+ "yes" votes in a session
-func (r *VoteRepository) GetMatchesForSession(ctx context.Context, sessionID uuid.UUID) ([]MediaItem, error) {
+// The most popular matches come first, with ties broken by title
+func (r *VoteRepository) GetMatchesForSession(ctx context.Context, sessionID uuid.UUID) ([]MatchResult, error) {
 	query := `
-		SELECT DISTINCT
+		SELECT
 			m.id,
 			m.tmdb_id,
 			m.media_type,
 			m.title,
 			m.metadata,
 			m.created_at,
-			m.updated_at
+			m.updated_at,
+			COUNT(DISTINCT sv.user_id) AS yes_count
 		FROM media_items m
 		INNER JOIN session_votes sv ON m.id = sv.media_id
 		WHERE sv.session_id = $1
 		AND sv.vote = 'yes'
 		GROUP BY m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
-		HAVING COUNT(sv.user_id) >= 2
-		ORDER BY m.title
+		HAVING COUNT(DISTINCT sv.user_id) >= 2
+		ORDER BY yes_count DESC, m.title
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -89,7 +467,273 @@ func (r *VoteRepository) GetMatchesForSession(ctx context.Context, sessionID uui
 	}
 	defer rows.Close()
 
-	var matches []MediaItem
+	var matches []MatchResult
+	for rows.Next() {
+		var match MatchResult
+		err := rows.Scan(
+			&match.ID,
+			&match.TMDBID,
+			&match.MediaType,
+			&match.Title,
+			&match.Metadata,
+			&match.CreatedAt,
+			&match.UpdatedAt,
+			&match.YesCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// GetMatchVoters returns the profiles of the users who voted "yes" on a media item, earliest vote first
+// Each voter appears once even if they voted "yes" on several seasons, and guests appear without a username;
+// returns nil when fewer than 2 users voted "yes" and so the media is not a match
+func (r *VoteRepository) GetMatchVoters(ctx context.Context, sessionID, mediaID uuid.UUID) ([]Profile, error) {
+	query := `
+		SELECT sv.user_id, p.username, COALESCE(p.invite_preference, 'none'),
+			COALESCE(p.created_at, g.created_at), COALESCE(p.updated_at, g.created_at)
+		FROM session_votes sv
+		LEFT JOIN profiles p ON p.id = sv.user_id
+		LEFT JOIN guest_voters g ON g.id = sv.user_id
+		WHERE sv.session_id = $1
+		AND sv.media_id = $2
+		AND sv.vote = 'yes'
+		AND (p.id IS NOT NULL OR g.id IS NOT NULL)
+		GROUP BY sv.user_id, p.username, p.invite_preference, p.created_at, p.updated_at, g.created_at
+		ORDER BY MIN(sv.updated_at), p.username
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match voters: %w", err)
+	}
+	defer rows.Close()
+
+	var voters []Profile
+	for rows.Next() {
+		var profile Profile
+		err := rows.Scan(
+			&profile.UserID,
+			&profile.Username,
+			&profile.InvitePreference,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match voter: %w", err)
+		}
+		voters = append(voters, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating match voters: %w", err)
+	}
+
+	if len(voters) < 2 {
+		return nil, nil
+	}
+
+	return voters, nil
+}
+
+// GetSoftMatchesForSession retrieves media that nobody rejected: no "no" votes and at least
+// two "yes" or "maybe" votes. YesCount counts only the "yes" votes; ordering matches GetMatchesForSession
+func (r *VoteRepository) GetSoftMatchesForSession(ctx context.Context, sessionID uuid.UUID) ([]MatchResult, error) {
+	query := `
+		SELECT
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at,
+			COUNT(DISTINCT sv.user_id) FILTER (WHERE sv.vote = 'yes') AS yes_count
+		FROM media_items m
+		INNER JOIN session_votes sv ON m.id = sv.media_id
+		WHERE sv.session_id = $1
+		GROUP BY m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
+		HAVING COUNT(*) FILTER (WHERE sv.vote = 'no') = 0
+		AND COUNT(DISTINCT sv.user_id) FILTER (WHERE sv.vote IN ('yes', 'maybe')) >= 2
+		ORDER BY yes_count DESC, m.title
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get soft matches: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []MatchResult
+	for rows.Next() {
+		var match MatchResult
+		err := rows.Scan(
+			&match.ID,
+			&match.TMDBID,
+			&match.MediaType,
+			&match.Title,
+			&match.Metadata,
+			&match.CreatedAt,
+			&match.UpdatedAt,
+			&match.YesCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan soft match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating soft matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+const (
+	// DefaultLikedMoviesLimit is the page size used when GetLikedMovies gets no limit
+	DefaultLikedMoviesLimit = 20
+	// MaxLikedMoviesLimit caps the page size of GetLikedMovies
+	MaxLikedMoviesLimit = 100
+)
+
+// MarkMatchesViewed records that a user has just viewed a session's matches
+func (r *VoteRepository) MarkMatchesViewed(ctx context.Context, sessionID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO session_views (session_id, user_id, last_viewed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (session_id, user_id)
+		DO UPDATE SET last_viewed_at = EXCLUDED.last_viewed_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark matches viewed: %w", err)
+	}
+
+	return nil
+}
+
+// CountNewMatchesSince counts the session's matches made since the user last viewed them
+// A match is dated by when it was first claimed, so changing a vote on an old match does not make it new again;
+// every match is new if the user never viewed them
+func (r *VoteRepository) CountNewMatchesSince(ctx context.Context, sessionID, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM session_matches sm
+		WHERE sm.session_id = $1
+		AND sm.matched_at > COALESCE(
+			(SELECT last_viewed_at FROM session_views WHERE session_id = $1 AND user_id = $2),
+			'-infinity'::timestamptz
+		)
+		AND (
+			SELECT COUNT(DISTINCT sv.user_id)
+			FROM session_votes sv
+			WHERE sv.session_id = sm.session_id
+			AND sv.media_id = sm.media_id
+			AND sv.vote = 'yes'
+		) >= 2
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count new matches: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLikedMovies retrieves the titles of movies with a "yes" vote in the session
+// Titles are unique and ordered by the most recent "yes" vote first
+func (r *VoteRepository) GetLikedMovies(ctx context.Context, sessionID uuid.UUID, limit, offset int) ([]string, error) {
+	if limit <= 0 {
+		limit = DefaultLikedMoviesLimit
+	}
+	if limit > MaxLikedMoviesLimit {
+		limit = MaxLikedMoviesLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+		SELECT m.title
+		FROM session_votes sv
+		JOIN media_items m ON sv.media_id = m.id
+		WHERE sv.session_id = $1 AND sv.vote = 'yes'
+		GROUP BY m.id, m.title
+		ORDER BY MAX(sv.created_at) DESC, m.title
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liked movies: %w", err)
+	}
+	defer rows.Close()
+
+	var titles []string
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, fmt.Errorf("failed to scan title: %w", err)
+		}
+		titles = append(titles, title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return titles, nil
+}
+
+// GetLikedMediaItems retrieves the media items with a "yes" vote in the session
+// Items are unique and ordered like GetLikedMovies, most recent "yes" vote first
+func (r *VoteRepository) GetLikedMediaItems(ctx context.Context, sessionID uuid.UUID, limit, offset int) ([]MediaItem, error) {
+	if limit <= 0 {
+		limit = DefaultLikedMoviesLimit
+	}
+	if limit > MaxLikedMoviesLimit {
+		limit = MaxLikedMoviesLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+		SELECT
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at
+		FROM session_votes sv
+		JOIN media_items m ON sv.media_id = m.id
+		WHERE sv.session_id = $1 AND sv.vote = 'yes'
+		GROUP BY m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
+		ORDER BY MAX(sv.created_at) DESC, m.title
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liked media: %w", err)
+	}
+	defer rows.Close()
+
+	var items []MediaItem
 	for rows.Next() {
 		var item MediaItem
 		err := rows.Scan(
@@ -102,31 +746,59 @@ func (r *VoteRepository) GetMatchesForSession(ctx context.Context, sessionID uui
 			&item.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan match: %w", err)
+			return nil, fmt.Errorf("failed to scan liked media: %w", err)
 		}
-		matches = append(matches, item)
+		items = append(items, item)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating matches: %w", err)
+		return nil, fmt.Errorf("error iterating liked media: %w", err)
 	}
 
-	return matches, nil
+	return items, nil
+}
+
+// CountLikedMovies counts the distinct media with at least one "yes" vote in the session
+func (r *VoteRepository) CountLikedMovies(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT media_id)
+		FROM session_votes
+		WHERE session_id = $1 AND vote = 'yes'
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count liked movies: %w", err)
+	}
+
+	return count, nil
 }
 
-// GetLikedMovies retrieves all movies with a "yes" vote in the session, along with their titles
-func (r *VoteRepository) GetLikedMovies(ctx context.Context, sessionID uuid.UUID) ([]string, error) {
+// GetGroupLikedMovies retrieves the titles of liked movies in the session, favoring those
+// liked by the most distinct voters so one prolific voter cannot dominate the list
+// Ties fall back to the most recent "yes" vote
+func (r *VoteRepository) GetGroupLikedMovies(ctx context.Context, sessionID uuid.UUID, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = DefaultLikedMoviesLimit
+	}
+	if limit > MaxLikedMoviesLimit {
+		limit = MaxLikedMoviesLimit
+	}
+
 	query := `
-		SELECT DISTINCT m.title
+		SELECT m.title
 		FROM session_votes sv
 		JOIN media_items m ON sv.media_id = m.id
 		WHERE sv.session_id = $1 AND sv.vote = 'yes'
-		ORDER BY m.title
+		GROUP BY m.id, m.title
+		ORDER BY COUNT(DISTINCT sv.user_id) DESC, MAX(sv.created_at) DESC, m.title
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	rows, err := r.db.QueryContext(ctx, query, sessionID, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query liked movies: %w", err)
+		return nil, fmt.Errorf("failed to query group liked movies: %w", err)
 	}
 	defer rows.Close()
 
@@ -145,3 +817,346 @@ func (r *VoteRepository) GetLikedMovies(ctx context.Context, sessionID uuid.UUID
 
 	return titles, nil
 }
+
+const (
+	// DefaultDislikedMediaLimit is the page size used when GetUserDislikedMedia gets no limit
+	DefaultDislikedMediaLimit = 20
+	// MaxDislikedMediaLimit caps the page size of GetUserDislikedMedia
+	MaxDislikedMediaLimit = 100
+)
+
+// GetUserDislikedMedia retrieves the distinct media a user voted "no" on across all sessions,
+// most recently disliked first
+func (r *VoteRepository) GetUserDislikedMedia(ctx context.Context, userID uuid.UUID, limit, offset int) ([]MediaItem, error) {
+	if limit <= 0 {
+		limit = DefaultDislikedMediaLimit
+	}
+	if limit > MaxDislikedMediaLimit {
+		limit = MaxDislikedMediaLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+		SELECT
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at
+		FROM media_items m
+		INNER JOIN session_votes sv ON m.id = sv.media_id
+		WHERE sv.user_id = $1
+		AND sv.vote = 'no'
+		GROUP BY m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
+		ORDER BY MAX(sv.created_at) DESC, m.title
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disliked media: %w", err)
+	}
+	defer rows.Close()
+
+	var disliked []MediaItem
+	for rows.Next() {
+		var item MediaItem
+		err := rows.Scan(
+			&item.ID,
+			&item.TMDBID,
+			&item.MediaType,
+			&item.Title,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan disliked media: %w", err)
+		}
+		disliked = append(disliked, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating disliked media: %w", err)
+	}
+
+	return disliked, nil
+}
+
+// MaxUserLikedMedia caps how much of a user's like history GetAllLikedByUser returns
+const MaxUserLikedMedia = 500
+
+// GetAllLikedByUser retrieves the distinct media a user voted "yes" on across all sessions,
+// most recently liked first; unlike GetLikedMediaItems only the user's own votes count
+func (r *VoteRepository) GetAllLikedByUser(ctx context.Context, userID uuid.UUID) ([]MediaItem, error) {
+	query := `
+		SELECT
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at
+		FROM media_items m
+		INNER JOIN session_votes sv ON m.id = sv.media_id
+		WHERE sv.user_id = $1
+		AND sv.vote = 'yes'
+		GROUP BY m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
+		ORDER BY MAX(sv.created_at) DESC, m.title
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, MaxUserLikedMedia)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liked media: %w", err)
+	}
+	defer rows.Close()
+
+	var liked []MediaItem
+	for rows.Next() {
+		var item MediaItem
+		err := rows.Scan(
+			&item.ID,
+			&item.TMDBID,
+			&item.MediaType,
+			&item.Title,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan liked media: %w", err)
+		}
+		liked = append(liked, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating liked media: %w", err)
+	}
+
+	return liked, nil
+}
+
+// CountUserMatches counts distinct media a user voted "yes" on that reached a match in the same session
+func (r *VoteRepository) CountUserMatches(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT sv.media_id)
+		FROM session_votes sv
+		WHERE sv.user_id = $1
+		AND sv.vote = 'yes'
+		AND (
+			SELECT COUNT(DISTINCT other.user_id)
+			FROM session_votes other
+			WHERE other.session_id = sv.session_id
+			AND other.media_id = sv.media_id
+			AND other.vote = 'yes'
+		) >= 2
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user matches: %w", err)
+	}
+
+	return count, nil
+}
+
+// FollowingMatchesWindow is how far back GetFollowingMatches looks for matches
+const FollowingMatchesWindow = 30 * 24 * time.Hour
+
+// GetFollowingMatches retrieves recent matches from public sessions created or joined by users that userID follows
+// Only matches made within FollowingMatchesWindow are returned, newest first
+func (r *VoteRepository) GetFollowingMatches(ctx context.Context, userID uuid.UUID, limit int) ([]SocialMatch, error) {
+	query := `
+		SELECT
+			ws.id,
+			ws.name,
+			m.id,
+			m.tmdb_id,
+			m.media_type,
+			m.title,
+			m.metadata,
+			m.created_at,
+			m.updated_at,
+			sm.matched_at
+		FROM session_matches sm
+		INNER JOIN watch_sessions ws ON ws.id = sm.session_id
+		INNER JOIN media_items m ON m.id = sm.media_id
+		WHERE ws.is_public = true
+		AND sm.matched_at > NOW() - make_interval(secs => $3)
+		AND EXISTS (
+			SELECT 1 FROM user_follows uf
+			WHERE uf.follower_id = $1
+			AND uf.deleted_at IS NULL
+			AND (
+				uf.following_id = ws.creator_id
+				OR EXISTS (
+					SELECT 1 FROM room_participants rp
+					WHERE rp.room_id = ws.id AND rp.user_id = uf.following_id
+					AND rp.status = 'joined'
+				)
+				OR EXISTS (
+					SELECT 1 FROM session_votes v
+					WHERE v.session_id = ws.id AND v.user_id = uf.following_id
+				)
+			)
+		)
+		ORDER BY sm.matched_at DESC, m.title
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, FollowingMatchesWindow.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get following matches: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []SocialMatch
+	for rows.Next() {
+		var match SocialMatch
+		err := rows.Scan(
+			&match.SessionID,
+			&match.SessionName,
+			&match.Media.ID,
+			&match.Media.TMDBID,
+			&match.Media.MediaType,
+			&match.Media.Title,
+			&match.Media.Metadata,
+			&match.Media.CreatedAt,
+			&match.Media.UpdatedAt,
+			&match.MatchedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan following match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating following matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// FindOrphanedVotes returns votes whose media_id no longer resolves to a media item
+func (r *VoteRepository) FindOrphanedVotes(ctx context.Context) ([]Vote, error) {
+	query := `
+		SELECT sv.session_id, sv.user_id, sv.media_id, sv.season_number, sv.vote, sv.created_at
+		FROM session_votes sv
+		LEFT JOIN media_items m ON sv.media_id = m.id
+		WHERE m.id IS NULL
+		ORDER BY sv.created_at, sv.session_id, sv.user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphaned votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []Vote
+	for rows.Next() {
+		var vote Vote
+		err := rows.Scan(
+			&vote.SessionID,
+			&vote.UserID,
+			&vote.MediaID,
+			&vote.SeasonNumber,
+			&vote.Vote,
+			&vote.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return votes, nil
+}
+
+// GetConsensusScore returns how much a session's group agreed, from 0 (no matches) to 1 (every candidate matched)
+// The score is the share of voted-on candidates that reached a match; sessions without votes score 0
+func (r *VoteRepository) GetConsensusScore(ctx context.Context, sessionID uuid.UUID) (float64, error) {
+	query := `
+		SELECT COALESCE(
+			COUNT(*) FILTER (WHERE yes_votes >= 2)::float8 / NULLIF(COUNT(*), 0),
+			0
+		)
+		FROM (
+			SELECT media_id, COUNT(DISTINCT user_id) FILTER (WHERE vote = 'yes') AS yes_votes
+			FROM session_votes
+			WHERE session_id = $1
+			GROUP BY media_id
+		) candidates
+	`
+
+	var score float64
+	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(&score)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get consensus score: %w", err)
+	}
+
+	return score, nil
+}
+
+// GetSessionMatchIntersection returns media that matched in both sessions, ordered by title
+func (r *VoteRepository) GetSessionMatchIntersection(ctx context.Context, sessionAID, sessionBID uuid.UUID) ([]MediaItem, error) {
+	query := `
+		SELECT m.id, m.tmdb_id, m.media_type, m.title, m.metadata, m.created_at, m.updated_at
+		FROM media_items m
+		WHERE m.id IN (
+			SELECT media_id FROM session_votes
+			WHERE session_id = $1 AND vote = 'yes'
+			GROUP BY media_id
+			HAVING COUNT(DISTINCT user_id) >= 2
+		)
+		AND m.id IN (
+			SELECT media_id FROM session_votes
+			WHERE session_id = $2 AND vote = 'yes'
+			GROUP BY media_id
+			HAVING COUNT(DISTINCT user_id) >= 2
+		)
+		ORDER BY m.title
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionAID, sessionBID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match intersection: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []MediaItem
+	for rows.Next() {
+		var item MediaItem
+		err := rows.Scan(
+			&item.ID,
+			&item.TMDBID,
+			&item.MediaType,
+			&item.Title,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		matches = append(matches, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating matches: %w", err)
+	}
+
+	return matches, nil
+}
@@ -2,7 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/testutils"
@@ -27,7 +31,7 @@ func TestVoteRepository_CastVote(t *testing.T) {
 	mediaID := testDB.SeedMediaItem(t, 123, "movie", "Test Movie")
 
 	t.Run("successfully casts a yes vote", func(t *testing.T) {
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes")
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes", nil)
 		if err != nil {
 			t.Fatalf("CastVote failed: %v", err)
 		}
@@ -51,7 +55,7 @@ func TestVoteRepository_CastVote(t *testing.T) {
 	t.Run("successfully casts a no vote", func(t *testing.T) {
 		media2ID := testDB.SeedMediaItem(t, 456, "movie", "Another Movie")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, media2ID, "no")
+		err := repo.CastVote(ctx, sessionID, user1ID, media2ID, "no", nil)
 		if err != nil {
 			t.Fatalf("CastVote failed: %v", err)
 		}
@@ -74,7 +78,7 @@ func TestVoteRepository_CastVote(t *testing.T) {
 	t.Run("successfully casts a maybe vote", func(t *testing.T) {
 		media3ID := testDB.SeedMediaItem(t, 789, "movie", "Maybe Movie")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, media3ID, "maybe")
+		err := repo.CastVote(ctx, sessionID, user1ID, media3ID, "maybe", nil)
 		if err != nil {
 			t.Fatalf("CastVote failed: %v", err)
 		}
@@ -98,13 +102,13 @@ func TestVoteRepository_CastVote(t *testing.T) {
 		media4ID := testDB.SeedMediaItem(t, 111, "movie", "Update Movie")
 
 		// Cast initial vote
-		err := repo.CastVote(ctx, sessionID, user1ID, media4ID, "yes")
+		err := repo.CastVote(ctx, sessionID, user1ID, media4ID, "yes", nil)
 		if err != nil {
 			t.Fatalf("First CastVote failed: %v", err)
 		}
 
 		// Change vote
-		err = repo.CastVote(ctx, sessionID, user1ID, media4ID, "no")
+		err = repo.CastVote(ctx, sessionID, user1ID, media4ID, "no", nil)
 		if err != nil {
 			t.Fatalf("Second CastVote failed: %v", err)
 		}
@@ -140,17 +144,59 @@ func TestVoteRepository_CastVote(t *testing.T) {
 		}
 	})
 
+	t.Run("preserves created_at and advances updated_at on vote change", func(t *testing.T) {
+		media7ID := testDB.SeedMediaItem(t, 444, "movie", "Changed Mind Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, media7ID, "yes", nil)
+		if err != nil {
+			t.Fatalf("First CastVote failed: %v", err)
+		}
+
+		// Backdate the original vote so a rewrite of created_at would be visible
+		setVoteTime(t, testDB, sessionID, user1ID, media7ID, "1 hour")
+
+		var createdBefore, updatedBefore time.Time
+		err = testDB.DB.QueryRow(
+			"SELECT created_at, updated_at FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
+			sessionID, user1ID, media7ID,
+		).Scan(&createdBefore, &updatedBefore)
+		if err != nil {
+			t.Fatalf("Failed to retrieve vote timestamps: %v", err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user1ID, media7ID, "no", nil)
+		if err != nil {
+			t.Fatalf("Second CastVote failed: %v", err)
+		}
+
+		var createdAfter, updatedAfter time.Time
+		err = testDB.DB.QueryRow(
+			"SELECT created_at, updated_at FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
+			sessionID, user1ID, media7ID,
+		).Scan(&createdAfter, &updatedAfter)
+		if err != nil {
+			t.Fatalf("Failed to retrieve vote timestamps: %v", err)
+		}
+
+		if !createdAfter.Equal(createdBefore) {
+			t.Errorf("Expected created_at to stay %v, got %v", createdBefore, createdAfter)
+		}
+		if !updatedAfter.After(updatedBefore) {
+			t.Errorf("Expected updated_at to advance past %v, got %v", updatedBefore, updatedAfter)
+		}
+	})
+
 	t.Run("allows different users to vote on same media", func(t *testing.T) {
 		media5ID := testDB.SeedMediaItem(t, 222, "movie", "Popular Movie")
 
 		// User1 votes yes
-		err := repo.CastVote(ctx, sessionID, user1ID, media5ID, "yes")
+		err := repo.CastVote(ctx, sessionID, user1ID, media5ID, "yes", nil)
 		if err != nil {
 			t.Fatalf("User1 CastVote failed: %v", err)
 		}
 
 		// User2 votes yes
-		err = repo.CastVote(ctx, sessionID, user2ID, media5ID, "yes")
+		err = repo.CastVote(ctx, sessionID, user2ID, media5ID, "yes", nil)
 		if err != nil {
 			t.Fatalf("User2 CastVote failed: %v", err)
 		}
@@ -174,14 +220,14 @@ func TestVoteRepository_CastVote(t *testing.T) {
 	t.Run("fails with invalid vote type", func(t *testing.T) {
 		media6ID := testDB.SeedMediaItem(t, 333, "movie", "Invalid Vote Movie")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, media6ID, "invalid")
+		err := repo.CastVote(ctx, sessionID, user1ID, media6ID, "invalid", nil)
 		if err == nil {
 			t.Error("Expected CastVote to fail with invalid vote type")
 		}
 	})
 }
 
-func TestVoteRepository_CheckMatch(t *testing.T) {
+func TestVoteRepository_CastVote_Seasons(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
 	defer testDB.Cleanup(t)
@@ -189,148 +235,187 @@ func TestVoteRepository_CheckMatch(t *testing.T) {
 	repo := NewVoteRepository(testDB.DB)
 	ctx := context.Background()
 
-	// Setup: Create users, session, and media
-	user1ID := uuid.New()
-	testDB.SeedProfile(t, user1ID, "user1")
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "season_voter")
 
-	user2ID := uuid.New()
-	testDB.SeedProfile(t, user2ID, "user2")
+	sessionID := testDB.SeedWatchSession(t, userID, "Season Session", false)
+	showID := testDB.SeedMediaItem(t, 1396, "tv", "Test Show")
 
-	user3ID := uuid.New()
-	testDB.SeedProfile(t, user3ID, "user3")
+	countVotes := func(t *testing.T, mediaID uuid.UUID) int {
+		t.Helper()
+		var count int
+		err := testDB.DB.QueryRow(
+			"SELECT COUNT(*) FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
+			sessionID, userID, mediaID,
+		).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to count votes: %v", err)
+		}
+		return count
+	}
 
-	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	seasonOne, seasonTwo := 1, 2
 
-	t.Run("returns false when no votes", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 100, "movie", "No Votes Movie")
+	t.Run("votes on two seasons of a show are distinct rows", func(t *testing.T) {
+		if err := repo.CastVote(ctx, sessionID, userID, showID, "yes", &seasonOne); err != nil {
+			t.Fatalf("CastVote(season 1) failed: %v", err)
+		}
+		if err := repo.CastVote(ctx, sessionID, userID, showID, "no", &seasonTwo); err != nil {
+			t.Fatalf("CastVote(season 2) failed: %v", err)
+		}
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
-		if err != nil {
-			t.Fatalf("CheckMatch failed: %v", err)
+		if count := countVotes(t, showID); count != 2 {
+			t.Fatalf("Expected 2 votes, got %d", count)
 		}
 
-		if isMatch {
-			t.Error("Expected no match with no votes")
+		var vote string
+		err := testDB.DB.QueryRow(
+			"SELECT vote FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3 AND season_number = $4",
+			sessionID, userID, showID, seasonOne,
+		).Scan(&vote)
+		if err != nil {
+			t.Fatalf("Failed to retrieve season 1 vote: %v", err)
+		}
+		if vote != "yes" {
+			t.Errorf("Expected season 1 vote 'yes', got '%s'", vote)
 		}
 	})
 
-	t.Run("returns false with only one yes vote", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 200, "movie", "One Vote Movie")
-
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes")
-		if err != nil {
+	t.Run("revoting a season updates its row", func(t *testing.T) {
+		if err := repo.CastVote(ctx, sessionID, userID, showID, "maybe", &seasonTwo); err != nil {
 			t.Fatalf("CastVote failed: %v", err)
 		}
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
-		if err != nil {
-			t.Fatalf("CheckMatch failed: %v", err)
+		if count := countVotes(t, showID); count != 2 {
+			t.Fatalf("Expected 2 votes, got %d", count)
 		}
 
-		if isMatch {
-			t.Error("Expected no match with only one yes vote")
+		var vote string
+		err := testDB.DB.QueryRow(
+			"SELECT vote FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3 AND season_number = $4",
+			sessionID, userID, showID, seasonTwo,
+		).Scan(&vote)
+		if err != nil {
+			t.Fatalf("Failed to retrieve season 2 vote: %v", err)
+		}
+		if vote != "maybe" {
+			t.Errorf("Expected season 2 vote 'maybe', got '%s'", vote)
 		}
 	})
 
-	t.Run("returns true with two yes votes", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 300, "movie", "Match Movie")
+	t.Run("a whole-show vote sits alongside season votes", func(t *testing.T) {
+		if err := repo.CastVote(ctx, sessionID, userID, showID, "yes", nil); err != nil {
+			t.Fatalf("CastVote failed: %v", err)
+		}
 
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes")
-		if err != nil {
-			t.Fatalf("User1 CastVote failed: %v", err)
+		if count := countVotes(t, showID); count != 3 {
+			t.Errorf("Expected 3 votes, got %d", count)
 		}
+	})
 
-		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "yes")
-		if err != nil {
-			t.Fatalf("User2 CastVote failed: %v", err)
+	t.Run("one user voting yes on two seasons is not a match", func(t *testing.T) {
+		if err := repo.CastVote(ctx, sessionID, userID, showID, "yes", &seasonTwo); err != nil {
+			t.Fatalf("CastVote failed: %v", err)
 		}
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		isMatch, err := repo.CheckMatch(ctx, sessionID, showID)
 		if err != nil {
 			t.Fatalf("CheckMatch failed: %v", err)
 		}
-
-		if !isMatch {
-			t.Error("Expected match with two yes votes")
+		if isMatch {
+			t.Error("Expected no match from a single user's season votes")
 		}
-	})
-
-	t.Run("returns true with three yes votes", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 400, "movie", "Three Votes Movie")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes")
+		matches, err := repo.CheckMatches(ctx, sessionID, []uuid.UUID{showID})
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("CheckMatches failed: %v", err)
+		}
+		if matches[showID] {
+			t.Error("Expected CheckMatches to report no match")
 		}
 
-		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "yes")
+		claimed, err := repo.ClaimMatch(ctx, sessionID, showID)
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("ClaimMatch failed: %v", err)
+		}
+		if claimed {
+			t.Error("Expected no match to be claimed")
 		}
 
-		err = repo.CastVote(ctx, sessionID, user3ID, mediaID, "yes")
+		sessionMatches, err := repo.GetMatchesForSession(ctx, sessionID)
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+		if len(sessionMatches) != 0 {
+			t.Errorf("Expected no session matches, got %d", len(sessionMatches))
 		}
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		voters, err := repo.GetMatchVoters(ctx, sessionID, showID)
 		if err != nil {
-			t.Fatalf("CheckMatch failed: %v", err)
+			t.Fatalf("GetMatchVoters failed: %v", err)
 		}
-
-		if !isMatch {
-			t.Error("Expected match with three yes votes")
+		if voters != nil {
+			t.Errorf("Expected no voters for a non-match, got %d", len(voters))
 		}
 	})
 
-	t.Run("returns false when no votes count as no match", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 500, "movie", "No Votes Movie")
+	t.Run("a second user's season vote makes a match with each voter listed once", func(t *testing.T) {
+		otherID := uuid.New()
+		testDB.SeedProfile(t, otherID, "season_friend")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "no")
-		if err != nil {
-			t.Fatal(err)
+		if err := repo.CastVote(ctx, sessionID, otherID, showID, "yes", &seasonOne); err != nil {
+			t.Fatalf("CastVote failed: %v", err)
 		}
 
-		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "no")
+		voters, err := repo.GetMatchVoters(ctx, sessionID, showID)
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("GetMatchVoters failed: %v", err)
+		}
+		if len(voters) != 2 {
+			t.Errorf("Expected 2 distinct voters, got %d", len(voters))
 		}
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		sessionMatches, err := repo.GetMatchesForSession(ctx, sessionID)
 		if err != nil {
-			t.Fatalf("CheckMatch failed: %v", err)
+			t.Fatalf("GetMatchesForSession failed: %v", err)
 		}
-
-		if isMatch {
-			t.Error("Expected no match with only no votes")
+		if len(sessionMatches) != 1 || sessionMatches[0].YesCount != 2 {
+			t.Errorf("Expected one match with 2 yes votes, got %+v", sessionMatches)
 		}
 	})
 
-	t.Run("ignores maybe votes in match calculation", func(t *testing.T) {
-		mediaID := testDB.SeedMediaItem(t, 600, "movie", "Maybe Movie")
+	t.Run("votes without a season stay unique", func(t *testing.T) {
+		movieID := testDB.SeedMediaItem(t, 1397, "movie", "Season-less Movie")
 
-		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes")
-		if err != nil {
-			t.Fatal(err)
+		if err := repo.CastVote(ctx, sessionID, userID, movieID, "yes", nil); err != nil {
+			t.Fatalf("First CastVote failed: %v", err)
+		}
+		if err := repo.CastVote(ctx, sessionID, userID, movieID, "no", nil); err != nil {
+			t.Fatalf("Second CastVote failed: %v", err)
 		}
 
-		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "maybe")
-		if err != nil {
-			t.Fatal(err)
+		if count := countVotes(t, movieID); count != 1 {
+			t.Errorf("Expected 1 vote, got %d", count)
 		}
+	})
 
-		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+	t.Run("history records the season", func(t *testing.T) {
+		events, err := repo.GetVoteHistory(ctx, sessionID, userID)
 		if err != nil {
-			t.Fatalf("CheckMatch failed: %v", err)
+			t.Fatalf("GetVoteHistory failed: %v", err)
+		}
+		if len(events) == 0 {
+			t.Fatal("Expected vote events")
 		}
 
-		if isMatch {
-			t.Error("Expected no match when only one yes and one maybe vote")
+		first := events[0]
+		if first.MediaID != showID || first.SeasonNumber == nil || *first.SeasonNumber != seasonOne {
+			t.Errorf("Expected the first event to be for season %d of the show, got %+v", seasonOne, first)
 		}
 	})
 }
 
-func TestVoteRepository_GetMatchesForSession(t *testing.T) {
+func TestVoteRepository_CastVotes(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
 	defer testDB.Cleanup(t)
@@ -338,78 +423,106 @@ func TestVoteRepository_GetMatchesForSession(t *testing.T) {
 	repo := NewVoteRepository(testDB.DB)
 	ctx := context.Background()
 
-	// Setup: Create users and session
-	user1ID := uuid.New()
-	testDB.SeedProfile(t, user1ID, "user1")
+	// Setup: Create user, session, and media
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "swiper")
 
-	user2ID := uuid.New()
-	testDB.SeedProfile(t, user2ID, "user2")
+	sessionID := testDB.SeedWatchSession(t, userID, "Swipe Session", false)
 
-	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	media1ID := testDB.SeedMediaItem(t, 6001, "movie", "Batch Movie 1")
+	media2ID := testDB.SeedMediaItem(t, 6002, "movie", "Batch Movie 2")
+	media3ID := testDB.SeedMediaItem(t, 6003, "movie", "Batch Movie 3")
 
-	t.Run("returns empty list when no matches", func(t *testing.T) {
-		matches, err := repo.GetMatchesForSession(ctx, sessionID)
+	countVotes := func(t *testing.T) int {
+		t.Helper()
+		var count int
+		err := testDB.DB.QueryRow(
+			"SELECT COUNT(*) FROM session_votes WHERE session_id = $1 AND user_id = $2",
+			sessionID, userID,
+		).Scan(&count)
 		if err != nil {
-			t.Fatalf("GetMatchesForSession failed: %v", err)
-		}
-
-		if len(matches) != 0 {
-			t.Errorf("Expected 0 matches, got %d", len(matches))
+			t.Fatalf("Failed to count votes: %v", err)
 		}
-	})
-
-	t.Run("returns matched media items", func(t *testing.T) {
-		// Create media and votes for matches
-		media1ID := testDB.SeedMediaItem(t, 1001, "movie", "Matched Movie 1")
-		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
-		testDB.SeedVote(t, sessionID, user2ID, media1ID, "yes")
-
-		media2ID := testDB.SeedMediaItem(t, 1002, "movie", "Matched Movie 2")
-		testDB.SeedVote(t, sessionID, user1ID, media2ID, "yes")
-		testDB.SeedVote(t, sessionID, user2ID, media2ID, "yes")
-
-		// Create media without match (only one yes)
-		media3ID := testDB.SeedMediaItem(t, 1003, "movie", "Not Matched Movie")
-		testDB.SeedVote(t, sessionID, user1ID, media3ID, "yes")
+		return count
+	}
 
-		matches, err := repo.GetMatchesForSession(ctx, sessionID)
+	voteFor := func(t *testing.T, mediaID uuid.UUID) string {
+		t.Helper()
+		var vote string
+		err := testDB.DB.QueryRow(
+			"SELECT vote FROM session_votes WHERE session_id = $1 AND user_id = $2 AND media_id = $3",
+			sessionID, userID, mediaID,
+		).Scan(&vote)
 		if err != nil {
-			t.Fatalf("GetMatchesForSession failed: %v", err)
+			t.Fatalf("Failed to get vote: %v", err)
+		}
+		return vote
+	}
+
+	t.Run("rejects the whole batch on an invalid vote", func(t *testing.T) {
+		err := repo.CastVotes(ctx, sessionID, userID, []VoteInput{
+			{MediaID: media1ID, Vote: "yes"},
+			{MediaID: media2ID, Vote: "love"},
+		})
+		if !errors.Is(err, ErrInvalidVote) {
+			t.Fatalf("Expected ErrInvalidVote, got %v", err)
 		}
 
-		if len(matches) != 2 {
-			t.Fatalf("Expected 2 matches, got %d", len(matches))
+		if got := countVotes(t); got != 0 {
+			t.Errorf("Expected no votes written, got %d", got)
 		}
+	})
 
-		// Verify matches are ordered by title
-		if matches[0].Title != "Matched Movie 1" {
-			t.Errorf("Expected first match 'Matched Movie 1', got '%s'", matches[0].Title)
+	t.Run("rolls back earlier votes when a later insert fails", func(t *testing.T) {
+		err := repo.CastVotes(ctx, sessionID, userID, []VoteInput{
+			{MediaID: media1ID, Vote: "yes"},
+			{MediaID: uuid.New(), Vote: "no"}, // Media does not exist
+		})
+		if err == nil {
+			t.Fatal("Expected error for unknown media, got nil")
 		}
 
-		if matches[1].Title != "Matched Movie 2" {
-			t.Errorf("Expected second match 'Matched Movie 2', got '%s'", matches[1].Title)
+		if got := countVotes(t); got != 0 {
+			t.Errorf("Expected the transaction to roll back, got %d votes", got)
 		}
 	})
 
-	t.Run("excludes media with only no votes", func(t *testing.T) {
-		session2ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	t.Run("casts every vote in the batch", func(t *testing.T) {
+		err := repo.CastVotes(ctx, sessionID, userID, []VoteInput{
+			{MediaID: media1ID, Vote: "yes"},
+			{MediaID: media2ID, Vote: "no"},
+			{MediaID: media3ID, Vote: "maybe"},
+		})
+		if err != nil {
+			t.Fatalf("CastVotes failed: %v", err)
+		}
 
-		media4ID := testDB.SeedMediaItem(t, 2001, "movie", "No Votes Movie")
-		testDB.SeedVote(t, session2ID, user1ID, media4ID, "no")
-		testDB.SeedVote(t, session2ID, user2ID, media4ID, "no")
+		if got := countVotes(t); got != 3 {
+			t.Errorf("Expected 3 votes, got %d", got)
+		}
+		if got := voteFor(t, media2ID); got != "no" {
+			t.Errorf("Expected vote 'no' for media 2, got '%s'", got)
+		}
+	})
 
-		matches, err := repo.GetMatchesForSession(ctx, session2ID)
+	t.Run("updates existing votes", func(t *testing.T) {
+		err := repo.CastVotes(ctx, sessionID, userID, []VoteInput{
+			{MediaID: media2ID, Vote: "yes"},
+		})
 		if err != nil {
-			t.Fatalf("GetMatchesForSession failed: %v", err)
+			t.Fatalf("CastVotes failed: %v", err)
 		}
 
-		if len(matches) != 0 {
-			t.Errorf("Expected 0 matches for no votes, got %d", len(matches))
+		if got := countVotes(t); got != 3 {
+			t.Errorf("Expected 3 votes, got %d", got)
+		}
+		if got := voteFor(t, media2ID); got != "yes" {
+			t.Errorf("Expected vote 'yes' for media 2, got '%s'", got)
 		}
 	})
 }
 
-func TestVoteRepository_GetLikedMovies(t *testing.T) {
+func TestVoteRepository_GetVoteHistory(t *testing.T) {
 	testDB := testutils.NewTestDB(t)
 	defer testDB.Close()
 	defer testDB.Cleanup(t)
@@ -417,91 +530,1956 @@ func TestVoteRepository_GetLikedMovies(t *testing.T) {
 	repo := NewVoteRepository(testDB.DB)
 	ctx := context.Background()
 
-	// Setup: Create users and session
-	user1ID := uuid.New()
-	testDB.SeedProfile(t, user1ID, "user1")
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "flipper")
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "steady")
 
-	user2ID := uuid.New()
-	testDB.SeedProfile(t, user2ID, "user2")
+	sessionID := testDB.SeedWatchSession(t, userID, "History Session", false)
+	mediaID := testDB.SeedMediaItem(t, 321, "movie", "Flip Movie")
 
-	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	for _, vote := range []string{"yes", "no", "yes"} {
+		if err := repo.CastVote(ctx, sessionID, userID, mediaID, vote, nil); err != nil {
+			t.Fatalf("CastVote(%s) failed: %v", vote, err)
+		}
+	}
+	if err := repo.CastVote(ctx, sessionID, otherID, mediaID, "maybe", nil); err != nil {
+		t.Fatalf("CastVote failed: %v", err)
+	}
 
-	t.Run("returns empty list when no likes", func(t *testing.T) {
-		titles, err := repo.GetLikedMovies(ctx, sessionID)
+	t.Run("records every vote change in order", func(t *testing.T) {
+		history, err := repo.GetVoteHistory(ctx, sessionID, userID)
 		if err != nil {
-			t.Fatalf("GetLikedMovies failed: %v", err)
+			t.Fatalf("GetVoteHistory failed: %v", err)
 		}
 
-		if len(titles) != 0 {
-			t.Errorf("Expected 0 titles, got %d", len(titles))
+		if len(history) != 3 {
+			t.Fatalf("Expected 3 history rows, got %d", len(history))
+		}
+		for i, expected := range []string{"yes", "no", "yes"} {
+			if history[i].Vote != expected {
+				t.Errorf("Expected event %d to be '%s', got '%s'", i, expected, history[i].Vote)
+			}
+			if history[i].MediaID != mediaID {
+				t.Errorf("Expected event %d for media %s, got %s", i, mediaID, history[i].MediaID)
+			}
 		}
 	})
 
-	t.Run("returns titles of liked movies", func(t *testing.T) {
-		// Create media and yes votes
-		media1ID := testDB.SeedMediaItem(t, 3001, "movie", "Zulu Movie") // Starts with Z to test ordering
-		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
+	t.Run("session_votes keeps only the latest vote", func(t *testing.T) {
+		var count int
+		var vote string
+		err := testDB.DB.QueryRow(
+			"SELECT COUNT(*), MAX(vote::text) FROM session_votes WHERE session_id = $1 AND user_id = $2",
+			sessionID, userID,
+		).Scan(&count, &vote)
+		if err != nil {
+			t.Fatalf("Failed to read session votes: %v", err)
+		}
 
-		media2ID := testDB.SeedMediaItem(t, 3002, "movie", "Alpha Movie") // Starts with A
-		testDB.SeedVote(t, sessionID, user2ID, media2ID, "yes")
+		if count != 1 || vote != "yes" {
+			t.Errorf("Expected a single 'yes' vote, got %d rows with '%s'", count, vote)
+		}
+	})
 
-		// Create media with no vote
-		media3ID := testDB.SeedMediaItem(t, 3003, "movie", "Disliked Movie")
-		testDB.SeedVote(t, sessionID, user1ID, media3ID, "no")
+	t.Run("batch votes are recorded too", func(t *testing.T) {
+		if err := repo.CastVotes(ctx, sessionID, otherID, []VoteInput{{MediaID: mediaID, Vote: "no"}}); err != nil {
+			t.Fatalf("CastVotes failed: %v", err)
+		}
 
-		titles, err := repo.GetLikedMovies(ctx, sessionID)
+		history, err := repo.GetVoteHistory(ctx, sessionID, otherID)
 		if err != nil {
-			t.Fatalf("GetLikedMovies failed: %v", err)
+			t.Fatalf("GetVoteHistory failed: %v", err)
 		}
-
-		if len(titles) != 2 {
-			t.Fatalf("Expected 2 titles, got %d", len(titles))
+		if len(history) != 2 || history[0].Vote != "maybe" || history[1].Vote != "no" {
+			t.Errorf("Expected maybe then no, got %+v", history)
 		}
+	})
 
-		// Verify alphabetical ordering
-		if titles[0] != "Alpha Movie" {
-			t.Errorf("Expected first title 'Alpha Movie', got '%s'", titles[0])
+	t.Run("returns no history for a user who never voted", func(t *testing.T) {
+		history, err := repo.GetVoteHistory(ctx, sessionID, uuid.New())
+		if err != nil {
+			t.Fatalf("GetVoteHistory failed: %v", err)
 		}
-
-		if titles[1] != "Zulu Movie" {
-			t.Errorf("Expected second title 'Zulu Movie', got '%s'", titles[1])
+		if len(history) != 0 {
+			t.Errorf("Expected no history, got %d events", len(history))
 		}
 	})
+}
 
-	t.Run("returns unique titles even with multiple yes votes", func(t *testing.T) {
-		session2ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+func TestVoteRepository_CloneCandidates(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
 
-		media4ID := testDB.SeedMediaItem(t, 4001, "movie", "Popular Movie")
-		testDB.SeedVote(t, session2ID, user1ID, media4ID, "yes")
-		testDB.SeedVote(t, session2ID, user2ID, media4ID, "yes")
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
 
-		titles, err := repo.GetLikedMovies(ctx, session2ID)
-		if err != nil {
-			t.Fatalf("GetLikedMovies failed: %v", err)
-		}
+	// Setup: A prior session with votes and one queued, unvoted candidate
+	hostID := uuid.New()
+	testDB.SeedProfile(t, hostID, "host")
 
-		if len(titles) != 1 {
-			t.Errorf("Expected 1 unique title, got %d", len(titles))
-		}
+	guestID := uuid.New()
+	testDB.SeedProfile(t, guestID, "guest")
 
-		if titles[0] != "Popular Movie" {
-			t.Errorf("Expected 'Popular Movie', got '%s'", titles[0])
-		}
-	})
+	sourceID := testDB.SeedWatchSession(t, hostID, "Last Friday", false)
+	targetID := testDB.SeedWatchSession(t, hostID, "This Friday", false)
 
-	t.Run("excludes maybe votes", func(t *testing.T) {
-		session3ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	media1ID := testDB.SeedMediaItem(t, 7001, "movie", "Deck Movie 1")
+	media2ID := testDB.SeedMediaItem(t, 7002, "movie", "Deck Movie 2")
+	media3ID := testDB.SeedMediaItem(t, 7003, "movie", "Deck Movie 3")
 
-		media5ID := testDB.SeedMediaItem(t, 5001, "movie", "Maybe Movie")
-		testDB.SeedVote(t, session3ID, user1ID, media5ID, "maybe")
+	testDB.SeedVote(t, sourceID, hostID, media1ID, "yes")
+	testDB.SeedVote(t, sourceID, guestID, media1ID, "yes")
+	testDB.SeedVote(t, sourceID, guestID, media2ID, "no")
 
-		titles, err := repo.GetLikedMovies(ctx, session3ID)
+	_, err := testDB.DB.Exec(
+		"INSERT INTO session_candidates (session_id, media_id) VALUES ($1, $2)",
+		sourceID, media3ID,
+	)
+	if err != nil {
+		t.Fatalf("Failed to seed candidate: %v", err)
+	}
+
+	t.Run("copies the candidate set", func(t *testing.T) {
+		cloned, err := repo.CloneCandidates(ctx, sourceID, targetID)
 		if err != nil {
-			t.Fatalf("GetLikedMovies failed: %v", err)
+			t.Fatalf("CloneCandidates failed: %v", err)
 		}
 
-		if len(titles) != 0 {
-			t.Errorf("Expected 0 titles for maybe votes, got %d", len(titles))
+		if cloned != 3 {
+			t.Errorf("Expected 3 candidates cloned, got %d", cloned)
+		}
+
+		rows, err := testDB.DB.Query("SELECT media_id FROM session_candidates WHERE session_id = $1", targetID)
+		if err != nil {
+			t.Fatalf("Failed to query candidates: %v", err)
+		}
+		defer rows.Close()
+
+		candidates := map[uuid.UUID]bool{}
+		for rows.Next() {
+			var mediaID uuid.UUID
+			if err := rows.Scan(&mediaID); err != nil {
+				t.Fatalf("Failed to scan candidate: %v", err)
+			}
+			candidates[mediaID] = true
+		}
+
+		for _, mediaID := range []uuid.UUID{media1ID, media2ID, media3ID} {
+			if !candidates[mediaID] {
+				t.Errorf("Expected media %s to be a candidate in the new session", mediaID)
+			}
+		}
+	})
+
+	t.Run("does not copy votes", func(t *testing.T) {
+		var count int
+		err := testDB.DB.QueryRow("SELECT COUNT(*) FROM session_votes WHERE session_id = $1", targetID).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to count votes: %v", err)
+		}
+
+		if count != 0 {
+			t.Errorf("Expected no votes in the new session, got %d", count)
+		}
+	})
+
+	t.Run("cloning twice adds nothing new", func(t *testing.T) {
+		cloned, err := repo.CloneCandidates(ctx, sourceID, targetID)
+		if err != nil {
+			t.Fatalf("CloneCandidates failed: %v", err)
+		}
+
+		if cloned != 0 {
+			t.Errorf("Expected 0 candidates cloned, got %d", cloned)
+		}
+	})
+}
+
+func TestVoteRepository_CheckMatch(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users, session, and media
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+	t.Run("returns false when no votes", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 100, "movie", "No Votes Movie")
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if isMatch {
+			t.Error("Expected no match with no votes")
+		}
+	})
+
+	t.Run("returns false with only one yes vote", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 200, "movie", "One Vote Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatalf("CastVote failed: %v", err)
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if isMatch {
+			t.Error("Expected no match with only one yes vote")
+		}
+	})
+
+	t.Run("returns true with two yes votes", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 300, "movie", "Match Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatalf("User1 CastVote failed: %v", err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatalf("User2 CastVote failed: %v", err)
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if !isMatch {
+			t.Error("Expected match with two yes votes")
+		}
+	})
+
+	t.Run("returns true with three yes votes", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 400, "movie", "Three Votes Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user3ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if !isMatch {
+			t.Error("Expected match with three yes votes")
+		}
+	})
+
+	t.Run("returns false when no votes count as no match", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 500, "movie", "No Votes Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "no", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "no", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if isMatch {
+			t.Error("Expected no match with only no votes")
+		}
+	})
+
+	t.Run("ignores maybe votes in match calculation", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 600, "movie", "Maybe Movie")
+
+		err := repo.CastVote(ctx, sessionID, user1ID, mediaID, "yes", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.CastVote(ctx, sessionID, user2ID, mediaID, "maybe", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+
+		if isMatch {
+			t.Error("Expected no match when only one yes and one maybe vote")
+		}
+	})
+}
+
+func TestVoteRepository_CastVoteAndCheckMatch(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users, session, and media
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "combined_user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "combined_user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "combined_user3")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Combined Session", false)
+	mediaID := testDB.SeedMediaItem(t, 250, "movie", "Combined Movie")
+
+	castVote := func(userID uuid.UUID, vote string) bool {
+		t.Helper()
+		isMatch, err := repo.CastVoteAndCheckMatch(ctx, sessionID, userID, mediaID, vote, nil)
+		if err != nil {
+			t.Fatalf("CastVoteAndCheckMatch failed: %v", err)
+		}
+		return isMatch
+	}
+
+	t.Run("first yes vote is not a match", func(t *testing.T) {
+		if castVote(user1ID, "yes") {
+			t.Error("Expected no match with one yes vote")
+		}
+	})
+
+	t.Run("repeating a yes vote does not count twice", func(t *testing.T) {
+		if castVote(user1ID, "yes") {
+			t.Error("Expected no match when the same user votes yes again")
+		}
+	})
+
+	t.Run("a no vote is never a match", func(t *testing.T) {
+		if castVote(user3ID, "no") {
+			t.Error("Expected no match for a no vote")
+		}
+	})
+
+	t.Run("the threshold-crossing yes vote is a match", func(t *testing.T) {
+		if !castVote(user2ID, "yes") {
+			t.Error("Expected a match on the second yes vote")
+		}
+
+		isMatch, err := repo.CheckMatch(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatch failed: %v", err)
+		}
+		if !isMatch {
+			t.Error("Expected CheckMatch to agree with the combined call")
+		}
+	})
+
+	t.Run("records a vote event for every call", func(t *testing.T) {
+		var events int
+		err := testDB.DB.QueryRow(`SELECT COUNT(*) FROM vote_events WHERE session_id = $1 AND media_id = $2`, sessionID, mediaID).Scan(&events)
+		if err != nil {
+			t.Fatalf("Failed to count vote events: %v", err)
+		}
+		if events != 4 {
+			t.Errorf("Expected 4 vote events, got %d", events)
+		}
+	})
+}
+
+func TestVoteRepository_CastVoteAndCheckMatch_Concurrent(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	const voters = 5
+
+	userIDs := make([]uuid.UUID, voters)
+	for i := range userIDs {
+		userIDs[i] = uuid.New()
+		testDB.SeedProfile(t, userIDs[i], fmt.Sprintf("racer%d", i))
+	}
+
+	sessionID := testDB.SeedWatchSession(t, userIDs[0], "Race Session", false)
+	mediaID := testDB.SeedMediaItem(t, 251, "movie", "Race Movie")
+
+	t.Run("exactly one concurrent yes vote reports the match", func(t *testing.T) {
+		results := make([]bool, voters)
+		errs := make([]error, voters)
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i, userID := range userIDs {
+			wg.Add(1)
+			go func(i int, userID uuid.UUID) {
+				defer wg.Done()
+				<-start
+				results[i], errs[i] = repo.CastVoteAndCheckMatch(ctx, sessionID, userID, mediaID, "yes", nil)
+			}(i, userID)
+		}
+		close(start)
+		wg.Wait()
+
+		matches := 0
+		for i := range results {
+			if errs[i] != nil {
+				t.Fatalf("CastVoteAndCheckMatch failed: %v", errs[i])
+			}
+			if results[i] {
+				matches++
+			}
+		}
+
+		if matches != 1 {
+			t.Errorf("Expected exactly 1 vote to report the match, got %d", matches)
+		}
+	})
+
+	t.Run("a repeated yes vote does not report the match again", func(t *testing.T) {
+		isMatch, err := repo.CastVoteAndCheckMatch(ctx, sessionID, userIDs[0], mediaID, "yes", nil)
+		if err != nil {
+			t.Fatalf("CastVoteAndCheckMatch failed: %v", err)
+		}
+		if isMatch {
+			t.Error("Expected an already claimed match not to be reported")
+		}
+	})
+
+	t.Run("exactly one concurrent batch claims the match", func(t *testing.T) {
+		batchMediaID := testDB.SeedMediaItem(t, 252, "movie", "Batch Race Movie")
+
+		results := make([]bool, voters)
+		errs := make([]error, voters)
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i, userID := range userIDs {
+			wg.Add(1)
+			go func(i int, userID uuid.UUID) {
+				defer wg.Done()
+				<-start
+				// Mirrors the batch handler: the claim runs once the batch has committed
+				if errs[i] = repo.CastVotes(ctx, sessionID, userID, []VoteInput{{MediaID: batchMediaID, Vote: "yes"}}); errs[i] != nil {
+					return
+				}
+				results[i], errs[i] = repo.ClaimMatch(ctx, sessionID, batchMediaID)
+			}(i, userID)
+		}
+		close(start)
+		wg.Wait()
+
+		matches := 0
+		for i := range results {
+			if errs[i] != nil {
+				t.Fatalf("CastVotes or ClaimMatch failed: %v", errs[i])
+			}
+			if results[i] {
+				matches++
+			}
+		}
+
+		if matches != 1 {
+			t.Errorf("Expected exactly 1 batch to claim the match, got %d", matches)
+		}
+	})
+}
+
+func TestVoteRepository_CheckMatchDetail(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users, session, and media
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+	mediaID := testDB.SeedMediaItem(t, 8001, "movie", "Detail Movie")
+
+	t.Run("returns no media below the threshold", func(t *testing.T) {
+		testDB.SeedVote(t, sessionID, user1ID, mediaID, "yes")
+
+		media, isMatch, err := repo.CheckMatchDetail(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatchDetail failed: %v", err)
+		}
+
+		if isMatch || media != nil {
+			t.Errorf("Expected no match, got isMatch=%v media=%v", isMatch, media)
+		}
+	})
+
+	t.Run("returns the media when the second yes vote creates the match", func(t *testing.T) {
+		testDB.SeedVote(t, sessionID, user2ID, mediaID, "yes")
+
+		media, isMatch, err := repo.CheckMatchDetail(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatchDetail failed: %v", err)
+		}
+
+		if !isMatch {
+			t.Fatal("Expected a match")
+		}
+		if media == nil {
+			t.Fatal("Expected the matched media item")
+		}
+		if media.ID != mediaID || media.Title != "Detail Movie" || media.TMDBID != 8001 {
+			t.Errorf("Expected Detail Movie (8001), got %s (%d)", media.Title, media.TMDBID)
+		}
+	})
+
+	t.Run("reports an existing match without media on later yes votes", func(t *testing.T) {
+		testDB.SeedVote(t, sessionID, user3ID, mediaID, "yes")
+
+		media, isMatch, err := repo.CheckMatchDetail(ctx, sessionID, mediaID)
+		if err != nil {
+			t.Fatalf("CheckMatchDetail failed: %v", err)
+		}
+
+		if !isMatch {
+			t.Error("Expected the media to still be a match")
+		}
+		if media != nil {
+			t.Error("Expected no media for a match created earlier")
+		}
+	})
+}
+
+func TestVoteRepository_GetMatchesForSession(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and session
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+	t.Run("returns empty list when no matches", func(t *testing.T) {
+		matches, err := repo.GetMatchesForSession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 0 {
+			t.Errorf("Expected 0 matches, got %d", len(matches))
+		}
+	})
+
+	t.Run("returns matched media items", func(t *testing.T) {
+		// Create media and votes for matches
+		media1ID := testDB.SeedMediaItem(t, 1001, "movie", "Matched Movie 1")
+		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, media1ID, "yes")
+
+		media2ID := testDB.SeedMediaItem(t, 1002, "movie", "Matched Movie 2")
+		testDB.SeedVote(t, sessionID, user1ID, media2ID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, media2ID, "yes")
+
+		// Create media without match (only one yes)
+		media3ID := testDB.SeedMediaItem(t, 1003, "movie", "Not Matched Movie")
+		testDB.SeedVote(t, sessionID, user1ID, media3ID, "yes")
+
+		matches, err := repo.GetMatchesForSession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 2 {
+			t.Fatalf("Expected 2 matches, got %d", len(matches))
+		}
+
+		// Verify matches are ordered by title
+		if matches[0].Title != "Matched Movie 1" {
+			t.Errorf("Expected first match 'Matched Movie 1', got '%s'", matches[0].Title)
+		}
+
+		if matches[1].Title != "Matched Movie 2" {
+			t.Errorf("Expected second match 'Matched Movie 2', got '%s'", matches[1].Title)
+		}
+	})
+
+	t.Run("excludes media with only no votes", func(t *testing.T) {
+		session2ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+		media4ID := testDB.SeedMediaItem(t, 2001, "movie", "No Votes Movie")
+		testDB.SeedVote(t, session2ID, user1ID, media4ID, "no")
+		testDB.SeedVote(t, session2ID, user2ID, media4ID, "no")
+
+		matches, err := repo.GetMatchesForSession(ctx, session2ID)
+		if err != nil {
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 0 {
+			t.Errorf("Expected 0 matches for no votes, got %d", len(matches))
+		}
+	})
+
+	t.Run("orders by yes count then title", func(t *testing.T) {
+		user3ID := uuid.New()
+		testDB.SeedProfile(t, user3ID, "user3")
+
+		session3ID := testDB.SeedWatchSession(t, user1ID, "Ranked Session", false)
+
+		// Two votes each; "Alpha" should break the tie ahead of "Bravo"
+		bravoID := testDB.SeedMediaItem(t, 3001, "movie", "Bravo")
+		testDB.SeedVote(t, session3ID, user1ID, bravoID, "yes")
+		testDB.SeedVote(t, session3ID, user2ID, bravoID, "yes")
+
+		alphaID := testDB.SeedMediaItem(t, 3002, "movie", "Alpha")
+		testDB.SeedVote(t, session3ID, user1ID, alphaID, "yes")
+		testDB.SeedVote(t, session3ID, user3ID, alphaID, "yes")
+
+		// Three votes; should rank first despite its title
+		zuluID := testDB.SeedMediaItem(t, 3003, "movie", "Zulu")
+		testDB.SeedVote(t, session3ID, user1ID, zuluID, "yes")
+		testDB.SeedVote(t, session3ID, user2ID, zuluID, "yes")
+		testDB.SeedVote(t, session3ID, user3ID, zuluID, "yes")
+
+		matches, err := repo.GetMatchesForSession(ctx, session3ID)
+		if err != nil {
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 3 {
+			t.Fatalf("Expected 3 matches, got %d", len(matches))
+		}
+
+		expected := []struct {
+			title    string
+			yesCount int
+		}{
+			{"Zulu", 3},
+			{"Alpha", 2},
+			{"Bravo", 2},
+		}
+		for i, want := range expected {
+			if matches[i].Title != want.title {
+				t.Errorf("Expected match %d to be '%s', got '%s'", i, want.title, matches[i].Title)
+			}
+			if matches[i].YesCount != want.yesCount {
+				t.Errorf("Expected match %d to have %d yes votes, got %d", i, want.yesCount, matches[i].YesCount)
+			}
+		}
+	})
+}
+
+func TestVoteRepository_GetMatchVoters(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	aliceID := uuid.New()
+	testDB.SeedProfile(t, aliceID, "alice")
+
+	bobID := uuid.New()
+	testDB.SeedProfile(t, bobID, "bob")
+
+	carolID := uuid.New()
+	testDB.SeedProfile(t, carolID, "carol")
+
+	sessionID := testDB.SeedWatchSession(t, aliceID, "Voters Session", false)
+	matchedID := testDB.SeedMediaItem(t, 9101, "movie", "Matched Movie")
+	unmatchedID := testDB.SeedMediaItem(t, 9102, "movie", "Unmatched Movie")
+
+	testDB.SeedVote(t, sessionID, aliceID, matchedID, "yes")
+	testDB.SeedVote(t, sessionID, bobID, matchedID, "yes")
+	testDB.SeedVote(t, sessionID, carolID, matchedID, "no")
+	testDB.SeedVote(t, sessionID, aliceID, unmatchedID, "yes")
+
+	t.Run("returns only the yes voters of a match", func(t *testing.T) {
+		voters, err := repo.GetMatchVoters(ctx, sessionID, matchedID)
+		if err != nil {
+			t.Fatalf("GetMatchVoters failed: %v", err)
+		}
+
+		if len(voters) != 2 {
+			t.Fatalf("Expected 2 voters, got %d", len(voters))
+		}
+
+		found := map[uuid.UUID]bool{}
+		for _, voter := range voters {
+			found[voter.UserID] = true
+		}
+		if !found[aliceID] || !found[bobID] {
+			t.Errorf("Expected alice and bob, got %+v", voters)
+		}
+		if found[carolID] {
+			t.Error("Expected the no voter to be excluded")
+		}
+	})
+
+	t.Run("returns nil for media that is not a match", func(t *testing.T) {
+		voters, err := repo.GetMatchVoters(ctx, sessionID, unmatchedID)
+		if err != nil {
+			t.Fatalf("GetMatchVoters failed: %v", err)
+		}
+		if voters != nil {
+			t.Errorf("Expected nil voters, got %d", len(voters))
+		}
+	})
+}
+
+func TestVoteRepository_CheckMatches(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	aliceID := uuid.New()
+	testDB.SeedProfile(t, aliceID, "alice")
+
+	bobID := uuid.New()
+	testDB.SeedProfile(t, bobID, "bob")
+
+	sessionID := testDB.SeedWatchSession(t, aliceID, "Bulk Check Session", false)
+	matchedID := testDB.SeedMediaItem(t, 9201, "movie", "Matched Movie")
+	alsoMatchedID := testDB.SeedMediaItem(t, 9202, "movie", "Also Matched Movie")
+	oneYesID := testDB.SeedMediaItem(t, 9203, "movie", "One Yes Movie")
+	splitID := testDB.SeedMediaItem(t, 9204, "movie", "Split Movie")
+	unvotedID := testDB.SeedMediaItem(t, 9205, "movie", "Unvoted Movie")
+
+	testDB.SeedVote(t, sessionID, aliceID, matchedID, "yes")
+	testDB.SeedVote(t, sessionID, bobID, matchedID, "yes")
+	testDB.SeedVote(t, sessionID, aliceID, alsoMatchedID, "yes")
+	testDB.SeedVote(t, sessionID, bobID, alsoMatchedID, "yes")
+	testDB.SeedVote(t, sessionID, aliceID, oneYesID, "yes")
+	testDB.SeedVote(t, sessionID, aliceID, splitID, "yes")
+	testDB.SeedVote(t, sessionID, bobID, splitID, "no")
+
+	t.Run("maps every requested ID to its match state", func(t *testing.T) {
+		matches, err := repo.CheckMatches(ctx, sessionID, []uuid.UUID{matchedID, alsoMatchedID, oneYesID, splitID, unvotedID})
+		if err != nil {
+			t.Fatalf("CheckMatches failed: %v", err)
+		}
+
+		expected := map[uuid.UUID]bool{
+			matchedID:     true,
+			alsoMatchedID: true,
+			oneYesID:      false,
+			splitID:       false,
+			unvotedID:     false,
+		}
+		if len(matches) != len(expected) {
+			t.Fatalf("Expected %d entries, got %d", len(expected), len(matches))
+		}
+		for mediaID, want := range expected {
+			got, ok := matches[mediaID]
+			if !ok {
+				t.Errorf("Expected an entry for media %s", mediaID)
+			} else if got != want {
+				t.Errorf("Expected match=%v for media %s, got %v", want, mediaID, got)
+			}
+		}
+	})
+
+	t.Run("agrees with CheckMatch", func(t *testing.T) {
+		matches, err := repo.CheckMatches(ctx, sessionID, []uuid.UUID{matchedID, oneYesID})
+		if err != nil {
+			t.Fatalf("CheckMatches failed: %v", err)
+		}
+
+		for mediaID, got := range matches {
+			want, err := repo.CheckMatch(ctx, sessionID, mediaID)
+			if err != nil {
+				t.Fatalf("CheckMatch failed: %v", err)
+			}
+			if got != want {
+				t.Errorf("Expected CheckMatches to agree with CheckMatch for media %s", mediaID)
+			}
+		}
+	})
+
+	t.Run("scopes votes to the session", func(t *testing.T) {
+		otherSessionID := testDB.SeedWatchSession(t, aliceID, "Other Session", false)
+
+		matches, err := repo.CheckMatches(ctx, otherSessionID, []uuid.UUID{matchedID})
+		if err != nil {
+			t.Fatalf("CheckMatches failed: %v", err)
+		}
+		if matches[matchedID] {
+			t.Error("Expected no match in a session without votes")
+		}
+	})
+
+	t.Run("returns an empty map for no IDs", func(t *testing.T) {
+		matches, err := repo.CheckMatches(ctx, sessionID, nil)
+		if err != nil {
+			t.Fatalf("CheckMatches failed: %v", err)
+		}
+		if len(matches) != 0 {
+			t.Errorf("Expected an empty map, got %v", matches)
+		}
+	})
+}
+
+func TestVoteRepository_GetSoftMatchesForSession(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Soft Session", false)
+
+	// yes + maybe: a soft match but not a strict one
+	softID := testDB.SeedMediaItem(t, 4001, "movie", "Soft Match")
+	testDB.SeedVote(t, sessionID, user1ID, softID, "yes")
+	testDB.SeedVote(t, sessionID, user2ID, softID, "maybe")
+
+	// yes + yes + no: a strict match, but the no vetoes it softly
+	vetoedID := testDB.SeedMediaItem(t, 4002, "movie", "Vetoed")
+	testDB.SeedVote(t, sessionID, user1ID, vetoedID, "yes")
+	testDB.SeedVote(t, sessionID, user2ID, vetoedID, "yes")
+	testDB.SeedVote(t, sessionID, user3ID, vetoedID, "no")
+
+	// a lone maybe is not enough
+	loneID := testDB.SeedMediaItem(t, 4003, "movie", "Lone Maybe")
+	testDB.SeedVote(t, sessionID, user1ID, loneID, "maybe")
+
+	t.Run("includes yes and maybe combinations", func(t *testing.T) {
+		matches, err := repo.GetSoftMatchesForSession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSoftMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 1 {
+			t.Fatalf("Expected 1 soft match, got %d", len(matches))
+		}
+		if matches[0].ID != softID {
+			t.Errorf("Expected 'Soft Match', got '%s'", matches[0].Title)
+		}
+		if matches[0].YesCount != 1 {
+			t.Errorf("Expected 1 yes vote, got %d", matches[0].YesCount)
+		}
+	})
+
+	t.Run("excludes media with any no vote", func(t *testing.T) {
+		matches, err := repo.GetSoftMatchesForSession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetSoftMatchesForSession failed: %v", err)
+		}
+
+		for _, match := range matches {
+			if match.ID == vetoedID {
+				t.Error("Expected media with a no vote to be excluded")
+			}
+		}
+	})
+
+	t.Run("leaves strict matches unchanged", func(t *testing.T) {
+		matches, err := repo.GetMatchesForSession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetMatchesForSession failed: %v", err)
+		}
+
+		if len(matches) != 1 || matches[0].ID != vetoedID {
+			t.Errorf("Expected only 'Vetoed' as a strict match, got %+v", matches)
+		}
+	})
+}
+
+func TestVoteRepository_CountNewMatchesSince(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Badge Session", false)
+
+	// Claim a media's match and backdate it so it lands clearly before or after the recorded view
+	claimMatch := func(mediaID uuid.UUID, offset string) {
+		t.Helper()
+		if _, err := repo.ClaimMatch(ctx, sessionID, mediaID); err != nil {
+			t.Fatalf("ClaimMatch failed: %v", err)
+		}
+		_, err := testDB.DB.Exec(
+			`UPDATE session_matches SET matched_at = NOW() + $2::interval WHERE session_id = $1 AND media_id = $3`,
+			sessionID, offset, mediaID,
+		)
+		if err != nil {
+			t.Fatalf("Failed to set match time: %v", err)
+		}
+	}
+
+	earlyID := testDB.SeedMediaItem(t, 5001, "movie", "Early Match")
+	testDB.SeedVote(t, sessionID, user1ID, earlyID, "yes")
+	testDB.SeedVote(t, sessionID, user2ID, earlyID, "yes")
+	claimMatch(earlyID, "-1 hour")
+
+	t.Run("every match is new before the first view", func(t *testing.T) {
+		count, err := repo.CountNewMatchesSince(ctx, sessionID, user1ID)
+		if err != nil {
+			t.Fatalf("CountNewMatchesSince failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 new match, got %d", count)
+		}
+	})
+
+	if err := repo.MarkMatchesViewed(ctx, sessionID, user1ID); err != nil {
+		t.Fatalf("MarkMatchesViewed failed: %v", err)
+	}
+
+	lateID := testDB.SeedMediaItem(t, 5002, "movie", "Late Match")
+	testDB.SeedVote(t, sessionID, user1ID, lateID, "yes")
+	testDB.SeedVote(t, sessionID, user2ID, lateID, "yes")
+	claimMatch(lateID, "1 minute")
+
+	// A single yes after the view is not a match yet
+	pendingID := testDB.SeedMediaItem(t, 5003, "movie", "Pending")
+	testDB.SeedVote(t, sessionID, user1ID, pendingID, "yes")
+	claimMatch(pendingID, "1 minute")
+
+	t.Run("counts only matches made after the view", func(t *testing.T) {
+		count, err := repo.CountNewMatchesSince(ctx, sessionID, user1ID)
+		if err != nil {
+			t.Fatalf("CountNewMatchesSince failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 new match, got %d", count)
+		}
+	})
+
+	t.Run("re-voting an old match does not make it new", func(t *testing.T) {
+		if err := repo.CastVote(ctx, sessionID, user2ID, earlyID, "no", nil); err != nil {
+			t.Fatalf("CastVote failed: %v", err)
+		}
+		if err := repo.CastVote(ctx, sessionID, user2ID, earlyID, "yes", nil); err != nil {
+			t.Fatalf("CastVote failed: %v", err)
+		}
+		if _, err := repo.ClaimMatch(ctx, sessionID, earlyID); err != nil {
+			t.Fatalf("ClaimMatch failed: %v", err)
+		}
+
+		count, err := repo.CountNewMatchesSince(ctx, sessionID, user1ID)
+		if err != nil {
+			t.Fatalf("CountNewMatchesSince failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected the re-voted match to stay seen, got %d new matches", count)
+		}
+	})
+
+	t.Run("views are tracked per user", func(t *testing.T) {
+		count, err := repo.CountNewMatchesSince(ctx, sessionID, user2ID)
+		if err != nil {
+			t.Fatalf("CountNewMatchesSince failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 new matches for a user who never viewed, got %d", count)
+		}
+	})
+
+	t.Run("viewing again resets the count", func(t *testing.T) {
+		claimMatch(lateID, "-1 minute")
+		if err := repo.MarkMatchesViewed(ctx, sessionID, user1ID); err != nil {
+			t.Fatalf("MarkMatchesViewed failed: %v", err)
+		}
+
+		count, err := repo.CountNewMatchesSince(ctx, sessionID, user1ID)
+		if err != nil {
+			t.Fatalf("CountNewMatchesSince failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Expected no new matches, got %d", count)
+		}
+	})
+}
+
+func TestVoteRepository_GetLikedMovies(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and session
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+	t.Run("returns empty list when no likes", func(t *testing.T) {
+		titles, err := repo.GetLikedMovies(ctx, sessionID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		if len(titles) != 0 {
+			t.Errorf("Expected 0 titles, got %d", len(titles))
+		}
+	})
+
+	t.Run("returns titles of liked movies", func(t *testing.T) {
+		// Create media and yes votes
+		media1ID := testDB.SeedMediaItem(t, 3001, "movie", "Zulu Movie") // Liked first
+		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
+		setVoteTime(t, testDB, sessionID, user1ID, media1ID, "2 hours")
+
+		media2ID := testDB.SeedMediaItem(t, 3002, "movie", "Alpha Movie") // Liked most recently
+		testDB.SeedVote(t, sessionID, user2ID, media2ID, "yes")
+		setVoteTime(t, testDB, sessionID, user2ID, media2ID, "1 hour")
+
+		// Create media with no vote
+		media3ID := testDB.SeedMediaItem(t, 3003, "movie", "Disliked Movie")
+		testDB.SeedVote(t, sessionID, user1ID, media3ID, "no")
+
+		titles, err := repo.GetLikedMovies(ctx, sessionID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		if len(titles) != 2 {
+			t.Fatalf("Expected 2 titles, got %d", len(titles))
+		}
+
+		// Verify most recent like comes first
+		if titles[0] != "Alpha Movie" {
+			t.Errorf("Expected first title 'Alpha Movie', got '%s'", titles[0])
+		}
+
+		if titles[1] != "Zulu Movie" {
+			t.Errorf("Expected second title 'Zulu Movie', got '%s'", titles[1])
+		}
+	})
+
+	t.Run("returns unique titles even with multiple yes votes", func(t *testing.T) {
+		session2ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+		media4ID := testDB.SeedMediaItem(t, 4001, "movie", "Popular Movie")
+		testDB.SeedVote(t, session2ID, user1ID, media4ID, "yes")
+		testDB.SeedVote(t, session2ID, user2ID, media4ID, "yes")
+
+		titles, err := repo.GetLikedMovies(ctx, session2ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		if len(titles) != 1 {
+			t.Errorf("Expected 1 unique title, got %d", len(titles))
+		}
+
+		if titles[0] != "Popular Movie" {
+			t.Errorf("Expected 'Popular Movie', got '%s'", titles[0])
+		}
+	})
+
+	t.Run("excludes maybe votes", func(t *testing.T) {
+		session3ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+		media5ID := testDB.SeedMediaItem(t, 5001, "movie", "Maybe Movie")
+		testDB.SeedVote(t, session3ID, user1ID, media5ID, "maybe")
+
+		titles, err := repo.GetLikedMovies(ctx, session3ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		if len(titles) != 0 {
+			t.Errorf("Expected 0 titles for maybe votes, got %d", len(titles))
+		}
+	})
+
+	t.Run("orders by recency rather than title", func(t *testing.T) {
+		session4ID := testDB.SeedWatchSession(t, user1ID, "Recency Session", false)
+
+		oldID := testDB.SeedMediaItem(t, 6001, "movie", "Aardvark Movie")
+		testDB.SeedVote(t, session4ID, user1ID, oldID, "yes")
+		setVoteTime(t, testDB, session4ID, user1ID, oldID, "3 hours")
+
+		midID := testDB.SeedMediaItem(t, 6002, "movie", "Middle Movie")
+		testDB.SeedVote(t, session4ID, user1ID, midID, "yes")
+		setVoteTime(t, testDB, session4ID, user1ID, midID, "2 hours")
+
+		// Liked twice; the latest vote decides its position
+		newID := testDB.SeedMediaItem(t, 6003, "movie", "Zebra Movie")
+		testDB.SeedVote(t, session4ID, user1ID, newID, "yes")
+		setVoteTime(t, testDB, session4ID, user1ID, newID, "4 hours")
+		testDB.SeedVote(t, session4ID, user2ID, newID, "yes")
+		setVoteTime(t, testDB, session4ID, user2ID, newID, "1 hour")
+
+		titles, err := repo.GetLikedMovies(ctx, session4ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		expected := []string{"Zebra Movie", "Middle Movie", "Aardvark Movie"}
+		if len(titles) != len(expected) {
+			t.Fatalf("Expected %d titles, got %d", len(expected), len(titles))
+		}
+		for i, title := range expected {
+			if titles[i] != title {
+				t.Errorf("Expected title %d to be '%s', got '%s'", i, title, titles[i])
+			}
+		}
+	})
+
+	t.Run("applies limit and offset", func(t *testing.T) {
+		session5ID := testDB.SeedWatchSession(t, user1ID, "Paging Session", false)
+
+		for i := 0; i < 5; i++ {
+			mediaID := testDB.SeedMediaItem(t, 7001+i, "movie", fmt.Sprintf("Paged Movie %d", i))
+			testDB.SeedVote(t, session5ID, user1ID, mediaID, "yes")
+			// Movie 0 is the most recent like, movie 4 the oldest
+			setVoteTime(t, testDB, session5ID, user1ID, mediaID, fmt.Sprintf("%d hours", i+1))
+		}
+
+		firstPage, err := repo.GetLikedMovies(ctx, session5ID, 2, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+		secondPage, err := repo.GetLikedMovies(ctx, session5ID, 2, 2)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+		lastPage, err := repo.GetLikedMovies(ctx, session5ID, 2, 4)
+		if err != nil {
+			t.Fatalf("GetLikedMovies failed: %v", err)
+		}
+
+		got := append(append(append([]string{}, firstPage...), secondPage...), lastPage...)
+		if len(firstPage) != 2 || len(secondPage) != 2 || len(lastPage) != 1 {
+			t.Fatalf("Expected pages of 2, 2 and 1 titles, got %d, %d and %d", len(firstPage), len(secondPage), len(lastPage))
+		}
+		for i, title := range got {
+			expected := fmt.Sprintf("Paged Movie %d", i)
+			if title != expected {
+				t.Errorf("Expected title %d to be '%s', got '%s'", i, expected, title)
+			}
+		}
+	})
+}
+
+func TestVoteRepository_GetLikedMediaItems(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and session
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+	t.Run("returns empty list when no likes", func(t *testing.T) {
+		items, err := repo.GetLikedMediaItems(ctx, sessionID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("Expected 0 items, got %d", len(items))
+		}
+	})
+
+	t.Run("returns full items of liked movies", func(t *testing.T) {
+		media1ID := testDB.SeedMediaItem(t, 3101, "movie", "Zulu Movie") // Liked first
+		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
+		setVoteTime(t, testDB, sessionID, user1ID, media1ID, "2 hours")
+
+		media2ID := testDB.SeedMediaItem(t, 3102, "movie", "Alpha Movie") // Liked most recently
+		testDB.SeedVote(t, sessionID, user2ID, media2ID, "yes")
+		setVoteTime(t, testDB, sessionID, user2ID, media2ID, "1 hour")
+
+		media3ID := testDB.SeedMediaItem(t, 3103, "movie", "Disliked Movie")
+		testDB.SeedVote(t, sessionID, user1ID, media3ID, "no")
+
+		items, err := repo.GetLikedMediaItems(ctx, sessionID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+
+		if len(items) != 2 {
+			t.Fatalf("Expected 2 items, got %d", len(items))
+		}
+
+		// Verify most recent like comes first, with every field populated
+		first := items[0]
+		if first.ID != media2ID {
+			t.Errorf("Expected first item ID %s, got %s", media2ID, first.ID)
+		}
+		if first.TMDBID != 3102 {
+			t.Errorf("Expected first item TMDB ID 3102, got %d", first.TMDBID)
+		}
+		if first.MediaType != "movie" {
+			t.Errorf("Expected media type 'movie', got '%s'", first.MediaType)
+		}
+		if first.Title != "Alpha Movie" {
+			t.Errorf("Expected first title 'Alpha Movie', got '%s'", first.Title)
+		}
+		if first.CreatedAt.IsZero() || first.UpdatedAt.IsZero() {
+			t.Error("Expected timestamps to be populated")
+		}
+
+		if items[1].ID != media1ID || items[1].Title != "Zulu Movie" {
+			t.Errorf("Expected second item 'Zulu Movie', got '%s'", items[1].Title)
+		}
+	})
+
+	t.Run("returns unique items even with multiple yes votes", func(t *testing.T) {
+		session2ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+		media4ID := testDB.SeedMediaItem(t, 4101, "movie", "Popular Movie")
+		testDB.SeedVote(t, session2ID, user1ID, media4ID, "yes")
+		testDB.SeedVote(t, session2ID, user2ID, media4ID, "yes")
+
+		items, err := repo.GetLikedMediaItems(ctx, session2ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+
+		if len(items) != 1 {
+			t.Fatalf("Expected 1 unique item, got %d", len(items))
+		}
+
+		if items[0].ID != media4ID {
+			t.Errorf("Expected 'Popular Movie', got '%s'", items[0].Title)
+		}
+	})
+
+	t.Run("excludes maybe votes", func(t *testing.T) {
+		session3ID := testDB.SeedWatchSession(t, user1ID, "Test Session", false)
+
+		media5ID := testDB.SeedMediaItem(t, 5101, "movie", "Maybe Movie")
+		testDB.SeedVote(t, session3ID, user1ID, media5ID, "maybe")
+
+		items, err := repo.GetLikedMediaItems(ctx, session3ID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("Expected 0 items for maybe votes, got %d", len(items))
+		}
+	})
+
+	t.Run("applies limit and offset", func(t *testing.T) {
+		session4ID := testDB.SeedWatchSession(t, user1ID, "Paging Session", false)
+
+		for i := 0; i < 3; i++ {
+			mediaID := testDB.SeedMediaItem(t, 7101+i, "movie", fmt.Sprintf("Paged Item %d", i))
+			testDB.SeedVote(t, session4ID, user1ID, mediaID, "yes")
+			// Item 0 is the most recent like, item 2 the oldest
+			setVoteTime(t, testDB, session4ID, user1ID, mediaID, fmt.Sprintf("%d hours", i+1))
+		}
+
+		firstPage, err := repo.GetLikedMediaItems(ctx, session4ID, 2, 0)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+		lastPage, err := repo.GetLikedMediaItems(ctx, session4ID, 2, 2)
+		if err != nil {
+			t.Fatalf("GetLikedMediaItems failed: %v", err)
+		}
+
+		if len(firstPage) != 2 || len(lastPage) != 1 {
+			t.Fatalf("Expected pages of 2 and 1 items, got %d and %d", len(firstPage), len(lastPage))
+		}
+		if firstPage[0].Title != "Paged Item 0" || lastPage[0].Title != "Paged Item 2" {
+			t.Errorf("Unexpected page order: '%s' first, '%s' last", firstPage[0].Title, lastPage[0].Title)
+		}
+	})
+}
+
+// setVoteTime backdates a vote so recency ordering is deterministic
+func setVoteTime(t *testing.T, testDB *testutils.TestDB, sessionID, userID, mediaID uuid.UUID, ago string) {
+	t.Helper()
+
+	_, err := testDB.DB.Exec(`
+		UPDATE session_votes
+		SET created_at = NOW() - $4::interval
+		WHERE session_id = $1 AND user_id = $2 AND media_id = $3
+	`, sessionID, userID, mediaID, ago)
+	if err != nil {
+		t.Fatalf("Failed to set vote time: %v", err)
+	}
+}
+
+func TestVoteRepository_GetUserDislikedMedia(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and sessions
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "picky")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "other")
+
+	session1ID := testDB.SeedWatchSession(t, userID, "Session One", false)
+	session2ID := testDB.SeedWatchSession(t, userID, "Session Two", false)
+
+	t.Run("returns empty list when nothing disliked", func(t *testing.T) {
+		disliked, err := repo.GetUserDislikedMedia(ctx, userID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetUserDislikedMedia failed: %v", err)
+		}
+
+		if len(disliked) != 0 {
+			t.Errorf("Expected 0 disliked media, got %d", len(disliked))
+		}
+	})
+
+	// The same movie disliked in two sessions, least recently
+	repeatID := testDB.SeedMediaItem(t, 5001, "movie", "Disliked Twice")
+	testDB.SeedVote(t, session1ID, userID, repeatID, "no")
+	setVoteTime(t, testDB, session1ID, userID, repeatID, "3 hours")
+	testDB.SeedVote(t, session2ID, userID, repeatID, "no")
+	setVoteTime(t, testDB, session2ID, userID, repeatID, "2 hours")
+
+	onceID := testDB.SeedMediaItem(t, 5002, "movie", "Disliked Once")
+	testDB.SeedVote(t, session2ID, userID, onceID, "no")
+	setVoteTime(t, testDB, session2ID, userID, onceID, "1 hour")
+
+	// Neither a like nor someone else's dislike counts
+	likedID := testDB.SeedMediaItem(t, 5003, "movie", "Liked")
+	testDB.SeedVote(t, session1ID, userID, likedID, "yes")
+	testDB.SeedVote(t, session1ID, otherID, likedID, "no")
+
+	t.Run("returns each disliked media once across sessions", func(t *testing.T) {
+		disliked, err := repo.GetUserDislikedMedia(ctx, userID, 0, 0)
+		if err != nil {
+			t.Fatalf("GetUserDislikedMedia failed: %v", err)
+		}
+
+		if len(disliked) != 2 {
+			t.Fatalf("Expected 2 disliked media, got %d", len(disliked))
+		}
+
+		// Most recently disliked first
+		if disliked[0].ID != onceID {
+			t.Errorf("Expected first item 'Disliked Once', got '%s'", disliked[0].Title)
+		}
+		if disliked[1].ID != repeatID {
+			t.Errorf("Expected second item 'Disliked Twice', got '%s'", disliked[1].Title)
+		}
+	})
+
+	t.Run("respects limit and offset", func(t *testing.T) {
+		firstPage, err := repo.GetUserDislikedMedia(ctx, userID, 1, 0)
+		if err != nil {
+			t.Fatalf("GetUserDislikedMedia failed: %v", err)
+		}
+
+		if len(firstPage) != 1 || firstPage[0].ID != onceID {
+			t.Errorf("Expected first page to contain only 'Disliked Once', got %v", firstPage)
+		}
+
+		secondPage, err := repo.GetUserDislikedMedia(ctx, userID, 1, 1)
+		if err != nil {
+			t.Fatalf("GetUserDislikedMedia failed: %v", err)
+		}
+
+		if len(secondPage) != 1 || secondPage[0].ID != repeatID {
+			t.Errorf("Expected second page to contain only 'Disliked Twice', got %v", secondPage)
+		}
+
+		pastEnd, err := repo.GetUserDislikedMedia(ctx, userID, 1, 2)
+		if err != nil {
+			t.Fatalf("GetUserDislikedMedia failed: %v", err)
+		}
+
+		if len(pastEnd) != 0 {
+			t.Errorf("Expected empty page past the end, got %d items", len(pastEnd))
+		}
+	})
+}
+
+func TestVoteRepository_GetAllLikedByUser(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and sessions
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "fan")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "other")
+
+	session1ID := testDB.SeedWatchSession(t, userID, "Session One", false)
+	session2ID := testDB.SeedWatchSession(t, otherID, "Session Two", false)
+
+	t.Run("returns empty list when nothing liked", func(t *testing.T) {
+		liked, err := repo.GetAllLikedByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetAllLikedByUser failed: %v", err)
+		}
+
+		if len(liked) != 0 {
+			t.Errorf("Expected 0 liked media, got %d", len(liked))
+		}
+	})
+
+	// The same movie liked in two sessions; the newer like sets its position
+	repeatID := testDB.SeedMediaItem(t, 5101, "movie", "Liked Twice")
+	testDB.SeedVote(t, session1ID, userID, repeatID, "yes")
+	setVoteTime(t, testDB, session1ID, userID, repeatID, "3 hours")
+	testDB.SeedVote(t, session2ID, userID, repeatID, "yes")
+	setVoteTime(t, testDB, session2ID, userID, repeatID, "1 hour")
+
+	onceID := testDB.SeedMediaItem(t, 5102, "movie", "Liked Once")
+	testDB.SeedVote(t, session1ID, userID, onceID, "yes")
+	setVoteTime(t, testDB, session1ID, userID, onceID, "2 hours")
+
+	// Neither a dislike nor someone else's like counts
+	dislikedID := testDB.SeedMediaItem(t, 5103, "movie", "Disliked")
+	testDB.SeedVote(t, session1ID, userID, dislikedID, "no")
+	testDB.SeedVote(t, session1ID, otherID, dislikedID, "yes")
+
+	t.Run("returns each liked media once across sessions", func(t *testing.T) {
+		liked, err := repo.GetAllLikedByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetAllLikedByUser failed: %v", err)
+		}
+
+		if len(liked) != 2 {
+			t.Fatalf("Expected 2 liked media, got %d", len(liked))
+		}
+
+		// Most recently liked first
+		if liked[0].ID != repeatID {
+			t.Errorf("Expected first item 'Liked Twice', got '%s'", liked[0].Title)
+		}
+		if liked[1].ID != onceID {
+			t.Errorf("Expected second item 'Liked Once', got '%s'", liked[1].Title)
+		}
+	})
+}
+
+func TestVoteRepository_CountUserMatches(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and sessions
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	user3ID := uuid.New()
+	testDB.SeedProfile(t, user3ID, "user3")
+
+	session1ID := testDB.SeedWatchSession(t, user1ID, "Session 1", false)
+	session2ID := testDB.SeedWatchSession(t, user1ID, "Session 2", false)
+
+	t.Run("returns zero for user without votes", func(t *testing.T) {
+		count, err := repo.CountUserMatches(ctx, user3ID)
+		if err != nil {
+			t.Fatalf("CountUserMatches failed: %v", err)
+		}
+
+		if count != 0 {
+			t.Errorf("Expected 0 matches, got %d", count)
+		}
+	})
+
+	t.Run("counts only matches the user contributed to", func(t *testing.T) {
+		// Matched with user1's yes vote
+		matchedID := testDB.SeedMediaItem(t, 3001, "movie", "Matched Movie")
+		testDB.SeedVote(t, session1ID, user1ID, matchedID, "yes")
+		testDB.SeedVote(t, session1ID, user2ID, matchedID, "yes")
+
+		// The same movie matched again in another session counts once
+		testDB.SeedVote(t, session2ID, user1ID, matchedID, "yes")
+		testDB.SeedVote(t, session2ID, user2ID, matchedID, "yes")
+
+		// User1 liked it alone
+		lonelyID := testDB.SeedMediaItem(t, 3002, "movie", "Lonely Like")
+		testDB.SeedVote(t, session1ID, user1ID, lonelyID, "yes")
+
+		// Matched by others while user1 voted no
+		otherMatchID := testDB.SeedMediaItem(t, 3003, "movie", "Other Match")
+		testDB.SeedVote(t, session1ID, user1ID, otherMatchID, "no")
+		testDB.SeedVote(t, session1ID, user2ID, otherMatchID, "yes")
+		testDB.SeedVote(t, session1ID, user3ID, otherMatchID, "yes")
+
+		// User1 yes-voted in one session, the match happened in another
+		splitID := testDB.SeedMediaItem(t, 3004, "movie", "Split Sessions")
+		testDB.SeedVote(t, session1ID, user1ID, splitID, "yes")
+		testDB.SeedVote(t, session2ID, user2ID, splitID, "yes")
+		testDB.SeedVote(t, session2ID, user3ID, splitID, "yes")
+
+		count, err := repo.CountUserMatches(ctx, user1ID)
+		if err != nil {
+			t.Fatalf("CountUserMatches failed: %v", err)
+		}
+
+		if count != 1 {
+			t.Errorf("Expected 1 match, got %d", count)
+		}
+
+		count, err = repo.CountUserMatches(ctx, user2ID)
+		if err != nil {
+			t.Fatalf("CountUserMatches failed: %v", err)
+		}
+
+		// Matched Movie, Other Match, Split Sessions
+		if count != 3 {
+			t.Errorf("Expected 3 matches, got %d", count)
+		}
+	})
+}
+
+func TestVoteRepository_GetFollowingMatches(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: viewer follows friend, but not stranger
+	viewerID := uuid.New()
+	testDB.SeedProfile(t, viewerID, "viewer")
+
+	friendID := uuid.New()
+	testDB.SeedProfile(t, friendID, "friend")
+
+	strangerID := uuid.New()
+	testDB.SeedProfile(t, strangerID, "stranger")
+
+	otherID := uuid.New()
+	testDB.SeedProfile(t, otherID, "other")
+
+	testDB.SeedFollow(t, viewerID, friendID)
+
+	// Two yes votes plus the claim, as casting them through the API would record
+	seedMatch := func(sessionID, mediaID, firstID, secondID uuid.UUID) {
+		t.Helper()
+		testDB.SeedVote(t, sessionID, firstID, mediaID, "yes")
+		testDB.SeedVote(t, sessionID, secondID, mediaID, "yes")
+		if _, err := repo.ClaimMatch(ctx, sessionID, mediaID); err != nil {
+			t.Fatalf("ClaimMatch failed: %v", err)
+		}
+	}
+
+	t.Run("returns empty list when nothing matched", func(t *testing.T) {
+		matches, err := repo.GetFollowingMatches(ctx, viewerID, 20)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		if len(matches) != 0 {
+			t.Errorf("Expected 0 matches, got %d", len(matches))
+		}
+	})
+
+	t.Run("returns only public matches involving followed users", func(t *testing.T) {
+		// Public session created by friend
+		friendPublic := testDB.SeedWatchSession(t, friendID, "Friend Public", true)
+		publicMovie := testDB.SeedMediaItem(t, 4001, "movie", "Public Match")
+		seedMatch(friendPublic, publicMovie, friendID, otherID)
+
+		// Public session created by stranger where friend voted
+		strangerPublic := testDB.SeedWatchSession(t, strangerID, "Stranger Public", true)
+		joinedMovie := testDB.SeedMediaItem(t, 4002, "movie", "Joined Match")
+		seedMatch(strangerPublic, joinedMovie, strangerID, friendID)
+
+		// Private session created by friend
+		friendPrivate := testDB.SeedWatchSession(t, friendID, "Friend Private", false)
+		privateMovie := testDB.SeedMediaItem(t, 4003, "movie", "Private Match")
+		seedMatch(friendPrivate, privateMovie, friendID, otherID)
+
+		// Public session without any followed user
+		strangerOnly := testDB.SeedWatchSession(t, strangerID, "Stranger Only", true)
+		strangerMovie := testDB.SeedMediaItem(t, 4004, "movie", "Stranger Match")
+		seedMatch(strangerOnly, strangerMovie, strangerID, otherID)
+
+		// Public session by friend with no match
+		friendNoMatch := testDB.SeedWatchSession(t, friendID, "Friend No Match", true)
+		soloMovie := testDB.SeedMediaItem(t, 4005, "movie", "Solo Like")
+		testDB.SeedVote(t, friendNoMatch, friendID, soloMovie, "yes")
+
+		matches, err := repo.GetFollowingMatches(ctx, viewerID, 20)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		if len(matches) != 2 {
+			t.Fatalf("Expected 2 matches, got %d", len(matches))
+		}
+
+		found := map[string]bool{}
+		for _, match := range matches {
+			found[match.Media.Title] = true
+		}
+
+		if !found["Public Match"] || !found["Joined Match"] {
+			t.Errorf("Expected 'Public Match' and 'Joined Match', got %v", found)
+		}
+	})
+
+	t.Run("ignores sessions the followed user was only invited to", func(t *testing.T) {
+		invitedSession := testDB.SeedWatchSession(t, strangerID, "Invited Only", true)
+		testDB.SeedRoomParticipant(t, invitedSession, friendID, "member", "invited")
+		invitedMovie := testDB.SeedMediaItem(t, 4006, "movie", "Invited Match")
+		seedMatch(invitedSession, invitedMovie, strangerID, otherID)
+
+		matches, err := repo.GetFollowingMatches(ctx, viewerID, 20)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		for _, match := range matches {
+			if match.SessionID == invitedSession {
+				t.Errorf("Expected no match from a session the friend has not joined, got %s", match.Media.Title)
+			}
+		}
+	})
+
+	t.Run("leaves out matches older than the window", func(t *testing.T) {
+		oldSession := testDB.SeedWatchSession(t, friendID, "Old Public", true)
+		oldMovie := testDB.SeedMediaItem(t, 4007, "movie", "Old Match")
+		seedMatch(oldSession, oldMovie, friendID, otherID)
+
+		_, err := testDB.DB.Exec(
+			`UPDATE session_matches SET matched_at = NOW() - make_interval(secs => $2) - INTERVAL '1 day' WHERE session_id = $1`,
+			oldSession, FollowingMatchesWindow.Seconds(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to backdate match: %v", err)
+		}
+
+		matches, err := repo.GetFollowingMatches(ctx, viewerID, 20)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		if len(matches) != 2 {
+			t.Errorf("Expected 2 matches, got %d", len(matches))
+		}
+		for _, match := range matches {
+			if match.SessionID == oldSession {
+				t.Error("Expected a match older than the window to be left out")
+			}
+		}
+	})
+
+	t.Run("respects the limit", func(t *testing.T) {
+		matches, err := repo.GetFollowingMatches(ctx, viewerID, 1)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		if len(matches) != 1 {
+			t.Errorf("Expected 1 match, got %d", len(matches))
+		}
+	})
+
+	t.Run("returns nothing for a user who follows nobody", func(t *testing.T) {
+		matches, err := repo.GetFollowingMatches(ctx, strangerID, 20)
+		if err != nil {
+			t.Fatalf("GetFollowingMatches failed: %v", err)
+		}
+
+		if len(matches) != 0 {
+			t.Errorf("Expected 0 matches, got %d", len(matches))
+		}
+	})
+}
+
+func TestVoteRepository_FindOrphanedVotes(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users, session and votes
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "user1")
+
+	sessionID := testDB.SeedWatchSession(t, userID, "Test Session", false)
+
+	t.Run("returns empty list when every vote resolves", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 8001, "movie", "Intact Movie")
+		testDB.SeedVote(t, sessionID, userID, mediaID, "yes")
+
+		votes, err := repo.FindOrphanedVotes(ctx)
+		if err != nil {
+			t.Fatalf("FindOrphanedVotes failed: %v", err)
+		}
+
+		if len(votes) != 0 {
+			t.Errorf("Expected 0 orphaned votes, got %d", len(votes))
+		}
+	})
+
+	t.Run("reports votes whose media item was removed", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 8002, "movie", "Deleted Movie")
+		testDB.SeedVote(t, sessionID, userID, mediaID, "no")
+
+		// Delete the media row with FK enforcement relaxed to simulate a bad merge
+		tx, err := testDB.DB.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+		if _, err := tx.Exec("SET LOCAL session_replication_role = replica"); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to relax FK checks: %v", err)
+		}
+		if _, err := tx.Exec("DELETE FROM media_items WHERE id = $1", mediaID); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to delete media item: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+
+		votes, err := repo.FindOrphanedVotes(ctx)
+		if err != nil {
+			t.Fatalf("FindOrphanedVotes failed: %v", err)
+		}
+
+		if len(votes) != 1 {
+			t.Fatalf("Expected 1 orphaned vote, got %d", len(votes))
+		}
+
+		if votes[0].MediaID != mediaID {
+			t.Errorf("Expected orphaned media ID %s, got %s", mediaID, votes[0].MediaID)
+		}
+		if votes[0].SessionID != sessionID || votes[0].UserID != userID {
+			t.Errorf("Expected vote from session %s and user %s, got %s and %s", sessionID, userID, votes[0].SessionID, votes[0].UserID)
+		}
+		if votes[0].Vote != "no" {
+			t.Errorf("Expected vote 'no', got '%s'", votes[0].Vote)
+		}
+	})
+}
+
+func TestVoteRepository_GetConsensusScore(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users and media
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	media1ID := testDB.SeedMediaItem(t, 12001, "movie", "Movie One")
+	media2ID := testDB.SeedMediaItem(t, 12002, "movie", "Movie Two")
+	media3ID := testDB.SeedMediaItem(t, 12003, "movie", "Movie Three")
+	media4ID := testDB.SeedMediaItem(t, 12004, "movie", "Movie Four")
+
+	t.Run("high agreement session scores near 1", func(t *testing.T) {
+		sessionID := testDB.SeedWatchSession(t, user1ID, "Agreeable", false)
+		for _, mediaID := range []uuid.UUID{media1ID, media2ID, media3ID} {
+			testDB.SeedVote(t, sessionID, user1ID, mediaID, "yes")
+			testDB.SeedVote(t, sessionID, user2ID, mediaID, "yes")
+		}
+		testDB.SeedVote(t, sessionID, user1ID, media4ID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, media4ID, "no")
+
+		score, err := repo.GetConsensusScore(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetConsensusScore failed: %v", err)
+		}
+
+		if score != 0.75 {
+			t.Errorf("Expected score 0.75, got %v", score)
+		}
+	})
+
+	t.Run("divisive session scores near 0", func(t *testing.T) {
+		sessionID := testDB.SeedWatchSession(t, user1ID, "Divisive", false)
+		testDB.SeedVote(t, sessionID, user1ID, media1ID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, media1ID, "yes")
+		for _, mediaID := range []uuid.UUID{media2ID, media3ID, media4ID} {
+			testDB.SeedVote(t, sessionID, user1ID, mediaID, "yes")
+			testDB.SeedVote(t, sessionID, user2ID, mediaID, "no")
+		}
+
+		score, err := repo.GetConsensusScore(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetConsensusScore failed: %v", err)
+		}
+
+		if score != 0.25 {
+			t.Errorf("Expected score 0.25, got %v", score)
+		}
+	})
+
+	t.Run("session without votes scores 0", func(t *testing.T) {
+		sessionID := testDB.SeedWatchSession(t, user1ID, "Empty", false)
+
+		score, err := repo.GetConsensusScore(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetConsensusScore failed: %v", err)
+		}
+
+		if score != 0 {
+			t.Errorf("Expected score 0, got %v", score)
+		}
+	})
+}
+
+func TestVoteRepository_GetSessionMatchIntersection(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	repo := NewVoteRepository(testDB.DB)
+	ctx := context.Background()
+
+	// Setup: Create users, sessions and media
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionAID := testDB.SeedWatchSession(t, user1ID, "Session A", false)
+	sessionBID := testDB.SeedWatchSession(t, user2ID, "Session B", false)
+	sessionCID := testDB.SeedWatchSession(t, user2ID, "Session C", false)
+
+	sharedID := testDB.SeedMediaItem(t, 13001, "movie", "Shared Favorite")
+	onlyAID := testDB.SeedMediaItem(t, 13002, "movie", "Only In A")
+	onlyBID := testDB.SeedMediaItem(t, 13003, "movie", "Only In B")
+	halfID := testDB.SeedMediaItem(t, 13004, "movie", "Half Liked")
+	otherID := testDB.SeedMediaItem(t, 13005, "movie", "Session C Pick")
+
+	match := func(sessionID, mediaID uuid.UUID) {
+		testDB.SeedVote(t, sessionID, user1ID, mediaID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, mediaID, "yes")
+	}
+
+	match(sessionAID, sharedID)
+	match(sessionAID, onlyAID)
+	match(sessionAID, halfID)
+
+	match(sessionBID, sharedID)
+	match(sessionBID, onlyBID)
+	// Liked by only one person in B, so not a match there
+	testDB.SeedVote(t, sessionBID, user1ID, halfID, "yes")
+
+	match(sessionCID, otherID)
+
+	t.Run("returns media matched in both sessions", func(t *testing.T) {
+		matches, err := repo.GetSessionMatchIntersection(ctx, sessionAID, sessionBID)
+		if err != nil {
+			t.Fatalf("GetSessionMatchIntersection failed: %v", err)
+		}
+
+		if len(matches) != 1 {
+			t.Fatalf("Expected 1 common match, got %d", len(matches))
+		}
+		if matches[0].ID != sharedID {
+			t.Errorf("Expected '%s', got '%s'", "Shared Favorite", matches[0].Title)
+		}
+	})
+
+	t.Run("returns empty list for disjoint match sets", func(t *testing.T) {
+		matches, err := repo.GetSessionMatchIntersection(ctx, sessionAID, sessionCID)
+		if err != nil {
+			t.Fatalf("GetSessionMatchIntersection failed: %v", err)
+		}
+
+		if len(matches) != 0 {
+			t.Errorf("Expected 0 common matches, got %d", len(matches))
 		}
 	})
 }
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Upstream services whose outbound API calls are counted
+const (
+	UpstreamTMDB   = "tmdb"
+	UpstreamOpenAI = "openai"
+)
+
+// Kinds of OpenAI tokens whose consumption is totalled
+const (
+	TokensPrompt     = "prompt"
+	TokensCompletion = "completion"
+)
+
+// Background jobs whose last completed run is reported
+const (
+	JobPrecache     = "precache"
+	JobMediaCleanup = "media_cleanup"
+)
+
+// Registry holds in-process request and upstream call counters
+// Recording on a nil registry is a no-op, so clients and middleware work without metrics
+type Registry struct {
+	mu            sync.Mutex
+	requests      map[string]int64
+	statusClasses map[string]int64
+	upstreamCalls map[string]int64
+	openAITokens  map[string]int64
+	jobLastRun    map[string]time.Time
+}
+
+// Snapshot is a point-in-time copy of every counter, as served by the metrics endpoint
+type Snapshot struct {
+	Requests      map[string]int64 `json:"requests"`
+	StatusClasses map[string]int64 `json:"status_classes"`
+	UpstreamCalls map[string]int64 `json:"upstream_calls"`
+	// OpenAITokens totals the prompt and completion tokens OpenAI reported, to track spend
+	OpenAITokens map[string]int64 `json:"openai_tokens"`
+	// JobLastRun holds when each background job last finished; jobs that have not run yet are absent
+	JobLastRun map[string]time.Time `json:"job_last_run"`
+}
+
+// NewRegistry creates a registry with the upstream counters starting at zero
+func NewRegistry() *Registry {
+	return &Registry{
+		requests:      make(map[string]int64),
+		statusClasses: make(map[string]int64),
+		upstreamCalls: map[string]int64{
+			UpstreamTMDB:   0,
+			UpstreamOpenAI: 0,
+		},
+		openAITokens: map[string]int64{
+			TokensPrompt:     0,
+			TokensCompletion: 0,
+		},
+		jobLastRun: make(map[string]time.Time),
+	}
+}
+
+// ObserveRequest counts a completed request against its route and status class
+func (r *Registry) ObserveRequest(route string, status int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[route]++
+	r.statusClasses[StatusClass(status)]++
+}
+
+// IncUpstreamCall counts one outbound API call to service
+func (r *Registry) IncUpstreamCall(service string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.upstreamCalls[service]++
+}
+
+// AddOpenAITokens adds the token usage OpenAI reported for one completion
+func (r *Registry) AddOpenAITokens(prompt, completion int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.openAITokens[TokensPrompt] += int64(prompt)
+	r.openAITokens[TokensCompletion] += int64(completion)
+}
+
+// ObserveJobRun records that a background job finished a run at the given time
+func (r *Registry) ObserveJobRun(job string, at time.Time) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobLastRun[job] = at
+}
+
+// Snapshot copies the current counter values
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Snapshot{
+		Requests:      copyCounters(r.requests),
+		StatusClasses: copyCounters(r.statusClasses),
+		UpstreamCalls: copyCounters(r.upstreamCalls),
+		OpenAITokens:  copyCounters(r.openAITokens),
+		JobLastRun:    copyTimes(r.jobLastRun),
+	}
+}
+
+// Handler serves the current snapshot as JSON
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.Snapshot())
+	})
+}
+
+// StatusClass groups a status code by its first digit, e.g. 404 becomes "4xx"
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func copyCounters(counters map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counters))
+	for key, value := range counters {
+		copied[key] = value
+	}
+	return copied
+}
+
+func copyTimes(times map[string]time.Time) map[string]time.Time {
+	copied := make(map[string]time.Time, len(times))
+	for key, value := range times {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// AdminMiddleware restricts access to the given user IDs. It must run after AuthMiddleware.
+func AdminMiddleware(adminUserIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[string]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r.Context())
+			if !ok {
+				http.Error(w, "User ID not found", http.StatusUnauthorized)
+				return
+			}
+
+			if !admins[userID] {
+				http.Error(w, "Admin access required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
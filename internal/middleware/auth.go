@@ -3,10 +3,13 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
 )
 
 // ContextKey is a custom type for context keys to avoid collisions
@@ -17,20 +20,22 @@ const (
 	UserIDKey ContextKey = "userID"
 )
 
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 // AuthMiddleware creates a middleware that validates Supabase JWT tokens using JWT secret
-func AuthMiddleware(supabaseURL string, jwtSecret string) func(http.Handler) http.Handler {
+// leeway is how far past exp (and before nbf/iat) a token is still accepted, to tolerate client clock skew.
+// A non-empty audience requires the aud claim to match it and, when supabaseURL is set, iss to be
+// the project's auth endpoint, so tokens minted for another project sharing the secret are rejected
+func AuthMiddleware(supabaseURL string, jwtSecret string, leeway time.Duration, audience string) func(http.Handler) http.Handler {
 	// Use JWT secret as-is (Supabase uses the raw string, not base64-decoded)
-	fmt.Printf("🔐 [AuthMiddleware] JWT secret (first 20 chars): %s...\n", jwtSecret[:min(20, len(jwtSecret))])
 	secretKey := []byte(jwtSecret)
-	fmt.Printf("✅ [AuthMiddleware] Using JWT secret directly, %d bytes\n", len(secretKey))
+	slog.Debug("auth middleware configured", "secret_bytes", len(secretKey))
+
+	parserOptions := []jwt.ParserOption{jwt.WithLeeway(leeway)}
+	if audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(audience))
+		if supabaseURL != "" {
+			parserOptions = append(parserOptions, jwt.WithIssuer(supabaseIssuer(supabaseURL)))
+		}
+	}
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -49,23 +54,22 @@ func AuthMiddleware(supabaseURL string, jwtSecret string) func(http.Handler) htt
 			}
 
 			tokenString := parts[1]
-
-			fmt.Printf("🔐 [AuthMiddleware] Validating token (first 50 chars): %s...\n", tokenString[:min(50, len(tokenString))])
-			fmt.Printf("🔐 [AuthMiddleware] Using secret key of %d bytes\n", len(secretKey))
+			reqLogger := logger.FromContext(r.Context())
+			reqLogger.Debug("validating token", "token_bytes", len(tokenString))
 
 			// Parse and validate the JWT token using the JWT secret
 			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 				// Verify the signing method is HMAC
 				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					fmt.Printf("❌ [AuthMiddleware] Unexpected signing method: %v\n", token.Header["alg"])
+					reqLogger.Debug("unexpected signing method", "alg", token.Header["alg"])
 					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 				}
-				fmt.Printf("✅ [AuthMiddleware] Signing method is HMAC: %v\n", token.Method.Alg())
+				reqLogger.Debug("signing method accepted", "alg", token.Method.Alg())
 				return secretKey, nil
-			})
+			}, parserOptions...)
 
 			if err != nil {
-				fmt.Printf("❌ [AuthMiddleware] Token validation error: %v\n", err) // Debug log
+				reqLogger.Debug("token validation failed", "error", err)
 				http.Error(w, fmt.Sprintf("Invalid token: %v", err), http.StatusUnauthorized)
 				return
 			}
@@ -89,13 +93,19 @@ func AuthMiddleware(supabaseURL string, jwtSecret string) func(http.Handler) htt
 				return
 			}
 
-			// Inject user ID into context
+			// Inject user ID and a logger tagged with it into context
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			ctx = logger.WithContext(ctx, reqLogger.With("user_id", userID))
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// supabaseIssuer returns the iss claim Supabase Auth puts in tokens for the project at supabaseURL
+func supabaseIssuer(supabaseURL string) string {
+	return strings.TrimRight(supabaseURL, "/") + "/auth/v1"
+}
+
 // GetUserID extracts the user ID from the request context
 func GetUserID(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserIDKey).(string)
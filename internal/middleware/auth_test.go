@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthMiddleware_Leeway(t *testing.T) {
+	secret := "test-secret"
+
+	// Expired ten seconds ago, as seen by a server whose clock runs slightly ahead of the client's
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-123",
+		"exp": time.Now().Add(-10 * time.Second).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		leeway     time.Duration
+		wantStatus int
+	}{
+		{"accepted within a 30s leeway", 30 * time.Second, http.StatusOK},
+		{"rejected without leeway", 0, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AuthMiddleware("", secret, tt.leeway, "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/rooms", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_AudienceAndIssuer(t *testing.T) {
+	secret := "test-secret"
+	supabaseURL := "https://project.supabase.co"
+
+	sign := func(claims jwt.MapClaims) string {
+		t.Helper()
+		claims["sub"] = "user-123"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		audience   string
+		token      string
+		wantStatus int
+	}{
+		{"matching audience and issuer", "authenticated", sign(jwt.MapClaims{"aud": "authenticated", "iss": supabaseURL + "/auth/v1"}), http.StatusOK},
+		{"wrong audience", "authenticated", sign(jwt.MapClaims{"aud": "anon", "iss": supabaseURL + "/auth/v1"}), http.StatusUnauthorized},
+		{"another project's issuer", "authenticated", sign(jwt.MapClaims{"aud": "authenticated", "iss": "https://other.supabase.co/auth/v1"}), http.StatusUnauthorized},
+		{"missing claims when validation is on", "authenticated", sign(jwt.MapClaims{}), http.StatusUnauthorized},
+		{"missing claims when validation is off", "", sign(jwt.MapClaims{}), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AuthMiddleware(supabaseURL+"/", secret, 0, tt.audience)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/rooms", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
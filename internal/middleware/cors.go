@@ -4,20 +4,60 @@ import (
 	"net/http"
 )
 
-// CORSMiddleware handles Cross-Origin Resource Sharing
+const (
+	corsAllowMethods = "POST, GET, OPTIONS, PUT, DELETE"
+	corsAllowHeaders = "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"
+)
+
+// CORSMiddleware handles Cross-Origin Resource Sharing for any origin
+// Intended for local development; use CORSMiddlewareWithConfig in production
 func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*") // For development; lock down in production
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+	return CORSMiddlewareWithConfig(nil, false)(next)
+}
+
+// CORSMiddlewareWithConfig handles Cross-Origin Resource Sharing for an origin allowlist
+// An empty allowlist or a "*" entry allows every origin
+func CORSMiddlewareWithConfig(allowedOrigins []string, allowCredentials bool) func(http.Handler) http.Handler {
+	allowAll := len(allowedOrigins) == 0
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
 		}
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			originAllowed := allowAll || allowed[origin]
+
+			if originAllowed {
+				// Browsers reject a wildcard origin on credentialed requests, so echo the caller instead
+				if allowAll && !allowCredentials {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else if origin != "" {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Add("Vary", "Origin")
+				}
+				if allowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			// Handle preflight requests
+			if r.Method == http.MethodOptions {
+				if origin != "" && !originAllowed {
+					http.Error(w, "Origin not allowed", http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
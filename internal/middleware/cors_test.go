@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddlewareWithConfig(t *testing.T) {
+	var reached bool
+	handler := CORSMiddlewareWithConfig([]string{"https://app.example.com"}, true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("reflects an allowed origin", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Expected allowed origin to be reflected, got '%s'", got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("Expected credentials to be allowed, got '%s'", got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("Expected Vary: Origin, got '%s'", got)
+		}
+		if !reached {
+			t.Error("Expected request to reach the next handler")
+		}
+	})
+
+	t.Run("omits headers for a disallowed origin", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin, got '%s'", got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Credentials, got '%s'", got)
+		}
+		if !reached {
+			t.Error("Expected same-origin enforcement to be left to the browser")
+		}
+	})
+
+	t.Run("answers a preflight request", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest(http.MethodOptions, "/api/sessions", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Expected allowed origin to be reflected, got '%s'", got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Methods"); got != corsAllowMethods {
+			t.Errorf("Expected Allow-Methods '%s', got '%s'", corsAllowMethods, got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Headers"); got != corsAllowHeaders {
+			t.Errorf("Expected Allow-Headers '%s', got '%s'", corsAllowHeaders, got)
+		}
+		if reached {
+			t.Error("Expected preflight to be answered without calling the next handler")
+		}
+	})
+
+	t.Run("rejects a preflight from a disallowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/sessions", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "" {
+			t.Errorf("Expected no Allow-Methods, got '%s'", got)
+		}
+	})
+}
+
+func TestCORSMiddleware_AllowsAnyOrigin(t *testing.T) {
+	handler := CORSMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected wildcard origin, got '%s'", got)
+	}
+}
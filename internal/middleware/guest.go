@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+)
+
+// GuestTokenParam is the query parameter that carries a guest voting token
+const GuestTokenParam = "guest_token"
+
+// GuestSessionIDKey is the context key for the session a guest token is limited to
+const GuestSessionIDKey ContextKey = "guestSessionID"
+
+// GuestTokenResolver returns the guest user ID a token grants and the session it is limited to,
+// or empty strings if the token is unknown or expired
+type GuestTokenResolver func(ctx context.Context, token string) (guestID, sessionID string, err error)
+
+// GuestTokenMiddleware accepts ?guest_token= in place of a JWT, injecting the guest's user ID and session into context
+// Handlers must confine guests to GetGuestSessionID's session.
+// Requests with an Authorization header or without a guest token are passed to auth unchanged
+func GuestTokenMiddleware(resolve GuestTokenResolver, auth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := auth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get(GuestTokenParam)
+			if token == "" || r.Header.Get("Authorization") != "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+
+			reqLogger := logger.FromContext(r.Context())
+
+			guestID, sessionID, err := resolve(r.Context(), token)
+			if err != nil {
+				reqLogger.Error("guest token lookup failed", "error", err)
+				writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to validate guest token")
+				return
+			}
+
+			if guestID == "" {
+				writeJSONError(w, http.StatusUnauthorized, "unauthorized", "Invalid or expired guest token")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, guestID)
+			ctx = context.WithValue(ctx, GuestSessionIDKey, sessionID)
+			ctx = logger.WithContext(ctx, reqLogger.With("user_id", guestID, "guest", true))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetGuestSessionID returns the session a guest-token request is limited to; ok is false for JWT requests
+func GetGuestSessionID(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(GuestSessionIDKey).(string)
+	return sessionID, ok
+}
+
+// writeJSONError responds with the API's error envelope {"error":{"code":...,"message":...}}
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]map[string]string{
+		"error": {"code": code, "message": message},
+	})
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGuestTokenMiddleware(t *testing.T) {
+	resolve := func(ctx context.Context, token string) (string, string, error) {
+		switch token {
+		case "valid":
+			return "guest-user", "guest-session", nil
+		case "broken":
+			return "", "", errors.New("database unavailable")
+		}
+		return "", "", nil
+	}
+
+	// Stand-in for AuthMiddleware that admits any request with a header
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				http.Error(w, "Missing authorization header", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(SetUserID(r.Context(), "jwt-user")))
+		})
+	}
+
+	var contextID, contextSessionID string
+	handler := GuestTokenMiddleware(resolve, auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contextID, _ = GetUserID(r.Context())
+		contextSessionID, _ = GetGuestSessionID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		query         string
+		authHeader    string
+		wantStatus    int
+		wantUserID    string
+		wantSessionID string
+	}{
+		{"valid guest token", "?guest_token=valid", "", http.StatusOK, "guest-user", "guest-session"},
+		{"expired or unknown guest token", "?guest_token=expired", "", http.StatusUnauthorized, "", ""},
+		{"lookup failure", "?guest_token=broken", "", http.StatusInternalServerError, "", ""},
+		{"authorization header takes precedence", "?guest_token=valid", "Bearer token", http.StatusOK, "jwt-user", ""},
+		{"no guest token falls through to auth", "", "", http.StatusUnauthorized, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contextID, contextSessionID = "", ""
+			req := httptest.NewRequest(http.MethodPost, "/api/sessions/abc/vote"+tt.query, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if contextID != tt.wantUserID {
+				t.Errorf("Expected user ID '%s', got '%s'", tt.wantUserID, contextID)
+			}
+			if contextSessionID != tt.wantSessionID {
+				t.Errorf("Expected guest session '%s', got '%s'", tt.wantSessionID, contextSessionID)
+			}
+		})
+	}
+}
+
+func TestGuestTokenMiddleware_JSONErrors(t *testing.T) {
+	resolve := func(ctx context.Context, token string) (string, string, error) {
+		return "", "", nil
+	}
+	auth := func(next http.Handler) http.Handler { return next }
+
+	handler := GuestTokenMiddleware(resolve, auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected an unknown token not to reach the handler")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sessions/abc/vote?guest_token=unknown", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected a JSON error, got Content-Type %q", ct)
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode error body: %v", err)
+	}
+	if body.Error.Code != "unauthorized" || body.Error.Message == "" {
+		t.Errorf("Expected an unauthorized error envelope, got %+v", body.Error)
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tahaburak/would-watch-backend/internal/logger"
+)
+
+func TestRequestLogger_StructuredFields(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.New(&buf, slog.LevelInfo)
+
+	secret := "test-secret"
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-123"}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context()).Info("handled")
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := RequestIDMiddleware(RequestLogger(base)(AuthMiddleware("", secret, 0, "")(inner)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/rooms", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(RequestIDHeader, "req-abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	lines := make(map[string]map[string]interface{})
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected JSON log line, got %q: %v", scanner.Text(), err)
+		}
+		lines[entry["msg"].(string)] = entry
+	}
+
+	t.Run("handler log carries request and user fields", func(t *testing.T) {
+		entry, ok := lines["handled"]
+		if !ok {
+			t.Fatal("Expected a 'handled' log line")
+		}
+
+		expected := map[string]interface{}{
+			"request_id": "req-abc",
+			"user_id":    "user-123",
+			"method":     "GET",
+			"path":       "/api/rooms",
+		}
+		for key, value := range expected {
+			if entry[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+			}
+		}
+	})
+
+	t.Run("completion log carries status", func(t *testing.T) {
+		entry, ok := lines["request completed"]
+		if !ok {
+			t.Fatal("Expected a 'request completed' log line")
+		}
+
+		if entry["status"] != float64(http.StatusTeapot) {
+			t.Errorf("Expected status %d, got %v", http.StatusTeapot, entry["status"])
+		}
+		if entry["request_id"] != "req-abc" {
+			t.Errorf("Expected request_id 'req-abc', got %v", entry["request_id"])
+		}
+		if _, ok := entry["duration_ms"]; !ok {
+			t.Error("Expected duration_ms field")
+		}
+	})
+
+	t.Run("debug output is suppressed at info level", func(t *testing.T) {
+		if _, ok := lines["validating token"]; ok {
+			t.Error("Expected debug lines to be filtered at info level")
+		}
+	})
+}
+
+func TestRequestLogger_AllowsHijack(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.New(&buf, slog.LevelInfo)
+
+	handler := RequestLogger(base)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("Expected the wrapped writer to support hijacking")
+			return
+		}
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		conn.Close()
+	}))
+
+	logged := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(logged)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	<-logged
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"status":101`)) {
+		t.Errorf("Expected hijacked request to be logged as switching protocols, got %s", buf.String())
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+)
+
+// unmatchedRoute labels requests that no registered pattern handled
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware counts each request by route pattern and status class
+// It must wrap the ServeMux directly so the matched pattern is visible once the mux returns
+func MetricsMiddleware(registry *metrics.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			// Patterns keep the label set bounded; raw paths would add a counter per ID
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			registry.ObserveRequest(route, recorder.status)
+		})
+	}
+}
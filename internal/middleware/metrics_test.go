@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+)
+
+func TestMetricsMiddleware_CountsRequests(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rooms/{id}/close", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.Handle("/metrics", registry.Handler())
+	handler := MetricsMiddleware(registry)(mux)
+
+	for _, path := range []string{"/api/rooms/a/close", "/api/rooms/b/close", "/nowhere"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var snapshot metrics.Snapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snapshot); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+
+	t.Run("counts requests per route pattern", func(t *testing.T) {
+		if got := snapshot.Requests["/api/rooms/{id}/close"]; got != 2 {
+			t.Errorf("Expected 2 requests for the close route, got %d", got)
+		}
+		if got := snapshot.Requests[unmatchedRoute]; got != 1 {
+			t.Errorf("Expected 1 unmatched request, got %d", got)
+		}
+	})
+
+	t.Run("counts status classes", func(t *testing.T) {
+		if got := snapshot.StatusClasses["4xx"]; got != 3 {
+			t.Errorf("Expected 3 4xx responses, got %d", got)
+		}
+	})
+
+	t.Run("reports upstream counters from zero", func(t *testing.T) {
+		for _, service := range []string{metrics.UpstreamTMDB, metrics.UpstreamOpenAI} {
+			if got, ok := snapshot.UpstreamCalls[service]; !ok || got != 0 {
+				t.Errorf("Expected %s calls to be reported as 0, got %d (present: %v)", service, got, ok)
+			}
+		}
+	})
+
+	t.Run("reports OpenAI token totals from zero", func(t *testing.T) {
+		for _, kind := range []string{metrics.TokensPrompt, metrics.TokensCompletion} {
+			if got, ok := snapshot.OpenAITokens[kind]; !ok || got != 0 {
+				t.Errorf("Expected %s tokens to be reported as 0, got %d (present: %v)", kind, got, ok)
+			}
+		}
+	})
+}
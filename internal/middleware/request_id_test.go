@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var contextID string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contextID, _ = GetRequestID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("generates an ID when none is sent", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		headerID := rec.Header().Get(RequestIDHeader)
+		if _, err := uuid.Parse(headerID); err != nil {
+			t.Fatalf("Expected generated UUID in response header, got '%s'", headerID)
+		}
+		if contextID != headerID {
+			t.Errorf("Expected context ID '%s' to match header, got '%s'", headerID, contextID)
+		}
+	})
+
+	t.Run("preserves an inbound ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(RequestIDHeader, "client-trace-42")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(RequestIDHeader); got != "client-trace-42" {
+			t.Errorf("Expected response header 'client-trace-42', got '%s'", got)
+		}
+		if contextID != "client-trace-42" {
+			t.Errorf("Expected context ID 'client-trace-42', got '%s'", contextID)
+		}
+	})
+}
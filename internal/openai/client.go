@@ -5,15 +5,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
 )
 
+// DefaultModel is the chat model used when none is configured
+const DefaultModel = "gpt-4o-mini"
+
+// DefaultTimeout bounds each OpenAI request when no timeout is configured
+const DefaultTimeout = 30 * time.Second
+
 // Client represents an OpenAI API client
 type Client struct {
 	APIKey  string
 	BaseURL string
+	Model   string
+	// Metrics counts every OpenAI API call when set
+	Metrics *metrics.Registry
 	client  *http.Client
 }
 
@@ -34,19 +47,54 @@ type ChatResponse struct {
 	Choices []struct {
 		Message ChatMessage `json:"message"`
 	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// Usage is the token count OpenAI bills a completion by
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
-// NewClient creates a new OpenAI client
+// NewClient creates a new OpenAI client using DefaultModel
 func NewClient(apiKey string) *Client {
+	return NewClientWithModel(apiKey, DefaultModel)
+}
+
+// NewClientWithModel creates a new OpenAI client for the given chat model, falling back to DefaultModel when empty
+func NewClientWithModel(apiKey, model string) *Client {
+	c := NewClientWithTimeout(apiKey, DefaultTimeout)
+	if model != "" {
+		c.Model = model
+	}
+	return c
+}
+
+// NewClientWithTimeout creates a new OpenAI client using DefaultModel whose requests are abandoned after timeout
+func NewClientWithTimeout(apiKey string, timeout time.Duration) *Client {
+	return NewClientWithHTTP(apiKey, &http.Client{
+		Timeout: timeout,
+	})
+}
+
+// NewClientWithHTTP creates a new OpenAI client using DefaultModel that sends requests through hc
+// Use it to share one pooled http.Client across API clients
+func NewClientWithHTTP(apiKey string, hc *http.Client) *Client {
 	return &Client{
 		APIKey:  apiKey,
 		BaseURL: "https://api.openai.com/v1",
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		Model:   DefaultModel,
+		client:  hc,
 	}
 }
 
+// Recommendation is a recommended movie and the model's explanation for it
+type Recommendation struct {
+	TMDBID int    `json:"tmdb_id"`
+	Reason string `json:"reason"`
+}
+
 // GetRecommendations gets movie recommendations based on liked movies
 // Returns TMDB IDs of recommended movies
 func (c *Client) GetRecommendations(likedMovies []string) ([]int, error) {
@@ -58,9 +106,121 @@ func (c *Client) GetRecommendations(likedMovies []string) ([]int, error) {
 	movieList := strings.Join(likedMovies, ", ")
 	prompt := fmt.Sprintf(`You are a movie expert. Given these movies that users liked: [%s], recommend 5 distinct movies that they would enjoy. Return ONLY a JSON array of TMDB IDs as integers, nothing else. Example format: [123, 456, 789, 101, 202]`, movieList)
 
+	content, err := c.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON array of IDs
+	var tmdbIDs []int
+	if err := json.Unmarshal([]byte(content), &tmdbIDs); err != nil {
+		return nil, fmt.Errorf("failed to parse TMDB IDs from response: %w (content: %s)", err, content)
+	}
+
+	if len(tmdbIDs) == 0 {
+		return nil, fmt.Errorf("no TMDB IDs returned")
+	}
+
+	return tmdbIDs, nil
+}
+
+// DefaultRecommendationCount is how many movies are requested when no count is given
+const DefaultRecommendationCount = 5
+
+// GetRecommendationsWithReasons gets movie recommendations based on liked movies,
+// each with a short explanation of why it was picked
+func (c *Client) GetRecommendationsWithReasons(likedMovies []string) ([]Recommendation, error) {
+	return c.GetRecommendationsExcluding(likedMovies, nil, DefaultRecommendationCount)
+}
+
+// GetRecommendationsExcluding gets count recommendations with reasons like GetRecommendationsWithReasons,
+// telling the model which TMDB IDs were already suggested; any it repeats anyway are dropped
+// count below 1 uses DefaultRecommendationCount
+func (c *Client) GetRecommendationsExcluding(likedMovies []string, excludeIDs []int, count int) ([]Recommendation, error) {
+	if len(likedMovies) == 0 {
+		return nil, fmt.Errorf("no liked movies provided")
+	}
+	if count < 1 {
+		count = DefaultRecommendationCount
+	}
+
+	// Create the prompt
+	movieList := strings.Join(likedMovies, ", ")
+	exclusion := ""
+	if len(excludeIDs) > 0 {
+		ids := make([]string, 0, len(excludeIDs))
+		for _, id := range excludeIDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		exclusion = fmt.Sprintf(` These TMDB IDs were already suggested, exclude these: [%s].`, strings.Join(ids, ", "))
+	}
+	prompt := fmt.Sprintf(`You are a movie expert. Given these movies that users liked: [%s], recommend %d distinct movies that they would enjoy.%s Return ONLY a JSON array of objects with a "tmdb_id" integer and a one-sentence "reason" explaining the pick in terms of the liked movies, nothing else. Example format: [{"tmdb_id": 123, "reason": "Another mind-bending sci-fi thriller like The Matrix."}]`, movieList, count, exclusion)
+
+	content, err := c.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations, err := parseRecommendations(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(excludeIDs) == 0 {
+		return recommendations, nil
+	}
+
+	excluded := make(map[int]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+
+	fresh := make([]Recommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		if !excluded[rec.TMDBID] {
+			fresh = append(fresh, rec)
+		}
+	}
+
+	return fresh, nil
+}
+
+// parseRecommendations parses the model's JSON array of recommendations
+// A missing reason is left empty, and bare integer IDs are accepted as recommendations without one
+func parseRecommendations(content string) ([]Recommendation, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal([]byte(content), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse recommendations from response: %w (content: %s)", err, content)
+	}
+
+	recommendations := make([]Recommendation, 0, len(entries))
+	for _, entry := range entries {
+		var rec Recommendation
+		if err := json.Unmarshal(entry, &rec.TMDBID); err != nil {
+			if err := json.Unmarshal(entry, &rec); err != nil {
+				return nil, fmt.Errorf("failed to parse recommendation %s: %w", entry, err)
+			}
+		}
+
+		if rec.TMDBID <= 0 {
+			continue
+		}
+		rec.Reason = strings.TrimSpace(rec.Reason)
+		recommendations = append(recommendations, rec)
+	}
+
+	if len(recommendations) == 0 {
+		return nil, fmt.Errorf("no TMDB IDs returned")
+	}
+
+	return recommendations, nil
+}
+
+// complete sends prompt as a single user message and returns the trimmed reply
+func (c *Client) complete(prompt string) (string, error) {
 	// Prepare request
 	reqBody := ChatRequest{
-		Model: "gpt-4o-mini",
+		Model: c.Model,
 		Messages: []ChatMessage{
 			{
 				Role:    "user",
@@ -71,53 +231,49 @@ func (c *Client) GetRecommendations(likedMovies []string) ([]int, error) {
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequest("POST", c.BaseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 
 	// Execute request
+	c.Metrics.IncUpstreamCall(metrics.UpstreamOpenAI)
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return "", fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
 	var chatResp ChatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("no choices returned from API")
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Extract TMDB IDs from the response
-	content := chatResp.Choices[0].Message.Content
-	content = strings.TrimSpace(content)
+	// Usage is billed even when the reply turns out to be unusable
+	c.Metrics.AddOpenAITokens(chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens)
+	slog.Info("openai usage",
+		"model", c.Model,
+		"prompt_tokens", chatResp.Usage.PromptTokens,
+		"completion_tokens", chatResp.Usage.CompletionTokens,
+		"total_tokens", chatResp.Usage.TotalTokens,
+	)
 
-	// Parse JSON array of IDs
-	var tmdbIDs []int
-	if err := json.Unmarshal([]byte(content), &tmdbIDs); err != nil {
-		return nil, fmt.Errorf("failed to parse TMDB IDs from response: %w (content: %s)", err, content)
-	}
-
-	if len(tmdbIDs) == 0 {
-		return nil, fmt.Errorf("no TMDB IDs returned")
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned from API")
 	}
 
-	return tmdbIDs, nil
+	return strings.TrimSpace(chatResp.Choices[0].Message.Content), nil
 }
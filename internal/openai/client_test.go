@@ -0,0 +1,275 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+)
+
+func TestClient_GetRecommendationsUsesConfiguredModel(t *testing.T) {
+	var gotModel string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		gotModel = req.Model
+
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"[603, 27205]"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Run("sends the custom model", func(t *testing.T) {
+		client := NewClientWithModel("test-key", "gpt-4.1")
+		client.BaseURL = server.URL
+
+		ids, err := client.GetRecommendations([]string{"The Matrix"})
+		if err != nil {
+			t.Fatalf("GetRecommendations failed: %v", err)
+		}
+
+		if gotModel != "gpt-4.1" {
+			t.Errorf("Expected model 'gpt-4.1' in payload, got '%s'", gotModel)
+		}
+		if len(ids) != 2 {
+			t.Errorf("Expected 2 IDs, got %d", len(ids))
+		}
+	})
+
+	t.Run("falls back to the default model", func(t *testing.T) {
+		client := NewClientWithModel("test-key", "")
+		client.BaseURL = server.URL
+
+		if _, err := client.GetRecommendations([]string{"The Matrix"}); err != nil {
+			t.Fatalf("GetRecommendations failed: %v", err)
+		}
+
+		if gotModel != DefaultModel {
+			t.Errorf("Expected model '%s' in payload, got '%s'", DefaultModel, gotModel)
+		}
+	})
+}
+
+// countingTransport records requests before delegating to the default transport
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientWithHTTP_UsesInjectedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"[603]"}}]}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := NewClientWithHTTP("test-key", &http.Client{Transport: transport})
+	client.BaseURL = server.URL
+
+	if _, err := client.GetRecommendations([]string{"The Matrix"}); err != nil {
+		t.Fatalf("GetRecommendations failed: %v", err)
+	}
+
+	if transport.requests != 1 {
+		t.Errorf("Expected injected client to send 1 request, got %d", transport.requests)
+	}
+	if client.Model != DefaultModel {
+		t.Errorf("Expected default model '%s', got '%s'", DefaultModel, client.Model)
+	}
+}
+
+func TestClient_GetRecommendationsWithReasons(t *testing.T) {
+	var gotPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		gotPrompt = req.Messages[0].Content
+
+		content, _ := json.Marshal(`[{"tmdb_id": 603, "reason": "Reality-bending action like Inception."}, {"tmdb_id": 27205}]`)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	recs, err := client.GetRecommendationsWithReasons([]string{"Inception"})
+	if err != nil {
+		t.Fatalf("GetRecommendationsWithReasons failed: %v", err)
+	}
+
+	if !strings.Contains(gotPrompt, `"reason"`) {
+		t.Errorf("Expected the prompt to ask for reasons, got %q", gotPrompt)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("Expected 2 recommendations, got %d", len(recs))
+	}
+	if recs[0].TMDBID != 603 || recs[0].Reason != "Reality-bending action like Inception." {
+		t.Errorf("Unexpected first recommendation: %+v", recs[0])
+	}
+	if recs[1].TMDBID != 27205 || recs[1].Reason != "" {
+		t.Errorf("Expected the second recommendation without a reason, got %+v", recs[1])
+	}
+}
+
+func TestClient_GetRecommendationsExcluding(t *testing.T) {
+	var gotPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		gotPrompt = req.Messages[0].Content
+
+		// The model repeats an excluded ID despite being told not to
+		content, _ := json.Marshal(`[{"tmdb_id": 603, "reason": "Already seen."}, {"tmdb_id": 155, "reason": "A darker Nolan pick."}]`)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	recs, err := client.GetRecommendationsExcluding([]string{"Inception"}, []int{603, 27205}, 3)
+	if err != nil {
+		t.Fatalf("GetRecommendationsExcluding failed: %v", err)
+	}
+
+	if !strings.Contains(gotPrompt, "exclude these: [603, 27205]") {
+		t.Errorf("Expected the prompt to list the excluded IDs, got %q", gotPrompt)
+	}
+	if !strings.Contains(gotPrompt, "recommend 3 distinct movies") {
+		t.Errorf("Expected the prompt to ask for 3 movies, got %q", gotPrompt)
+	}
+	if len(recs) != 1 || recs[0].TMDBID != 155 {
+		t.Errorf("Expected only the fresh recommendation 155, got %+v", recs)
+	}
+}
+
+func TestClient_RecordsTokenUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"[603, 27205]"}}],
+			"usage":{"prompt_tokens":42,"completion_tokens":7,"total_tokens":49}
+		}`))
+	}))
+	defer server.Close()
+
+	registry := metrics.NewRegistry()
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+	client.Metrics = registry
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRecommendations([]string{"The Matrix"}); err != nil {
+			t.Fatalf("GetRecommendations failed: %v", err)
+		}
+	}
+
+	tokens := registry.Snapshot().OpenAITokens
+	if tokens[metrics.TokensPrompt] != 84 {
+		t.Errorf("Expected 84 prompt tokens, got %d", tokens[metrics.TokensPrompt])
+	}
+	if tokens[metrics.TokensCompletion] != 14 {
+		t.Errorf("Expected 14 completion tokens, got %d", tokens[metrics.TokensCompletion])
+	}
+}
+
+func TestParseRecommendations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Recommendation
+		wantErr bool
+	}{
+		{
+			name:    "objects with reasons",
+			content: `[{"tmdb_id": 603, "reason": " Same directors' energy. "}, {"tmdb_id": 155, "reason": "Dark and cerebral."}]`,
+			want:    []Recommendation{{TMDBID: 603, Reason: "Same directors' energy."}, {TMDBID: 155, Reason: "Dark and cerebral."}},
+		},
+		{
+			name:    "omitted reason",
+			content: `[{"tmdb_id": 603}]`,
+			want:    []Recommendation{{TMDBID: 603}},
+		},
+		{
+			name:    "bare IDs",
+			content: `[603, 155]`,
+			want:    []Recommendation{{TMDBID: 603}, {TMDBID: 155}},
+		},
+		{
+			name:    "skips entries without an ID",
+			content: `[{"reason": "No ID given"}, {"tmdb_id": 155}]`,
+			want:    []Recommendation{{TMDBID: 155}},
+		},
+		{
+			name:    "no usable IDs",
+			content: `[{"reason": "No ID given"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			content: `Here are some movies you might like`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecommendations(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRecommendations failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d recommendations, got %+v", len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Recommendation %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewClientWithTimeout_SlowServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Draining the body lets the server notice when the client gives up
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout("test-key", 20*time.Millisecond)
+	client.BaseURL = server.URL
+
+	_, err := client.GetRecommendations([]string{"The Matrix"})
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+}
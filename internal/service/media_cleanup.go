@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+)
+
+// DefaultMediaCleanupInterval is how often the worker purges stale media when no interval is configured
+const DefaultMediaCleanupInterval = 24 * time.Hour
+
+// MediaCleanupWorker periodically deletes cached media that was never voted on, so media_items stays bounded
+type MediaCleanupWorker struct {
+	mediaRepo *database.MediaRepository
+	metrics   *metrics.Registry
+	interval  time.Duration
+	maxAge    time.Duration
+	running   atomic.Bool
+}
+
+// NewMediaCleanupWorker creates a media cleanup worker
+// interval or maxAge below or equal to zero use DefaultMediaCleanupInterval and database.DefaultUnreferencedMediaMaxAge;
+// a nil registry skips last-run reporting
+func NewMediaCleanupWorker(m *database.MediaRepository, registry *metrics.Registry, interval, maxAge time.Duration) *MediaCleanupWorker {
+	if interval <= 0 {
+		interval = DefaultMediaCleanupInterval
+	}
+	if maxAge <= 0 {
+		maxAge = database.DefaultUnreferencedMediaMaxAge
+	}
+
+	return &MediaCleanupWorker{
+		mediaRepo: m,
+		metrics:   registry,
+		interval:  interval,
+		maxAge:    maxAge,
+	}
+}
+
+// Run purges once per interval until ctx is cancelled
+// Unlike pre-caching there is nothing to warm, so the first purge waits a full interval
+func (w *MediaCleanupWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce deletes unreferenced media last cached more than maxAge ago
+// It returns the number of rows deleted, or false when a previous run is still in progress
+func (w *MediaCleanupWorker) RunOnce(ctx context.Context) (int, bool) {
+	if !w.running.CompareAndSwap(false, true) {
+		log.Printf("Media cleanup run skipped: previous run still in progress")
+		return 0, false
+	}
+	defer w.running.Store(false)
+
+	deleted, err := w.mediaRepo.DeleteUnreferencedMedia(ctx, time.Now().Add(-w.maxAge))
+	if err != nil {
+		log.Printf("Warning: Media cleanup failed: %v", err)
+		return 0, true
+	}
+
+	if deleted > 0 {
+		log.Printf("Media cleanup deleted %d unreferenced media items", deleted)
+	}
+
+	w.metrics.ObserveJobRun(metrics.JobMediaCleanup, time.Now())
+	return deleted, true
+}
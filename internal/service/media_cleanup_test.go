@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+)
+
+func TestMediaCleanupWorker_RunOnce(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	registry := metrics.NewRegistry()
+	worker := NewMediaCleanupWorker(database.NewMediaRepository(testDB.DB), registry, 0, 0)
+
+	testDB.SeedMediaItem(t, 7101, "movie", "Fresh Movie")
+
+	deleted, ran := worker.RunOnce(context.Background())
+	if !ran {
+		t.Fatal("Expected the run to start")
+	}
+	if deleted != 0 {
+		t.Errorf("Expected freshly cached media to be kept, got %d deleted", deleted)
+	}
+
+	if _, ok := registry.Snapshot().JobLastRun[metrics.JobMediaCleanup]; !ok {
+		t.Error("Expected the media cleanup last run to be recorded")
+	}
+}
+
+func TestMediaCleanupWorker_SkipsOverlappingRun(t *testing.T) {
+	registry := metrics.NewRegistry()
+	worker := NewMediaCleanupWorker(nil, registry, 0, 0)
+
+	// Simulate a run that is still in progress
+	worker.running.Store(true)
+
+	if _, ran := worker.RunOnce(context.Background()); ran {
+		t.Error("Expected an overlapping run to be skipped")
+	}
+	if _, ok := registry.Snapshot().JobLastRun[metrics.JobMediaCleanup]; ok {
+		t.Error("Expected a skipped run not to be recorded")
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+// DefaultPrecacheInterval is how often the worker refreshes cached movies when no interval is configured
+const DefaultPrecacheInterval = 6 * time.Hour
+
+// PrecacheWorker periodically caches now-playing and trending movies so browsing starts warm
+type PrecacheWorker struct {
+	tmdbClient *tmdb.Client
+	mediaRepo  *database.MediaRepository
+	metrics    *metrics.Registry
+	interval   time.Duration
+	running    atomic.Bool
+}
+
+// NewPrecacheWorker creates a pre-cache worker
+// interval below or equal to zero uses DefaultPrecacheInterval; a nil registry skips last-run reporting
+func NewPrecacheWorker(t *tmdb.Client, m *database.MediaRepository, registry *metrics.Registry, interval time.Duration) *PrecacheWorker {
+	if interval <= 0 {
+		interval = DefaultPrecacheInterval
+	}
+
+	return &PrecacheWorker{
+		tmdbClient: t,
+		mediaRepo:  m,
+		metrics:    registry,
+		interval:   interval,
+	}
+}
+
+// Run caches immediately and then once per interval until ctx is cancelled
+func (w *PrecacheWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fetches now-playing and trending movies and caches each one
+// It returns the number of movies cached, or false when a previous run is still in progress.
+// The run is only recorded as the job's last run when every fetch and cache write succeeded
+func (w *PrecacheWorker) RunOnce(ctx context.Context) (int, bool) {
+	if !w.running.CompareAndSwap(false, true) {
+		log.Printf("Pre-cache run skipped: previous run still in progress")
+		return 0, false
+	}
+	defer w.running.Store(false)
+
+	lists := []struct {
+		name  string
+		fetch func(context.Context) (*tmdb.MovieResponse, error)
+	}{
+		{"now playing", w.tmdbClient.GetNowPlayingCtx},
+		{"trending", w.tmdbClient.GetTrending},
+	}
+
+	cached := 0
+	failed := false
+	for _, list := range lists {
+		if ctx.Err() != nil {
+			return cached, true
+		}
+
+		resp, err := list.fetch(ctx)
+		if err != nil {
+			log.Printf("Warning: Pre-cache failed to fetch %s movies: %v", list.name, err)
+			failed = true
+			continue
+		}
+
+		for _, movie := range resp.Results {
+			if _, err := w.mediaRepo.CacheMovie(ctx, movie); err != nil {
+				log.Printf("Warning: Pre-cache failed to cache movie %d: %v", movie.ID, err)
+				failed = true
+				continue
+			}
+			cached++
+		}
+	}
+
+	if !failed {
+		w.metrics.ObserveJobRun(metrics.JobPrecache, time.Now())
+	}
+	return cached, true
+}
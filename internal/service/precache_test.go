@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+func TestPrecacheWorker_RunOnce(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/movie/now_playing":
+			w.Write([]byte(`{"page":1,"results":[{"id":1001,"title":"Now Playing One"},{"id":1002,"title":"Now Playing Two"}]}`))
+		case "/trending/movie/week":
+			// Overlaps with now playing, which must not create a duplicate row
+			w.Write([]byte(`{"page":1,"results":[{"id":1002,"title":"Now Playing Two"},{"id":1003,"title":"Trending Three"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer tmdbServer.Close()
+
+	registry := metrics.NewRegistry()
+	mediaRepo := database.NewMediaRepository(testDB.DB)
+	worker := NewPrecacheWorker(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), mediaRepo, registry, 0)
+	ctx := context.Background()
+
+	cached, ran := worker.RunOnce(ctx)
+	if !ran {
+		t.Fatal("Expected the run to start")
+	}
+	if cached != 4 {
+		t.Errorf("Expected 4 cache writes, got %d", cached)
+	}
+
+	for _, tmdbID := range []int{1001, 1002, 1003} {
+		item, err := mediaRepo.GetMediaByTMDBID(ctx, tmdbID, tmdb.MediaTypeMovie)
+		if err != nil {
+			t.Fatalf("GetMediaByTMDBID failed: %v", err)
+		}
+		if item == nil {
+			t.Errorf("Expected tmdb_id %d to be cached", tmdbID)
+		}
+	}
+
+	var count int
+	if err := testDB.DB.QueryRow(`SELECT COUNT(*) FROM media_items`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count media items: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 media items, got %d", count)
+	}
+
+	if _, ok := registry.Snapshot().JobLastRun[metrics.JobPrecache]; !ok {
+		t.Error("Expected the pre-cache last run to be recorded")
+	}
+}
+
+func TestPrecacheWorker_SkipsOverlappingRun(t *testing.T) {
+	var calls atomic.Int32
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"page":1,"results":[]}`))
+	}))
+	defer tmdbServer.Close()
+
+	registry := metrics.NewRegistry()
+	worker := NewPrecacheWorker(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), nil, registry, 0)
+
+	// Simulate a run that is still in progress
+	worker.running.Store(true)
+
+	if _, ran := worker.RunOnce(context.Background()); ran {
+		t.Error("Expected an overlapping run to be skipped")
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Expected no TMDB calls, got %d", got)
+	}
+	if _, ok := registry.Snapshot().JobLastRun[metrics.JobPrecache]; ok {
+		t.Error("Expected a skipped run not to be recorded")
+	}
+}
+
+func TestPrecacheWorker_DoesNotRecordFailedRun(t *testing.T) {
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer tmdbServer.Close()
+
+	registry := metrics.NewRegistry()
+	worker := NewPrecacheWorker(tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)), nil, registry, 0)
+
+	cached, ran := worker.RunOnce(context.Background())
+	if !ran {
+		t.Fatal("Expected the run to start")
+	}
+	if cached != 0 {
+		t.Errorf("Expected no cache writes, got %d", cached)
+	}
+	if _, ok := registry.Snapshot().JobLastRun[metrics.JobPrecache]; ok {
+		t.Error("Expected a failed run not to be recorded")
+	}
+}
+
+func TestNewPrecacheWorker_DefaultInterval(t *testing.T) {
+	worker := NewPrecacheWorker(nil, nil, nil, 0)
+	if worker.interval != DefaultPrecacheInterval {
+		t.Errorf("Expected interval %s, got %s", DefaultPrecacheInterval, worker.interval)
+	}
+}
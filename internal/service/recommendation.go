@@ -2,53 +2,185 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 
+	"github.com/google/uuid"
 	"github.com/tahaburak/would-watch-backend/internal/database"
 	"github.com/tahaburak/would-watch-backend/internal/openai"
 	"github.com/tahaburak/would-watch-backend/internal/tmdb"
-	"github.com/google/uuid"
 )
 
+// likedTitlesPromptLimit bounds how many liked titles are sent to OpenAI
+const likedTitlesPromptLimit = 20
+
+// DefaultMinLikedMovies is how many liked movies a session needs before AI recommendations are generated
+const DefaultMinLikedMovies = 3
+
+// ErrNotEnoughLikes is returned when a session has fewer liked movies than the service requires
+var ErrNotEnoughLikes = errors.New("not enough liked movies")
+
+// Recommendation sources accepted by RecommendationOptions.Source
+const (
+	// SourceAI asks OpenAI for recommendations based on liked titles
+	SourceAI = "ai"
+	// SourceTMDB aggregates TMDB's similar movies for the session's matches
+	SourceTMDB = "tmdb"
+)
+
+const (
+	// similarSeedLimit bounds how many matches are looked up on TMDB for similar movies
+	similarSeedLimit = 5
+	// similarRecommendationLimit bounds how many TMDB-similar movies are returned
+	similarRecommendationLimit = 10
+)
+
+// RecommendationOptions tunes how the liked set sent to OpenAI is built
+type RecommendationOptions struct {
+	// Group favors titles liked by the most distinct voters over the most recent likes
+	Group bool
+	// Limit caps the liked titles in the prompt; zero uses likedTitlesPromptLimit
+	Limit int
+	// UserID, when set, excludes media this user has disliked in any session
+	UserID uuid.UUID
+	// Source picks SourceAI (the default when empty) or SourceTMDB
+	Source string
+}
+
+// Recommendation is a recommended media item and why it was picked, when the source explains it
+type Recommendation struct {
+	database.MediaItem
+	Reason string `json:"reason,omitempty"`
+}
+
 type RecommendationService struct {
 	openaiClient *openai.Client
 	tmdbClient   *tmdb.Client
 	voteRepo     *database.VoteRepository
 	mediaRepo    *database.MediaRepository
+	recRepo      *database.RecommendationRepository
+	minLikes     int
 }
 
-func NewRecommendationService(oid *openai.Client, t *tmdb.Client, v *database.VoteRepository, m *database.MediaRepository) *RecommendationService {
+// NewRecommendationService creates a recommendation service
+// AI recommendations are recorded in r so re-rolls can exclude them; a nil r keeps no history
+// minLikes below 1 uses DefaultMinLikedMovies
+func NewRecommendationService(oid *openai.Client, t *tmdb.Client, v *database.VoteRepository, m *database.MediaRepository, r *database.RecommendationRepository, minLikes int) *RecommendationService {
+	if minLikes < 1 {
+		minLikes = DefaultMinLikedMovies
+	}
+
 	return &RecommendationService{
 		openaiClient: oid,
 		tmdbClient:   t,
 		voteRepo:     v,
 		mediaRepo:    m,
+		recRepo:      r,
+		minLikes:     minLikes,
 	}
 }
 
+// MinLikedMovies returns how many liked movies a session needs before AI recommendations are generated
+func (s *RecommendationService) MinLikedMovies() int {
+	return s.minLikes
+}
+
 // GenerateRecommendations fetches liked movies, asks OpenAI, and caches results
-func (s *RecommendationService) GenerateRecommendations(ctx context.Context, sessionID uuid.UUID) ([]database.MediaItem, error) {
-	// 1. Get liked movies from this session
-	likedTitles, err := s.voteRepo.GetLikedMovies(ctx, sessionID)
+func (s *RecommendationService) GenerateRecommendations(ctx context.Context, sessionID uuid.UUID) ([]Recommendation, error) {
+	return s.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{})
+}
+
+// GenerateRecommendationsWithOptions is GenerateRecommendations with control over the liked set
+// AI recommendations return ErrNotEnoughLikes until the session has MinLikedMovies liked movies
+func (s *RecommendationService) GenerateRecommendationsWithOptions(ctx context.Context, sessionID uuid.UUID, opts RecommendationOptions) ([]Recommendation, error) {
+	if opts.Source == SourceTMDB {
+		return s.generateSimilarRecommendations(ctx, sessionID, opts)
+	}
+
+	return s.generateAIRecommendations(ctx, sessionID, opts, false)
+}
+
+// RerollRecommendations asks OpenAI for a fresh batch of recommendations, telling it which
+// movies were already recommended in the session so none of them come back
+// Like GenerateRecommendationsWithOptions it returns ErrNotEnoughLikes for sessions with too few likes
+func (s *RecommendationService) RerollRecommendations(ctx context.Context, sessionID uuid.UUID, opts RecommendationOptions) ([]Recommendation, error) {
+	return s.generateAIRecommendations(ctx, sessionID, opts, true)
+}
+
+// generateAIRecommendations asks OpenAI for recommendations from the session's liked movies and records them
+// When reroll is set, movies already recommended in the session are excluded from the prompt and the results
+func (s *RecommendationService) generateAIRecommendations(ctx context.Context, sessionID uuid.UUID, opts RecommendationOptions, reroll bool) ([]Recommendation, error) {
+	// Without an OpenAI key recommendations are disabled rather than failing every request
+	if s.openaiClient == nil || s.openaiClient.APIKey == "" {
+		return []Recommendation{}, nil
+	}
+
+	likedCount, err := s.voteRepo.CountLikedMovies(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count liked movies: %w", err)
+	}
+
+	if likedCount < s.minLikes {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughLikes, likedCount, s.minLikes)
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = likedTitlesPromptLimit
+	}
+
+	// 1. Get the liked movies from this session, group favorites or most recent first
+	var likedTitles []string
+	if opts.Group {
+		likedTitles, err = s.voteRepo.GetGroupLikedMovies(ctx, sessionID, limit)
+	} else {
+		likedTitles, err = s.voteRepo.GetLikedMovies(ctx, sessionID, limit, 0)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get liked movies: %w", err)
 	}
 
 	if len(likedTitles) == 0 {
-		return []database.MediaItem{}, nil
+		return []Recommendation{}, nil
 	}
 
-	// 2. Ask OpenAI for recommendations
-	recommendedTMDBIDs, err := s.openaiClient.GetRecommendations(likedTitles)
+	excluded, err := s.dislikedTMDBIDs(ctx, opts.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get openai recommendations: %w", err)
+		return nil, fmt.Errorf("failed to get disliked media: %w", err)
+	}
+
+	// 2. Ask OpenAI for recommendations and why it picked them, minus anything already suggested on a re-roll
+	var recommended []openai.Recommendation
+	if reroll && s.recRepo != nil {
+		previous, err := s.recRepo.GetRecommendedTMDBIDs(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get previous recommendations: %w", err)
+		}
+		for _, tmdbID := range previous {
+			excluded[tmdbID] = true
+		}
+		recommended, err = s.openaiClient.GetRecommendationsExcluding(likedTitles, previous, openai.DefaultRecommendationCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get openai recommendations: %w", err)
+		}
+	} else {
+		recommended, err = s.openaiClient.GetRecommendationsWithReasons(likedTitles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get openai recommendations: %w", err)
+		}
 	}
 
-	var recommendations []database.MediaItem
+	var recommendations []Recommendation
 
 	// 3. Fetch details for each recommended movie + Cache in DB
-	for _, tmdbID := range recommendedTMDBIDs {
+	for _, rec := range recommended {
+		tmdbID := rec.TMDBID
+		if excluded[tmdbID] {
+			continue
+		}
+
 		// Check if we already have it in DB?
 		existing, err := s.mediaRepo.GetMediaByTMDBID(ctx, tmdbID, "movie")
 		if err != nil {
@@ -56,12 +188,12 @@ func (s *RecommendationService) GenerateRecommendations(ctx context.Context, ses
 		}
 
 		if existing != nil {
-			recommendations = append(recommendations, *existing)
+			recommendations = append(recommendations, Recommendation{MediaItem: *existing, Reason: rec.Reason})
 			continue
 		}
 
 		// Not in DB, fetch from TMDB
-		tmdbMovie, err := s.tmdbClient.GetMovieByID(tmdbID)
+		tmdbMovie, err := s.tmdbClient.GetMovieByIDCtx(ctx, tmdbID)
 		if err != nil {
 			log.Printf("Warning: TMDB fetch failed for tmdb_id %d: %v", tmdbID, err)
 			continue
@@ -77,7 +209,114 @@ func (s *RecommendationService) GenerateRecommendations(ctx context.Context, ses
 		// Re-fetch from DB to get the UUID and consistent format
 		saved, err := s.mediaRepo.GetMediaByTMDBID(ctx, tmdbID, "movie")
 		if err == nil && saved != nil {
-			recommendations = append(recommendations, *saved)
+			recommendations = append(recommendations, Recommendation{MediaItem: *saved, Reason: rec.Reason})
+		}
+	}
+
+	s.recordRecommendations(ctx, sessionID, recommendations)
+
+	return recommendations, nil
+}
+
+// recordRecommendations adds what was just shown to the session's recommended set
+// The recommendations are still returned when this fails; the next re-roll may just repeat some
+func (s *RecommendationService) recordRecommendations(ctx context.Context, sessionID uuid.UUID, recommendations []Recommendation) {
+	if s.recRepo == nil || len(recommendations) == 0 {
+		return
+	}
+
+	tmdbIDs := make([]int, 0, len(recommendations))
+	for _, rec := range recommendations {
+		tmdbIDs = append(tmdbIDs, rec.TMDBID)
+	}
+
+	if err := s.recRepo.SaveRecommendations(ctx, sessionID, tmdbIDs); err != nil {
+		log.Printf("Warning: Failed to record recommendations for session %s: %v", sessionID, err)
+	}
+}
+
+// dislikedTMDBIDs returns the TMDB IDs of media userID voted "no" on, or nothing when userID is unset
+func (s *RecommendationService) dislikedTMDBIDs(ctx context.Context, userID uuid.UUID) (map[int]bool, error) {
+	excluded := map[int]bool{}
+	if userID == uuid.Nil {
+		return excluded, nil
+	}
+
+	disliked, err := s.voteRepo.GetUserDislikedMedia(ctx, userID, database.MaxDislikedMediaLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range disliked {
+		excluded[item.TMDBID] = true
+	}
+
+	return excluded, nil
+}
+
+// generateSimilarRecommendations recommends the movies TMDB lists as similar to the session's matches,
+// favoring those similar to several matches, without calling OpenAI
+func (s *RecommendationService) generateSimilarRecommendations(ctx context.Context, sessionID uuid.UUID, opts RecommendationOptions) ([]Recommendation, error) {
+	matches, err := s.voteRepo.GetMatchesForSession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matches: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return []Recommendation{}, nil
+	}
+
+	excluded, err := s.dislikedTMDBIDs(ctx, opts.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disliked media: %w", err)
+	}
+	for _, match := range matches {
+		excluded[match.TMDBID] = true
+	}
+
+	if len(matches) > similarSeedLimit {
+		matches = matches[:similarSeedLimit]
+	}
+
+	// Count how many matches each similar movie was suggested for, keeping first-seen order for ties
+	counts := map[int]int{}
+	movies := map[int]tmdb.Movie{}
+	var order []int
+	for _, match := range matches {
+		similar, err := s.tmdbClient.GetSimilarMovies(ctx, match.TMDBID)
+		if err != nil {
+			log.Printf("Warning: TMDB similar lookup failed for tmdb_id %d: %v", match.TMDBID, err)
+			continue
+		}
+
+		for _, movie := range similar.Results {
+			if excluded[movie.ID] {
+				continue
+			}
+			if _, seen := movies[movie.ID]; !seen {
+				movies[movie.ID] = movie
+				order = append(order, movie.ID)
+			}
+			counts[movie.ID]++
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if len(order) > similarRecommendationLimit {
+		order = order[:similarRecommendationLimit]
+	}
+
+	recommendations := []Recommendation{}
+	for _, tmdbID := range order {
+		if _, err := s.mediaRepo.CacheMovie(ctx, movies[tmdbID]); err != nil {
+			log.Printf("Warning: Failed to cache recommendation %d: %v", tmdbID, err)
+		}
+
+		saved, err := s.mediaRepo.GetMediaByTMDBID(ctx, tmdbID, "movie")
+		if err == nil && saved != nil {
+			recommendations = append(recommendations, Recommendation{MediaItem: *saved})
 		}
 	}
 
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tahaburak/would-watch-backend/internal/database"
+	"github.com/tahaburak/would-watch-backend/internal/openai"
+	"github.com/tahaburak/would-watch-backend/internal/testutils"
+	"github.com/tahaburak/would-watch-backend/internal/tmdb"
+)
+
+// promptRecorder is a fake OpenAI chat endpoint that records the last prompt it received
+type promptRecorder struct {
+	mu     sync.Mutex
+	prompt string
+	// reply is the model's answer; empty answers "[550]"
+	reply string
+}
+
+func (p *promptRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req openai.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	p.prompt = req.Messages[0].Content
+	reply := p.reply
+	p.mu.Unlock()
+
+	if reply == "" {
+		reply = "[550]"
+	}
+
+	json.NewEncoder(w).Encode(openai.ChatResponse{
+		Choices: []struct {
+			Message openai.ChatMessage `json:"message"`
+		}{
+			{Message: openai.ChatMessage{Role: "assistant", Content: reply}},
+		},
+	})
+}
+
+func (p *promptRecorder) setReply(reply string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reply = reply
+}
+
+func (p *promptRecorder) lastPrompt() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.prompt
+}
+
+func TestRecommendationService_GroupOption(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	recorder := &promptRecorder{}
+	openaiServer := httptest.NewServer(recorder)
+	defer openaiServer.Close()
+
+	openaiClient := openai.NewClient("test-key")
+	openaiClient.BaseURL = openaiServer.URL
+
+	svc := NewRecommendationService(
+		openaiClient,
+		tmdb.NewClient("test-key"),
+		database.NewVoteRepository(testDB.DB),
+		database.NewMediaRepository(testDB.DB),
+		nil,
+		DefaultMinLikedMovies,
+	)
+	ctx := context.Background()
+
+	// The recommended movie is already cached, so TMDB is never called
+	testDB.SeedMediaItem(t, 550, "movie", "Fight Club")
+
+	prolificID := uuid.New()
+	testDB.SeedProfile(t, prolificID, "prolific")
+	friend1ID := uuid.New()
+	testDB.SeedProfile(t, friend1ID, "friend1")
+	friend2ID := uuid.New()
+	testDB.SeedProfile(t, friend2ID, "friend2")
+
+	sessionID := testDB.SeedWatchSession(t, prolificID, "Group Night", false)
+
+	// Everyone liked the group favorite, but before the prolific voter's solo likes
+	groupFavoriteID := testDB.SeedMediaItem(t, 4001, "movie", "Group Favorite")
+	for _, userID := range []uuid.UUID{prolificID, friend1ID, friend2ID} {
+		testDB.SeedVote(t, sessionID, userID, groupFavoriteID, "yes")
+	}
+	if _, err := testDB.DB.Exec(
+		`UPDATE session_votes SET created_at = NOW() - INTERVAL '1 day' WHERE session_id = $1 AND media_id = $2`,
+		sessionID, groupFavoriteID,
+	); err != nil {
+		t.Fatalf("Failed to backdate votes: %v", err)
+	}
+
+	soloTitles := []string{"Solo Pick One", "Solo Pick Two", "Solo Pick Three"}
+	for i, title := range soloTitles {
+		mediaID := testDB.SeedMediaItem(t, 4100+i, "movie", title)
+		testDB.SeedVote(t, sessionID, prolificID, mediaID, "yes")
+	}
+
+	t.Run("group option puts group-favored titles first", func(t *testing.T) {
+		recs, err := svc.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{Group: true})
+		if err != nil {
+			t.Fatalf("GenerateRecommendationsWithOptions failed: %v", err)
+		}
+		if len(recs) != 1 {
+			t.Fatalf("Expected 1 recommendation, got %d", len(recs))
+		}
+
+		prompt := recorder.lastPrompt()
+		groupIdx := strings.Index(prompt, "Group Favorite")
+		if groupIdx == -1 {
+			t.Fatalf("Expected prompt to include the group favorite, got: %s", prompt)
+		}
+		for _, title := range soloTitles {
+			soloIdx := strings.Index(prompt, title)
+			if soloIdx == -1 {
+				t.Errorf("Expected prompt to include %s", title)
+				continue
+			}
+			if groupIdx > soloIdx {
+				t.Errorf("Expected the group favorite ahead of %s in prompt: %s", title, prompt)
+			}
+		}
+	})
+
+	t.Run("group option respects the limit", func(t *testing.T) {
+		if _, err := svc.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{Group: true, Limit: 1}); err != nil {
+			t.Fatalf("GenerateRecommendationsWithOptions failed: %v", err)
+		}
+
+		prompt := recorder.lastPrompt()
+		if !strings.Contains(prompt, "[Group Favorite]") {
+			t.Errorf("Expected only the group favorite in prompt, got: %s", prompt)
+		}
+	})
+
+	t.Run("default ordering favors recent likes", func(t *testing.T) {
+		if _, err := svc.GenerateRecommendations(ctx, sessionID); err != nil {
+			t.Fatalf("GenerateRecommendations failed: %v", err)
+		}
+
+		prompt := recorder.lastPrompt()
+		if strings.Index(prompt, "Group Favorite") < strings.Index(prompt, "Solo Pick One") {
+			t.Errorf("Expected recent solo likes ahead of the older group favorite, got: %s", prompt)
+		}
+	})
+	t.Run("excludes media the user disliked", func(t *testing.T) {
+		recommendedID := testDB.SeedMediaItem(t, 550, "movie", "Fight Club")
+		testDB.SeedVote(t, sessionID, friend1ID, recommendedID, "no")
+
+		recs, err := svc.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{UserID: friend1ID})
+		if err != nil {
+			t.Fatalf("GenerateRecommendationsWithOptions failed: %v", err)
+		}
+		if len(recs) != 0 {
+			t.Errorf("Expected the disliked recommendation to be excluded, got %d", len(recs))
+		}
+
+		recs, err = svc.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{UserID: friend2ID})
+		if err != nil {
+			t.Fatalf("GenerateRecommendationsWithOptions failed: %v", err)
+		}
+		if len(recs) != 1 {
+			t.Errorf("Expected another user to still get the recommendation, got %d", len(recs))
+		}
+	})
+}
+
+func TestRecommendationService_TMDBSource(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	var openaiCalls int
+	var mu sync.Mutex
+	openaiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		openaiCalls++
+		mu.Unlock()
+		http.Error(w, "unexpected OpenAI call", http.StatusInternalServerError)
+	}))
+	defer openaiServer.Close()
+
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/movie/550/similar":
+			w.Write([]byte(`{"page":1,"results":[{"id":807,"title":"Se7en"},{"id":680,"title":"Pulp Fiction"},{"id":13,"title":"Forrest Gump"}]}`))
+		case "/movie/680/similar":
+			// Suggests a match back, which must not be recommended again
+			w.Write([]byte(`{"page":1,"results":[{"id":550,"title":"Fight Club"},{"id":13,"title":"Forrest Gump"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer tmdbServer.Close()
+
+	openaiClient := openai.NewClient("test-key")
+	openaiClient.BaseURL = openaiServer.URL
+
+	svc := NewRecommendationService(
+		openaiClient,
+		tmdb.NewClient("test-key", tmdb.WithBaseURL(tmdbServer.URL)),
+		database.NewVoteRepository(testDB.DB),
+		database.NewMediaRepository(testDB.DB),
+		nil,
+		DefaultMinLikedMovies,
+	)
+	ctx := context.Background()
+
+	user1ID := uuid.New()
+	testDB.SeedProfile(t, user1ID, "user1")
+	user2ID := uuid.New()
+	testDB.SeedProfile(t, user2ID, "user2")
+
+	sessionID := testDB.SeedWatchSession(t, user1ID, "Similar Night", false)
+
+	// Two matches to seed the similar lookups
+	for _, seed := range []struct {
+		tmdbID int
+		title  string
+	}{{550, "Fight Club"}, {680, "Pulp Fiction"}} {
+		mediaID := testDB.SeedMediaItem(t, seed.tmdbID, "movie", seed.title)
+		testDB.SeedVote(t, sessionID, user1ID, mediaID, "yes")
+		testDB.SeedVote(t, sessionID, user2ID, mediaID, "yes")
+	}
+
+	recs, err := svc.GenerateRecommendationsWithOptions(ctx, sessionID, RecommendationOptions{Source: SourceTMDB})
+	if err != nil {
+		t.Fatalf("GenerateRecommendationsWithOptions failed: %v", err)
+	}
+
+	t.Run("never calls OpenAI", func(t *testing.T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if openaiCalls != 0 {
+			t.Errorf("Expected no OpenAI calls, got %d", openaiCalls)
+		}
+	})
+
+	t.Run("aggregates similar movies and skips matches", func(t *testing.T) {
+		if len(recs) != 2 {
+			t.Fatalf("Expected 2 recommendations, got %d", len(recs))
+		}
+
+		// Forrest Gump is similar to both matches, so it ranks first
+		if recs[0].TMDBID != 13 {
+			t.Errorf("Expected Forrest Gump first, got '%s'", recs[0].Title)
+		}
+		if recs[1].TMDBID != 807 {
+			t.Errorf("Expected Se7en second, got '%s'", recs[1].Title)
+		}
+	})
+}
+
+func TestRecommendationService_MinLikedMovies(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	recorder := &promptRecorder{}
+	openaiServer := httptest.NewServer(recorder)
+	defer openaiServer.Close()
+
+	openaiClient := openai.NewClient("test-key")
+	openaiClient.BaseURL = openaiServer.URL
+
+	svc := NewRecommendationService(
+		openaiClient,
+		tmdb.NewClient("test-key"),
+		database.NewVoteRepository(testDB.DB),
+		database.NewMediaRepository(testDB.DB),
+		nil,
+		3,
+	)
+	ctx := context.Background()
+
+	// The recommended movie is already cached, so TMDB is never called
+	testDB.SeedMediaItem(t, 550, "movie", "Fight Club")
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "picky")
+	sessionID := testDB.SeedWatchSession(t, userID, "Threshold Night", false)
+
+	// A "no" vote must not count toward the threshold
+	for i, title := range []string{"Liked One", "Liked Two"} {
+		mediaID := testDB.SeedMediaItem(t, 4200+i, "movie", title)
+		testDB.SeedVote(t, sessionID, userID, mediaID, "yes")
+	}
+	dislikedID := testDB.SeedMediaItem(t, 4210, "movie", "Disliked")
+	testDB.SeedVote(t, sessionID, userID, dislikedID, "no")
+
+	t.Run("refuses below the threshold", func(t *testing.T) {
+		recs, err := svc.GenerateRecommendations(ctx, sessionID)
+		if !errors.Is(err, ErrNotEnoughLikes) {
+			t.Fatalf("Expected ErrNotEnoughLikes, got %v", err)
+		}
+		if recs != nil {
+			t.Errorf("Expected no recommendations, got %d", len(recs))
+		}
+		if prompt := recorder.lastPrompt(); prompt != "" {
+			t.Errorf("Expected OpenAI not to be called, got prompt: %s", prompt)
+		}
+	})
+
+	t.Run("generates at the threshold", func(t *testing.T) {
+		mediaID := testDB.SeedMediaItem(t, 4202, "movie", "Liked Three")
+		testDB.SeedVote(t, sessionID, userID, mediaID, "yes")
+
+		recs, err := svc.GenerateRecommendations(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GenerateRecommendations failed: %v", err)
+		}
+		if len(recs) != 1 {
+			t.Errorf("Expected 1 recommendation, got %d", len(recs))
+		}
+		if svc.MinLikedMovies() != 3 {
+			t.Errorf("Expected MinLikedMovies 3, got %d", svc.MinLikedMovies())
+		}
+	})
+}
+
+func TestRecommendationService_Reroll(t *testing.T) {
+	testDB := testutils.NewTestDB(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+
+	recorder := &promptRecorder{}
+	openaiServer := httptest.NewServer(recorder)
+	defer openaiServer.Close()
+
+	openaiClient := openai.NewClient("test-key")
+	openaiClient.BaseURL = openaiServer.URL
+
+	recRepo := database.NewRecommendationRepository(testDB.DB)
+	svc := NewRecommendationService(
+		openaiClient,
+		tmdb.NewClient("test-key"),
+		database.NewVoteRepository(testDB.DB),
+		database.NewMediaRepository(testDB.DB),
+		recRepo,
+		DefaultMinLikedMovies,
+	)
+	ctx := context.Background()
+
+	// Both recommended movies are already cached, so TMDB is never called
+	testDB.SeedMediaItem(t, 550, "movie", "Fight Club")
+	testDB.SeedMediaItem(t, 603, "movie", "The Matrix")
+
+	userID := uuid.New()
+	testDB.SeedProfile(t, userID, "reroller")
+	sessionID := testDB.SeedWatchSession(t, userID, "Reroll Night", false)
+
+	for i, title := range []string{"Liked One", "Liked Two", "Liked Three"} {
+		mediaID := testDB.SeedMediaItem(t, 4300+i, "movie", title)
+		testDB.SeedVote(t, sessionID, userID, mediaID, "yes")
+	}
+
+	recs, err := svc.GenerateRecommendations(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("GenerateRecommendations failed: %v", err)
+	}
+	if len(recs) != 1 || recs[0].TMDBID != 550 {
+		t.Fatalf("Expected Fight Club to be recommended first, got %+v", recs)
+	}
+
+	t.Run("excludes earlier recommendations from the prompt and results", func(t *testing.T) {
+		// The model repeats the earlier pick alongside a new one
+		recorder.setReply("[550, 603]")
+
+		recs, err := svc.RerollRecommendations(ctx, sessionID, RecommendationOptions{})
+		if err != nil {
+			t.Fatalf("RerollRecommendations failed: %v", err)
+		}
+
+		if prompt := recorder.lastPrompt(); !strings.Contains(prompt, "exclude these: [550]") {
+			t.Errorf("Expected the prompt to exclude 550, got: %s", prompt)
+		}
+		if len(recs) != 1 || recs[0].TMDBID != 603 {
+			t.Errorf("Expected only The Matrix, got %+v", recs)
+		}
+	})
+
+	t.Run("records the union of every batch", func(t *testing.T) {
+		tmdbIDs, err := recRepo.GetRecommendedTMDBIDs(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("GetRecommendedTMDBIDs failed: %v", err)
+		}
+		if len(tmdbIDs) != 2 || tmdbIDs[0] != 550 || tmdbIDs[1] != 603 {
+			t.Errorf("Expected recorded IDs [550 603], got %v", tmdbIDs)
+		}
+	})
+}
+
+func TestNewRecommendationService_DefaultMinLikedMovies(t *testing.T) {
+	svc := NewRecommendationService(nil, nil, nil, nil, nil, 0)
+	if got := svc.MinLikedMovies(); got != DefaultMinLikedMovies {
+		t.Errorf("Expected default minimum %d, got %d", DefaultMinLikedMovies, got)
+	}
+}
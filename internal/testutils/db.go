@@ -49,7 +49,16 @@ func (tdb *TestDB) Cleanup(t *testing.T) {
 	t.Helper()
 
 	tables := []string{
+		"vote_events",
+		"session_views",
+		"session_matches",
+		"session_snapshots",
 		"session_votes",
+		"session_candidates",
+		"idempotency_keys",
+		"session_guest_tokens",
+		"guest_voters",
+		"session_recommendations",
 		"room_participants",
 		"watch_sessions",
 		"media_items",
@@ -174,7 +183,7 @@ func (tdb *TestDB) SeedFollow(t *testing.T, followerID, followingID uuid.UUID) {
 	query := `
 		INSERT INTO user_follows (follower_id, following_id, created_at)
 		VALUES ($1, $2, NOW())
-		ON CONFLICT (follower_id, following_id) DO NOTHING
+		ON CONFLICT (follower_id, following_id) DO UPDATE SET deleted_at = NULL
 	`
 
 	_, err := tdb.DB.Exec(query, followerID, followingID)
@@ -206,7 +215,7 @@ func (tdb *TestDB) SeedVote(t *testing.T, sessionID, userID, mediaID uuid.UUID,
 	query := `
 		INSERT INTO session_votes (session_id, user_id, media_id, vote, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		ON CONFLICT (session_id, user_id, media_id)
+		ON CONFLICT (session_id, user_id, media_id, season_number)
 		DO UPDATE SET vote = EXCLUDED.vote, updated_at = NOW()
 	`
 
@@ -0,0 +1,191 @@
+package tmdb
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// ttlCache holds a single lazily fetched value that is refetched once it is older than the TTL
+// Fetches run outside the lock and are shared by concurrent callers, and a failed fetch is
+// remembered for failureTTL so a flapping upstream is not retried on every call
+type ttlCache[T any] struct {
+	mu        sync.Mutex
+	value     T
+	fetchedAt time.Time
+	loaded    bool
+	failedAt  time.Time
+	failure   error
+	inflight  *ttlFetch[T]
+}
+
+// ttlFetch is a fetch in progress; done is closed once value and err are set
+type ttlFetch[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// get returns the cached value, fetching it first if it is missing or expired
+// While a recent fetch has failed, the stale value is served if there is one, else that failure
+func (tc *ttlCache[T]) get(ctx context.Context, ttl, failureTTL time.Duration, now time.Time, fetch func(context.Context) (T, error)) (T, error) {
+	tc.mu.Lock()
+	if tc.loaded && now.Sub(tc.fetchedAt) < ttl {
+		value := tc.value
+		tc.mu.Unlock()
+		return value, nil
+	}
+	if tc.failure != nil && now.Sub(tc.failedAt) < failureTTL {
+		value, loaded, failure := tc.value, tc.loaded, tc.failure
+		tc.mu.Unlock()
+		if loaded {
+			return value, nil
+		}
+		var zero T
+		return zero, failure
+	}
+	tc.mu.Unlock()
+
+	return tc.fetch(ctx, now, fetch)
+}
+
+// refresh fetches and stores a new value regardless of the age of the cached one
+func (tc *ttlCache[T]) refresh(ctx context.Context, now time.Time, fetch func(context.Context) (T, error)) (T, error) {
+	return tc.fetch(ctx, now, fetch)
+}
+
+// fetch joins the fetch in progress or starts one, storing its result
+// A failed fetch keeps the previous value; a fetch cut short by its caller's context is not remembered as a failure
+func (tc *ttlCache[T]) fetch(ctx context.Context, now time.Time, fetch func(context.Context) (T, error)) (T, error) {
+	tc.mu.Lock()
+	call := tc.inflight
+	if call == nil {
+		call = &ttlFetch[T]{done: make(chan struct{})}
+		tc.inflight = call
+		tc.mu.Unlock()
+
+		call.value, call.err = fetch(ctx)
+
+		tc.mu.Lock()
+		tc.inflight = nil
+		switch {
+		case call.err == nil:
+			tc.value = call.value
+			tc.fetchedAt = now
+			tc.loaded = true
+			tc.failure = nil
+		case ctx.Err() == nil:
+			tc.failure = call.err
+			tc.failedAt = now
+		}
+		tc.mu.Unlock()
+		close(call.done)
+
+		return call.value, call.err
+	}
+	tc.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// lruCache maps keys to values for up to ttl, evicting the least recently used entry once it holds maxEntries
+// A nil cache stores nothing, so callers can disable caching by leaving it unset
+type lruCache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]*list.Element
+	// order holds *lruEntry values, most recently used at the front
+	order *list.List
+}
+
+type lruEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	storedAt time.Time
+}
+
+// newLRUCache creates an LRU cache, or returns nil when ttl or maxEntries disables it
+func newLRUCache[K comparable, V any](ttl time.Duration, maxEntries int) *lruCache[K, V] {
+	if ttl <= 0 || maxEntries <= 0 {
+		return nil
+	}
+
+	return &lruCache[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// get returns the value stored under key unless it is missing or older than the TTL
+func (c *lruCache[K, V]) get(key K, now time.Time) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := elem.Value.(*lruEntry[K, V])
+	if now.Sub(entry.storedAt) >= c.ttl {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// put stores value under key, evicting the least recently used entry if the cache is full
+func (c *lruCache[K, V]) put(key K, value V, now time.Time) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.storedAt = now
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, storedAt: now})
+
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// len returns how many entries the cache holds, including expired ones not yet evicted
+func (c *lruCache[K, V]) len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
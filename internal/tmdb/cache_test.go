@@ -0,0 +1,410 @@
+package tmdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newGenreServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genre/movie/list":
+			atomic.AddInt32(hits, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"genres":[{"id":28,"name":"Action"},{"id":35,"name":"Comedy"}]}`))
+		case "/configuration":
+			atomic.AddInt32(hits, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"images":{"secure_base_url":"https://image.tmdb.org/t/p/","poster_sizes":["w500","original"]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetGenres_CachesUntilExpiry(t *testing.T) {
+	var hits int32
+	server := newGenreServer(t, &hits)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+	client.CacheTTL = time.Hour
+	client.now = func() time.Time { return now }
+
+	genres, err := client.GetGenres(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(genres) != 2 {
+		t.Fatalf("expected 2 genres, got %d", len(genres))
+	}
+
+	if genres[0].Name != "Action" {
+		t.Errorf("expected first genre 'Action', got %s", genres[0].Name)
+	}
+
+	// Within the TTL the cached list is served
+	now = now.Add(30 * time.Minute)
+	if _, err := client.GetGenres(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected 1 request within TTL, got %d", got)
+	}
+
+	// After the TTL the list is refetched
+	now = now.Add(time.Hour)
+	if _, err := client.GetGenres(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected 2 requests after expiry, got %d", got)
+	}
+}
+
+func TestGetConfiguration_CachesUntilExpiry(t *testing.T) {
+	var hits int32
+	server := newGenreServer(t, &hits)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+	client.CacheTTL = time.Hour
+	client.now = func() time.Time { return now }
+
+	config, err := client.GetConfiguration(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.Images.SecureBaseURL != "https://image.tmdb.org/t/p/" {
+		t.Errorf("unexpected secure base URL %s", config.Images.SecureBaseURL)
+	}
+
+	client.GetConfiguration(context.Background())
+	now = now.Add(2 * time.Hour)
+	client.GetConfiguration(context.Background())
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestRefreshCaches_ForcesRefetch(t *testing.T) {
+	var hits int32
+	server := newGenreServer(t, &hits)
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	if _, err := client.GetGenres(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetConfiguration(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.RefreshCaches(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two initial fetches plus one refetch of each cache
+	if got := atomic.LoadInt32(&hits); got != 4 {
+		t.Errorf("expected 4 requests, got %d", got)
+	}
+}
+
+func TestGetGenres_KeepsCachedValueOnFailedRefresh(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"genres":[{"id":28,"name":"Action"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	if _, err := client.GetGenres(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fail = true
+	if err := client.RefreshCaches(context.Background()); err == nil {
+		t.Fatal("expected error when refresh fails")
+	}
+
+	client.CacheTTL = time.Hour
+	genres, err := client.GetGenres(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(genres) != 1 {
+		t.Errorf("expected cached genres to survive a failed refresh, got %d", len(genres))
+	}
+}
+
+func TestGetConfiguration_SharesConcurrentFetches(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		w.Write([]byte(`{"images":{"secure_base_url":"https://img.example/t/p/"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	var wg sync.WaitGroup
+	results := make(chan string, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- client.ImageBaseURL(context.Background())
+		}()
+	}
+
+	// Let every caller reach the cache before the single upstream request completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for got := range results {
+		if got != "https://img.example/t/p/" {
+			t.Errorf("expected the configuration's secure base, got %q", got)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected concurrent callers to share 1 request, got %d", got)
+	}
+}
+
+func TestGetConfiguration_RemembersFailures(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	client.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if got := client.ImageBaseURL(context.Background()); got != DefaultImageBaseURL {
+			t.Errorf("expected %q, got %q", DefaultImageBaseURL, got)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected a failed fetch to be remembered, got %d requests", got)
+	}
+
+	// Once the failure is old enough TMDB is asked again
+	now = now.Add(cacheFailureTTL)
+	if _, err := client.GetConfiguration(context.Background()); err == nil {
+		t.Fatal("expected error from failing upstream")
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestGetConfiguration_DoesNotRememberCancellation(t *testing.T) {
+	var hits int32
+	server := newGenreServer(t, &hits)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetConfiguration(ctx); err == nil {
+		t.Fatal("expected error for a cancelled context")
+	}
+
+	if _, err := client.GetConfiguration(context.Background()); err != nil {
+		t.Fatalf("expected a cancelled fetch not to be cached, got %v", err)
+	}
+}
+
+func newSearchServer(t *testing.T, hits *int32, status *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if code := atomic.LoadInt32(status); code != 0 {
+			w.WriteHeader(int(code))
+			return
+		}
+		w.Write([]byte(`{"page":1,"results":[{"id":268,"title":"Batman"},{"id":272,"title":"Batman Begins"}],"total_pages":1,"total_results":2}`))
+	}))
+}
+
+func TestSearchMovie_CachesUntilExpiry(t *testing.T) {
+	var hits, status int32
+	server := newSearchServer(t, &hits, &status)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithSearchCache(time.Hour, 10))
+	client.now = func() time.Time { return now }
+
+	first, err := client.SearchMovie("batman")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Case and spacing differences share the cached result
+	second, err := client.SearchMovie("  Batman ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected 1 request for identical searches within TTL, got %d", got)
+	}
+	if len(second.Results) != 2 || second.Results[0].Title != "Batman" {
+		t.Errorf("expected the cached results, got %+v", second.Results)
+	}
+
+	// Callers reordering their copy must not disturb the cache
+	first.Results[0], first.Results[1] = first.Results[1], first.Results[0]
+	third, err := client.SearchMovie("batman")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if third.Results[0].Title != "Batman" {
+		t.Errorf("expected the cached order to be unchanged, got %+v", third.Results)
+	}
+
+	// A different page or year is a different search
+	if _, err := client.SearchMovieWithOptions("batman", SearchOptions{Page: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.SearchMovieWithOptions("batman", SearchOptions{Year: 1989}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("expected page and year to miss the cache, got %d requests", got)
+	}
+
+	// After the TTL the search is refetched
+	now = now.Add(time.Hour)
+	if _, err := client.SearchMovie("batman"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 4 {
+		t.Errorf("expected a refetch after expiry, got %d requests", got)
+	}
+}
+
+func TestSearchMovie_DoesNotCacheFailures(t *testing.T) {
+	var hits int32
+	status := int32(http.StatusInternalServerError)
+	server := newSearchServer(t, &hits, &status)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.SearchMovie("batman"); err == nil {
+		t.Fatal("expected an error from a failing server")
+	}
+
+	atomic.StoreInt32(&status, 0)
+	if _, err := client.SearchMovie("batman"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected the failed search to be retried, got %d requests", got)
+	}
+}
+
+func TestSearchMovie_CacheCanBeDisabled(t *testing.T) {
+	var hits, status int32
+	server := newSearchServer(t, &hits, &status)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithSearchCache(time.Hour, 0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchMovie("batman"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("expected every search to reach TMDB, got %d requests", got)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newLRUCache[string, int](time.Hour, 2)
+
+	cache.put("a", 1, now)
+	cache.put("b", 2, now)
+
+	// Reading "a" makes "b" the least recently used
+	if _, ok := cache.get("a", now); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	cache.put("c", 3, now)
+
+	if _, ok := cache.get("b", now); ok {
+		t.Error("expected b to be evicted")
+	}
+	if v, ok := cache.get("a", now); !ok || v != 1 {
+		t.Errorf("expected a=1 to survive, got %d, %v", v, ok)
+	}
+	if v, ok := cache.get("c", now); !ok || v != 3 {
+		t.Errorf("expected c=3 to be cached, got %d, %v", v, ok)
+	}
+	if got := cache.len(); got != 2 {
+		t.Errorf("expected 2 entries, got %d", got)
+	}
+}
+
+func TestLRUCache_ConcurrentUse(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newLRUCache[int, int](time.Hour, 16)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.put(i%32, worker, now)
+				cache.get((i+worker)%32, now)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if got := cache.len(); got > 16 {
+		t.Errorf("expected at most 16 entries, got %d", got)
+	}
+}
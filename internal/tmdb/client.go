@@ -1,18 +1,102 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
+)
+
+// DefaultBaseURL is the public TMDB v3 API endpoint
+const DefaultBaseURL = "https://api.themoviedb.org/3"
+
+// DefaultImageBaseURL is TMDB's secure image host, used when the configuration endpoint is unavailable
+const DefaultImageBaseURL = "https://image.tmdb.org/t/p/"
+
+// DefaultImageSize is the poster and backdrop width used when a client does not pick one
+const DefaultImageSize = "w500"
+
+// DefaultTimeout bounds each TMDB request when no timeout is configured
+const DefaultTimeout = 10 * time.Second
+
+// DefaultWatchRegion is the ISO 3166-1 region used for watch providers when none is given
+const DefaultWatchRegion = "US"
+
+// MaxCastMembers caps how many top-billed cast members MovieDetails carries
+const MaxCastMembers = 10
+
+// ErrMovieNotFound is returned when TMDB has no movie with the requested ID
+var ErrMovieNotFound = errors.New("movie not found")
+
+// ErrImageNotFound is returned when the image CDN has no file at the requested path
+var ErrImageNotFound = errors.New("image not found")
+
+// TMDBError is returned when TMDB answers with an unexpected status
+// Message carries TMDB's status_message when the body included one
+type TMDBError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is TMDB's Retry-After header, sent with 429 responses
+	RetryAfter string
+}
+
+func (e *TMDBError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// newTMDBError builds a TMDBError from a failed response, reading status_message from its body
+func newTMDBError(resp *http.Response) *TMDBError {
+	var body struct {
+		StatusMessage string `json:"status_message"`
+	}
+	// A body that isn't TMDB's error JSON still leaves the status code to go on
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	return &TMDBError{
+		StatusCode: resp.StatusCode,
+		Message:    body.StatusMessage,
+		RetryAfter: resp.Header.Get("Retry-After"),
+	}
+}
+
+// DefaultCacheTTL is how long genre and configuration data is served from memory
+const DefaultCacheTTL = 24 * time.Hour
+
+// cacheFailureTTL is how long a failed genre or configuration fetch is remembered before TMDB is asked again
+const cacheFailureTTL = 30 * time.Second
+
+const (
+	// DefaultSearchCacheTTL is how long a movie search result is served from memory
+	DefaultSearchCacheTTL = time.Hour
+	// DefaultSearchCacheSize caps how many distinct searches are cached
+	DefaultSearchCacheSize = 500
 )
 
 // Client represents a TMDB API client
 type Client struct {
-	BaseURL string
-	APIKey  string
-	client  *http.Client
+	BaseURL  string
+	APIKey   string
+	CacheTTL time.Duration
+	client   *http.Client
+
+	genres        ttlCache[[]Genre]
+	configuration ttlCache[*Configuration]
+	searches      *lruCache[searchCacheKey, MovieResponse]
+	imageBaseURL  string
+	metrics       *metrics.Registry
+	now           func() time.Time
 }
 
 // Movie represents a movie from TMDB API
@@ -41,45 +125,339 @@ type MovieResponse struct {
 	TotalResults int     `json:"total_results"`
 }
 
+// clone copies the response and its result list, so callers can reorder results without touching a cached copy
+func (m MovieResponse) clone() *MovieResponse {
+	results := make([]Movie, len(m.Results))
+	copy(results, m.Results)
+	m.Results = results
+	return &m
+}
+
+// Media types TMDB's multi search tags each result with
+const (
+	MediaTypeMovie  = "movie"
+	MediaTypeTV     = "tv"
+	MediaTypePerson = "person"
+)
+
+// TVShow represents a TV show from TMDB API
+type TVShow struct {
+	ID               int      `json:"id"`
+	Name             string   `json:"name"`
+	OriginalName     string   `json:"original_name"`
+	Overview         string   `json:"overview"`
+	PosterPath       string   `json:"poster_path"`
+	BackdropPath     string   `json:"backdrop_path"`
+	FirstAirDate     string   `json:"first_air_date"`
+	VoteAverage      float64  `json:"vote_average"`
+	VoteCount        int      `json:"vote_count"`
+	Popularity       float64  `json:"popularity"`
+	Adult            bool     `json:"adult"`
+	OriginalLanguage string   `json:"original_language"`
+	OriginCountry    []string `json:"origin_country"`
+	GenreIDs         []int    `json:"genre_ids"`
+}
+
+// MultiResult is a movie or TV show from a multi search, told apart by MediaType
+// Movies fill Title and ReleaseDate; TV shows fill Name and FirstAirDate
+type MultiResult struct {
+	ID               int      `json:"id"`
+	MediaType        string   `json:"media_type"`
+	Title            string   `json:"title"`
+	OriginalTitle    string   `json:"original_title"`
+	Name             string   `json:"name"`
+	OriginalName     string   `json:"original_name"`
+	Overview         string   `json:"overview"`
+	PosterPath       string   `json:"poster_path"`
+	BackdropPath     string   `json:"backdrop_path"`
+	ReleaseDate      string   `json:"release_date"`
+	FirstAirDate     string   `json:"first_air_date"`
+	VoteAverage      float64  `json:"vote_average"`
+	VoteCount        int      `json:"vote_count"`
+	Popularity       float64  `json:"popularity"`
+	Adult            bool     `json:"adult"`
+	Video            bool     `json:"video"`
+	OriginalLanguage string   `json:"original_language"`
+	OriginCountry    []string `json:"origin_country"`
+	GenreIDs         []int    `json:"genre_ids"`
+}
+
+// MultiResponse represents the response structure from TMDB's multi search endpoint
+type MultiResponse struct {
+	Page         int           `json:"page"`
+	Results      []MultiResult `json:"results"`
+	TotalPages   int           `json:"total_pages"`
+	TotalResults int           `json:"total_results"`
+}
+
+// Movie returns the result as a Movie; only meaningful when MediaType is MediaTypeMovie
+func (m MultiResult) Movie() Movie {
+	return Movie{
+		ID:               m.ID,
+		Title:            m.Title,
+		OriginalTitle:    m.OriginalTitle,
+		Overview:         m.Overview,
+		PosterPath:       m.PosterPath,
+		BackdropPath:     m.BackdropPath,
+		ReleaseDate:      m.ReleaseDate,
+		VoteAverage:      m.VoteAverage,
+		VoteCount:        m.VoteCount,
+		Popularity:       m.Popularity,
+		Adult:            m.Adult,
+		Video:            m.Video,
+		OriginalLanguage: m.OriginalLanguage,
+		GenreIDs:         m.GenreIDs,
+	}
+}
+
+// TVShow returns the result as a TVShow; only meaningful when MediaType is MediaTypeTV
+func (m MultiResult) TVShow() TVShow {
+	return TVShow{
+		ID:               m.ID,
+		Name:             m.Name,
+		OriginalName:     m.OriginalName,
+		Overview:         m.Overview,
+		PosterPath:       m.PosterPath,
+		BackdropPath:     m.BackdropPath,
+		FirstAirDate:     m.FirstAirDate,
+		VoteAverage:      m.VoteAverage,
+		VoteCount:        m.VoteCount,
+		Popularity:       m.Popularity,
+		Adult:            m.Adult,
+		OriginalLanguage: m.OriginalLanguage,
+		OriginCountry:    m.OriginCountry,
+		GenreIDs:         m.GenreIDs,
+	}
+}
+
+// Genre represents a TMDB movie genre
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CastMember represents an actor in a movie's credits
+type CastMember struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	ProfilePath string `json:"profile_path"`
+	Order       int    `json:"order"`
+}
+
+// Credits represents the credits appended to a movie details response
+type Credits struct {
+	Cast []CastMember `json:"cast"`
+}
+
+// MovieDetails represents a single movie with its runtime, tagline, genres, and top cast
+type MovieDetails struct {
+	Movie
+	Runtime int     `json:"runtime"`
+	Tagline string  `json:"tagline"`
+	Genres  []Genre `json:"genres"`
+	Credits Credits `json:"credits"`
+}
+
+// Provider represents a streaming, rental, or purchase service offering a movie
+type Provider struct {
+	ProviderID      int    `json:"provider_id"`
+	ProviderName    string `json:"provider_name"`
+	LogoPath        string `json:"logo_path"`
+	DisplayPriority int    `json:"display_priority"`
+}
+
+// WatchProviders lists where a movie can be streamed, rented, or bought in one region
+type WatchProviders struct {
+	Region   string     `json:"region"`
+	Link     string     `json:"link"`
+	Flatrate []Provider `json:"flatrate"`
+	Rent     []Provider `json:"rent"`
+	Buy      []Provider `json:"buy"`
+}
+
+// watchProvidersResponse represents the watch providers endpoint, keyed by region
+type watchProvidersResponse struct {
+	ID      int                       `json:"id"`
+	Results map[string]WatchProviders `json:"results"`
+}
+
+// GenreResponse represents the response from the genre list endpoint
+type GenreResponse struct {
+	Genres []Genre `json:"genres"`
+}
+
+// ImageConfiguration holds the image base URLs and sizes TMDB supports
+type ImageConfiguration struct {
+	BaseURL       string   `json:"base_url"`
+	SecureBaseURL string   `json:"secure_base_url"`
+	BackdropSizes []string `json:"backdrop_sizes"`
+	LogoSizes     []string `json:"logo_sizes"`
+	PosterSizes   []string `json:"poster_sizes"`
+	ProfileSizes  []string `json:"profile_sizes"`
+	StillSizes    []string `json:"still_sizes"`
+}
+
+// Configuration represents the TMDB API configuration
+type Configuration struct {
+	Images     ImageConfiguration `json:"images"`
+	ChangeKeys []string           `json:"change_keys"`
+}
+
+// SearchOptions narrows a movie search; zero values leave a filter off
+type SearchOptions struct {
+	// Year limits results to movies first released in that year
+	Year int
+	// Page selects a page of results; zero requests the first page
+	Page int
+	// IncludeAdult lets adult titles into the results; callers must only set it on a user's explicit opt-in
+	IncludeAdult bool
+}
+
+// searchCacheKey identifies a movie search; queries differing only in case or spacing share an entry
+type searchCacheKey struct {
+	query        string
+	year         int
+	page         int
+	includeAdult bool
+}
+
+func newSearchCacheKey(query string, opts SearchOptions) searchCacheKey {
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+
+	return searchCacheKey{
+		query:        strings.ToLower(strings.Join(strings.Fields(query), " ")),
+		year:         opts.Year,
+		page:         page,
+		includeAdult: opts.IncludeAdult,
+	}
+}
+
+// Option configures a Client at construction
+type Option func(*Client)
+
+// WithBaseURL points the client at a different TMDB endpoint, such as a proxy or a staging mock
+// An empty value keeps DefaultBaseURL
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/"); baseURL != "" {
+			c.BaseURL = baseURL
+		}
+	}
+}
+
+// WithImageBaseURL pins the base that poster and backdrop paths are joined to
+// An empty value uses the base reported by TMDB's configuration endpoint
+func WithImageBaseURL(imageBaseURL string) Option {
+	return func(c *Client) {
+		c.imageBaseURL = strings.TrimSpace(imageBaseURL)
+	}
+}
+
+// WithSearchCache sets how long movie searches are cached and how many are kept
+// A ttl or maxEntries of zero disables the search cache
+func WithSearchCache(ttl time.Duration, maxEntries int) Option {
+	return func(c *Client) {
+		c.searches = newLRUCache[searchCacheKey, MovieResponse](ttl, maxEntries)
+	}
+}
+
+// WithMetrics counts every TMDB API call in registry
+func WithMetrics(registry *metrics.Registry) Option {
+	return func(c *Client) {
+		c.metrics = registry
+	}
+}
+
 // NewClient creates a new TMDB client with a configured HTTP client
-func NewClient(apiKey string) *Client {
-	return &Client{
-		BaseURL: "https://api.themoviedb.org/3",
-		APIKey:  apiKey,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+func NewClient(apiKey string, opts ...Option) *Client {
+	return NewClientWithTimeout(apiKey, DefaultTimeout, opts...)
+}
+
+// NewClientWithTimeout creates a new TMDB client whose requests are abandoned after timeout
+func NewClientWithTimeout(apiKey string, timeout time.Duration, opts ...Option) *Client {
+	return NewClientWithHTTP(apiKey, &http.Client{
+		Timeout: timeout,
+	}, opts...)
+}
+
+// NewClientWithHTTP creates a new TMDB client that sends requests through hc
+// Use it to share one pooled http.Client across API clients
+func NewClientWithHTTP(apiKey string, hc *http.Client, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:  DefaultBaseURL,
+		APIKey:   apiKey,
+		CacheTTL: DefaultCacheTTL,
+		client:   hc,
+		searches: newLRUCache[searchCacheKey, MovieResponse](DefaultSearchCacheTTL, DefaultSearchCacheSize),
+		now:      time.Now,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // SearchMovie searches for movies by query string
 func (c *Client) SearchMovie(query string) (*MovieResponse, error) {
+	return c.SearchMovieCtx(context.Background(), query)
+}
+
+// SearchMovieCtx searches for movies by query string, aborting when ctx is cancelled
+func (c *Client) SearchMovieCtx(ctx context.Context, query string) (*MovieResponse, error) {
+	return c.SearchMovieWithOptionsCtx(ctx, query, SearchOptions{})
+}
+
+// SearchMovieWithOptions searches for movies by query string, narrowed by opts
+func (c *Client) SearchMovieWithOptions(query string, opts SearchOptions) (*MovieResponse, error) {
+	return c.SearchMovieWithOptionsCtx(context.Background(), query, opts)
+}
+
+// SearchMovieWithOptionsCtx searches for movies by query string, narrowed by opts, aborting when ctx is cancelled
+// Successful results are cached, so repeating a search within the cache TTL does not call TMDB
+func (c *Client) SearchMovieWithOptionsCtx(ctx context.Context, query string, opts SearchOptions) (*MovieResponse, error) {
 	if query == "" {
 		return nil, fmt.Errorf("query cannot be empty")
 	}
 
+	key := newSearchCacheKey(query, opts)
+	if cached, ok := c.searches.get(key, c.currentTime()); ok {
+		return cached.clone(), nil
+	}
+
 	endpoint := fmt.Sprintf("%s/search/movie", c.BaseURL)
 
 	params := url.Values{}
 	params.Add("api_key", c.APIKey)
 	params.Add("query", query)
-	params.Add("include_adult", "false")
+	params.Add("include_adult", strconv.FormatBool(opts.IncludeAdult))
+	if opts.Year != 0 {
+		params.Add("primary_release_year", strconv.Itoa(opts.Year))
+	}
+	if opts.Page > 1 {
+		params.Add("page", strconv.Itoa(opts.Page))
+	}
 
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newTMDBError(resp)
 	}
 
 	var movieResp MovieResponse
@@ -87,11 +465,66 @@ func (c *Client) SearchMovie(query string) (*MovieResponse, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &movieResp, nil
+	c.searches.put(key, movieResp, c.currentTime())
+
+	return movieResp.clone(), nil
+}
+
+// SearchMulti searches movies and TV shows together, aborting when ctx is cancelled
+// People are dropped, so every result is either a movie or a TV show
+// includeAdult lets adult titles into the results; callers must only set it on a user's explicit opt-in
+func (c *Client) SearchMulti(ctx context.Context, query string, includeAdult bool) (*MultiResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	endpoint := fmt.Sprintf("%s/search/multi", c.BaseURL)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+	params.Add("query", query)
+	params.Add("include_adult", strconv.FormatBool(includeAdult))
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var multiResp MultiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&multiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := make([]MultiResult, 0, len(multiResp.Results))
+	for _, result := range multiResp.Results {
+		if result.MediaType == MediaTypeMovie || result.MediaType == MediaTypeTV {
+			results = append(results, result)
+		}
+	}
+	multiResp.Results = results
+
+	return &multiResp, nil
 }
 
 // GetNowPlaying retrieves currently playing movies in theaters
 func (c *Client) GetNowPlaying() (*MovieResponse, error) {
+	return c.GetNowPlayingCtx(context.Background())
+}
+
+// GetNowPlayingCtx retrieves currently playing movies in theaters, aborting when ctx is cancelled
+func (c *Client) GetNowPlayingCtx(ctx context.Context) (*MovieResponse, error) {
 	endpoint := fmt.Sprintf("%s/movie/now_playing", c.BaseURL)
 
 	params := url.Values{}
@@ -99,19 +532,92 @@ func (c *Client) GetNowPlaying() (*MovieResponse, error) {
 
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var movieResp MovieResponse
+	if err := json.NewDecoder(resp.Body).Decode(&movieResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &movieResp, nil
+}
+
+// GetTrending retrieves the movies trending on TMDB this week
+func (c *Client) GetTrending(ctx context.Context) (*MovieResponse, error) {
+	endpoint := fmt.Sprintf("%s/trending/movie/week", c.BaseURL)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var movieResp MovieResponse
+	if err := json.NewDecoder(resp.Body).Decode(&movieResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &movieResp, nil
+}
+
+// DiscoverByGenre retrieves a page of movies in a genre, most popular first
+// page below 1 requests the first page; includeAdult follows the same opt-in rule as SearchOptions
+func (c *Client) DiscoverByGenre(ctx context.Context, genreID int, page int, includeAdult bool) (*MovieResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	endpoint := fmt.Sprintf("%s/discover/movie", c.BaseURL)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+	params.Add("with_genres", strconv.Itoa(genreID))
+	params.Add("sort_by", "popularity.desc")
+	params.Add("page", strconv.Itoa(page))
+	params.Add("include_adult", strconv.FormatBool(includeAdult))
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newTMDBError(resp)
 	}
 
 	var movieResp MovieResponse
@@ -124,6 +630,11 @@ func (c *Client) GetNowPlaying() (*MovieResponse, error) {
 
 // GetMovieByID retrieves movie details by TMDB ID
 func (c *Client) GetMovieByID(tmdbID int) (*Movie, error) {
+	return c.GetMovieByIDCtx(context.Background(), tmdbID)
+}
+
+// GetMovieByIDCtx retrieves movie details by TMDB ID, aborting when ctx is cancelled
+func (c *Client) GetMovieByIDCtx(ctx context.Context, tmdbID int) (*Movie, error) {
 	endpoint := fmt.Sprintf("%s/movie/%d", c.BaseURL, tmdbID)
 
 	params := url.Values{}
@@ -131,23 +642,23 @@ func (c *Client) GetMovieByID(tmdbID int) (*Movie, error) {
 
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("movie not found")
+		return nil, ErrMovieNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newTMDBError(resp)
 	}
 
 	var movie Movie
@@ -157,3 +668,308 @@ func (c *Client) GetMovieByID(tmdbID int) (*Movie, error) {
 
 	return &movie, nil
 }
+
+// GetMovieDetails retrieves a movie with its credits appended, keeping the top MaxCastMembers cast
+func (c *Client) GetMovieDetails(ctx context.Context, tmdbID int) (*MovieDetails, error) {
+	endpoint := fmt.Sprintf("%s/movie/%d", c.BaseURL, tmdbID)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+	params.Add("append_to_response", "credits")
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMovieNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var details MovieDetails
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// The details endpoint returns genre objects rather than genre_ids
+	if len(details.GenreIDs) == 0 {
+		for _, genre := range details.Genres {
+			details.GenreIDs = append(details.GenreIDs, genre.ID)
+		}
+	}
+
+	sort.SliceStable(details.Credits.Cast, func(i, j int) bool {
+		return details.Credits.Cast[i].Order < details.Credits.Cast[j].Order
+	})
+	if len(details.Credits.Cast) > MaxCastMembers {
+		details.Credits.Cast = details.Credits.Cast[:MaxCastMembers]
+	}
+
+	return &details, nil
+}
+
+// GetSimilarMovies retrieves the first page of movies TMDB considers similar to tmdbID
+func (c *Client) GetSimilarMovies(ctx context.Context, tmdbID int) (*MovieResponse, error) {
+	endpoint := fmt.Sprintf("%s/movie/%d/similar", c.BaseURL, tmdbID)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMovieNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var movieResp MovieResponse
+	if err := json.NewDecoder(resp.Body).Decode(&movieResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &movieResp, nil
+}
+
+// GetWatchProviders retrieves where a movie can be watched in region, defaulting to DefaultWatchRegion
+// A region TMDB has no providers for yields an empty provider set rather than an error
+func (c *Client) GetWatchProviders(ctx context.Context, tmdbID int, region string) (*WatchProviders, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		region = DefaultWatchRegion
+	}
+
+	endpoint := fmt.Sprintf("%s/movie/%d/watch/providers", c.BaseURL, tmdbID)
+
+	params := url.Values{}
+	params.Add("api_key", c.APIKey)
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMovieNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newTMDBError(resp)
+	}
+
+	var providersResp watchProvidersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&providersResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	providers := providersResp.Results[region]
+	providers.Region = region
+	if providers.Flatrate == nil {
+		providers.Flatrate = []Provider{}
+	}
+	if providers.Rent == nil {
+		providers.Rent = []Provider{}
+	}
+	if providers.Buy == nil {
+		providers.Buy = []Provider{}
+	}
+
+	return &providers, nil
+}
+
+// GetGenres retrieves the movie genre list, served from cache until CacheTTL elapses
+func (c *Client) GetGenres(ctx context.Context) ([]Genre, error) {
+	return c.genres.get(ctx, c.CacheTTL, cacheFailureTTL, c.currentTime(), c.fetchGenres)
+}
+
+// GetConfiguration retrieves the API configuration, served from cache until CacheTTL elapses
+func (c *Client) GetConfiguration(ctx context.Context) (*Configuration, error) {
+	return c.configuration.get(ctx, c.CacheTTL, cacheFailureTTL, c.currentTime(), c.fetchConfiguration)
+}
+
+// ImageBaseURL returns the base for building image URLs: the configured one, else TMDB's
+// secure base from the cached configuration, else DefaultImageBaseURL
+func (c *Client) ImageBaseURL(ctx context.Context) string {
+	if c.imageBaseURL != "" {
+		return c.imageBaseURL
+	}
+
+	config, err := c.GetConfiguration(ctx)
+	if err == nil && config != nil && config.Images.SecureBaseURL != "" {
+		return config.Images.SecureBaseURL
+	}
+
+	return DefaultImageBaseURL
+}
+
+// ImageURL joins an image path such as "/poster.jpg" to baseURL at the given size, e.g. "w500"
+// An empty path or base yields "" so clients never receive a malformed URL
+func ImageURL(baseURL, size, path string) string {
+	path = strings.TrimLeft(strings.TrimSpace(path), "/")
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if path == "" || baseURL == "" {
+		return ""
+	}
+	if size == "" {
+		size = DefaultImageSize
+	}
+
+	return baseURL + "/" + size + "/" + path
+}
+
+// ImageSizes lists the poster and backdrop widths TMDB's image CDN serves
+var ImageSizes = []string{"w92", "w154", "w185", "w300", "w342", "w500", "w780", "w1280", "original"}
+
+// imagePathPattern matches TMDB image paths, which are a single opaque file name such as "/kqjL17yufvn9OVLyXYpvtyrFfak.jpg"
+var imagePathPattern = regexp.MustCompile(`^/[A-Za-z0-9_-]+\.(jpg|png)$`)
+
+// IsImageSize reports whether size is one of ImageSizes
+func IsImageSize(size string) bool {
+	for _, supported := range ImageSizes {
+		if size == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImagePath reports whether path looks like a TMDB image path, so it cannot escape the CDN's size directory
+func IsImagePath(path string) bool {
+	return imagePathPattern.MatchString(path)
+}
+
+// GetImage fetches an image from the TMDB image CDN at the given size
+// The caller must close the response body; a missing image returns ErrImageNotFound
+func (c *Client) GetImage(ctx context.Context, size, path string) (*http.Response, error) {
+	if !IsImageSize(size) || !IsImagePath(path) {
+		return nil, fmt.Errorf("invalid image size %q or path %q", size, path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", ImageURL(c.ImageBaseURL(ctx), size, path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrImageNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newTMDBError(resp)
+	}
+
+	return resp, nil
+}
+
+// RefreshCaches refetches the genre list and configuration regardless of their age
+func (c *Client) RefreshCaches(ctx context.Context) error {
+	if _, err := c.genres.refresh(ctx, c.currentTime(), c.fetchGenres); err != nil {
+		return fmt.Errorf("failed to refresh genres: %w", err)
+	}
+
+	if _, err := c.configuration.refresh(ctx, c.currentTime(), c.fetchConfiguration); err != nil {
+		return fmt.Errorf("failed to refresh configuration: %w", err)
+	}
+
+	return nil
+}
+
+// currentTime returns the client's clock, which tests may override
+func (c *Client) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+func (c *Client) fetchGenres(ctx context.Context) ([]Genre, error) {
+	var genreResp GenreResponse
+	if err := c.getJSON(ctx, "/genre/movie/list", url.Values{}, &genreResp); err != nil {
+		return nil, err
+	}
+
+	return genreResp.Genres, nil
+}
+
+func (c *Client) fetchConfiguration(ctx context.Context) (*Configuration, error) {
+	var config Configuration
+	if err := c.getJSON(ctx, "/configuration", url.Values{}, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// do sends req to TMDB, counting the call when metrics are enabled
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	c.metrics.IncUpstreamCall(metrics.UpstreamTMDB)
+	return c.client.Do(req)
+}
+
+// getJSON performs an authenticated GET against a TMDB endpoint and decodes the JSON body into out
+func (c *Client) getJSON(ctx context.Context, path string, params url.Values, out interface{}) error {
+	params.Set("api_key", c.APIKey)
+
+	fullURL := fmt.Sprintf("%s%s?%s", c.BaseURL, path, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newTMDBError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -1,9 +1,18 @@
 package tmdb
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
+
+	"github.com/tahaburak/would-watch-backend/internal/metrics"
 )
 
 func TestNewClient(t *testing.T) {
@@ -157,7 +166,42 @@ func TestSearchMovie_APIError(t *testing.T) {
 
 	_, err := client.SearchMovie("test")
 	if err == nil {
-		t.Error("expected error for API failure, got nil")
+		t.Fatal("expected error for API failure, got nil")
+	}
+
+	var tmdbErr *TMDBError
+	if !errors.As(err, &tmdbErr) {
+		t.Fatalf("expected a *TMDBError, got %T", err)
+	}
+	if tmdbErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", tmdbErr.StatusCode)
+	}
+	if tmdbErr.Message != "Invalid API key" {
+		t.Errorf("expected status_message 'Invalid API key', got %q", tmdbErr.Message)
+	}
+}
+
+func TestTMDBError_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("rate limited"))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := client.GetGenres(context.Background())
+
+	var tmdbErr *TMDBError
+	if !errors.As(err, &tmdbErr) {
+		t.Fatalf("expected a *TMDBError, got %v", err)
+	}
+	if tmdbErr.StatusCode != http.StatusTooManyRequests || tmdbErr.RetryAfter != "7" {
+		t.Errorf("expected 429 with Retry-After 7, got %+v", tmdbErr)
+	}
+	if tmdbErr.Message != "" {
+		t.Errorf("expected no message for a non-JSON body, got %q", tmdbErr.Message)
 	}
 }
 
@@ -176,3 +220,651 @@ func TestGetNowPlaying_APIError(t *testing.T) {
 		t.Error("expected error for API failure, got nil")
 	}
 }
+
+// countingTransport records requests before delegating to the default transport
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientWithHTTP_UsesInjectedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":0,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := NewClientWithHTTP("test-key", &http.Client{Transport: transport})
+	client.BaseURL = server.URL
+
+	if _, err := client.SearchMovie("Inception"); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+
+	if transport.requests != 1 {
+		t.Errorf("expected injected client to send 1 request, got %d", transport.requests)
+	}
+}
+
+func TestSearchMovieCtx_CancelledContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the response until the test finishes so only cancellation can end the request
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := client.SearchMovieCtx(ctx, "Inception")
+	if err == nil {
+		t.Fatal("expected error for cancelled context, got nil")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected request to abort promptly, took %s", elapsed)
+	}
+}
+
+func TestGetMovieByIDCtx_AlreadyCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":550,"title":"Fight Club"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.BaseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetMovieByIDCtx(ctx, 550); err == nil {
+		t.Error("expected error for cancelled context, got nil")
+	}
+
+	if requests != 0 {
+		t.Errorf("expected no request to reach the server, got %d", requests)
+	}
+}
+
+func TestNewClient_WithBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/movie" {
+			t.Errorf("expected path /search/movie, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":0,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL+"/"))
+
+	if client.BaseURL != server.URL {
+		t.Errorf("expected BaseURL %s, got %s", server.URL, client.BaseURL)
+	}
+
+	if _, err := client.SearchMovie("Inception"); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+
+	if got := NewClient("test-key", WithBaseURL("")).BaseURL; got != DefaultBaseURL {
+		t.Errorf("expected empty base URL to keep %s, got %s", DefaultBaseURL, got)
+	}
+}
+
+func TestGetMovieDetails_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/27205" {
+			t.Errorf("expected path /movie/27205, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("append_to_response"); got != "credits" {
+			t.Errorf("expected append_to_response=credits, got %s", got)
+		}
+
+		cast := make([]map[string]interface{}, 0, MaxCastMembers+2)
+		for i := MaxCastMembers + 1; i >= 0; i-- {
+			cast = append(cast, map[string]interface{}{
+				"id":        1000 + i,
+				"name":      fmt.Sprintf("Actor %d", i),
+				"character": fmt.Sprintf("Role %d", i),
+				"order":     i,
+			})
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      27205,
+			"title":   "Inception",
+			"runtime": 148,
+			"tagline": "Your mind is the scene of the crime.",
+			"genres": []map[string]interface{}{
+				{"id": 28, "name": "Action"},
+				{"id": 878, "name": "Science Fiction"},
+			},
+			"credits": map[string]interface{}{"cast": cast},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	details, err := client.GetMovieDetails(context.Background(), 27205)
+	if err != nil {
+		t.Fatalf("GetMovieDetails failed: %v", err)
+	}
+
+	if details.ID != 27205 || details.Title != "Inception" {
+		t.Errorf("expected Inception (27205), got %s (%d)", details.Title, details.ID)
+	}
+
+	if details.Runtime != 148 {
+		t.Errorf("expected runtime 148, got %d", details.Runtime)
+	}
+
+	if details.Tagline != "Your mind is the scene of the crime." {
+		t.Errorf("unexpected tagline %q", details.Tagline)
+	}
+
+	if len(details.Genres) != 2 || details.Genres[1].Name != "Science Fiction" {
+		t.Errorf("expected 2 genres ending with Science Fiction, got %+v", details.Genres)
+	}
+
+	if len(details.GenreIDs) != 2 || details.GenreIDs[0] != 28 {
+		t.Errorf("expected genre IDs derived from genres, got %v", details.GenreIDs)
+	}
+
+	if len(details.Credits.Cast) != MaxCastMembers {
+		t.Fatalf("expected %d cast members, got %d", MaxCastMembers, len(details.Credits.Cast))
+	}
+
+	for i, member := range details.Credits.Cast {
+		if member.Order != i {
+			t.Errorf("expected cast member %d to have billing order %d, got %d", i, i, member.Order)
+		}
+	}
+}
+
+func TestGetMovieDetails_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status_code":34,"status_message":"The resource you requested could not be found."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.GetMovieDetails(context.Background(), 1); !errors.Is(err, ErrMovieNotFound) {
+		t.Errorf("expected ErrMovieNotFound, got %v", err)
+	}
+}
+
+const watchProvidersPayload = `{
+	"id": 550,
+	"results": {
+		"US": {
+			"link": "https://www.themoviedb.org/movie/550-fight-club/watch?locale=US",
+			"flatrate": [{"provider_id": 8, "provider_name": "Netflix", "logo_path": "/netflix.jpg", "display_priority": 1}],
+			"rent": [{"provider_id": 2, "provider_name": "Apple TV", "logo_path": "/apple.jpg", "display_priority": 4}],
+			"buy": [
+				{"provider_id": 2, "provider_name": "Apple TV", "logo_path": "/apple.jpg", "display_priority": 4},
+				{"provider_id": 3, "provider_name": "Google Play Movies", "logo_path": "/play.jpg", "display_priority": 16}
+			]
+		},
+		"GB": {
+			"link": "https://www.themoviedb.org/movie/550-fight-club/watch?locale=GB",
+			"rent": [{"provider_id": 10, "provider_name": "Amazon Video", "logo_path": "/amazon.jpg", "display_priority": 8}]
+		}
+	}
+}`
+
+func TestGetWatchProviders_RegionWithProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/550/watch/providers" {
+			t.Errorf("expected path /movie/550/watch/providers, got %s", r.URL.Path)
+		}
+		w.Write([]byte(watchProvidersPayload))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	providers, err := client.GetWatchProviders(context.Background(), 550, "")
+	if err != nil {
+		t.Fatalf("GetWatchProviders failed: %v", err)
+	}
+
+	if providers.Region != DefaultWatchRegion {
+		t.Errorf("expected default region %s, got %s", DefaultWatchRegion, providers.Region)
+	}
+
+	if len(providers.Flatrate) != 1 || providers.Flatrate[0].ProviderName != "Netflix" {
+		t.Errorf("expected Netflix flatrate provider, got %+v", providers.Flatrate)
+	}
+
+	if len(providers.Rent) != 1 || len(providers.Buy) != 2 {
+		t.Errorf("expected 1 rent and 2 buy providers, got %d and %d", len(providers.Rent), len(providers.Buy))
+	}
+
+	gb, err := client.GetWatchProviders(context.Background(), 550, "gb")
+	if err != nil {
+		t.Fatalf("GetWatchProviders failed: %v", err)
+	}
+
+	if gb.Region != "GB" || len(gb.Rent) != 1 || gb.Rent[0].ProviderName != "Amazon Video" {
+		t.Errorf("expected GB rent provider Amazon Video, got %+v", gb)
+	}
+
+	if gb.Flatrate == nil || len(gb.Flatrate) != 0 {
+		t.Errorf("expected empty flatrate list for GB, got %v", gb.Flatrate)
+	}
+}
+
+func TestGetWatchProviders_RegionWithoutProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(watchProvidersPayload))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	providers, err := client.GetWatchProviders(context.Background(), 550, "JP")
+	if err != nil {
+		t.Fatalf("expected no error for a region without providers, got %v", err)
+	}
+
+	if providers.Region != "JP" {
+		t.Errorf("expected region JP, got %s", providers.Region)
+	}
+
+	if len(providers.Flatrate) != 0 || len(providers.Rent) != 0 || len(providers.Buy) != 0 {
+		t.Errorf("expected an empty provider set, got %+v", providers)
+	}
+
+	if providers.Flatrate == nil || providers.Rent == nil || providers.Buy == nil {
+		t.Error("expected provider lists to be empty slices, not nil")
+	}
+}
+
+func TestGetSimilarMovies_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/550/similar" {
+			t.Errorf("expected path /movie/550/similar, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"page":1,"results":[{"id":807,"title":"Se7en"},{"id":1422,"title":"The Departed"}],"total_pages":1,"total_results":2}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	similar, err := client.GetSimilarMovies(context.Background(), 550)
+	if err != nil {
+		t.Fatalf("GetSimilarMovies failed: %v", err)
+	}
+
+	if len(similar.Results) != 2 || similar.Results[0].Title != "Se7en" {
+		t.Errorf("expected Se7en and The Departed, got %+v", similar.Results)
+	}
+}
+
+func TestGetTrending_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/trending/movie/week" {
+			t.Errorf("expected path /trending/movie/week, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"page":1,"results":[{"id":693134,"title":"Dune: Part Two"}],"total_pages":1,"total_results":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	trending, err := client.GetTrending(context.Background())
+	if err != nil {
+		t.Fatalf("GetTrending failed: %v", err)
+	}
+
+	if len(trending.Results) != 1 || trending.Results[0].Title != "Dune: Part Two" {
+		t.Errorf("expected Dune: Part Two, got %+v", trending.Results)
+	}
+}
+
+func TestDiscoverByGenre(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/discover/movie" {
+			t.Errorf("expected path /discover/movie, got %s", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"page":2,"results":[{"id":245891,"title":"John Wick","genre_ids":[28]}],"total_pages":5,"total_results":100}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	t.Run("filters by genre sorted by popularity", func(t *testing.T) {
+		resp, err := client.DiscoverByGenre(context.Background(), 28, 2, false)
+		if err != nil {
+			t.Fatalf("DiscoverByGenre failed: %v", err)
+		}
+
+		if got := query.Get("with_genres"); got != "28" {
+			t.Errorf("expected with_genres=28, got %q", got)
+		}
+		if got := query.Get("sort_by"); got != "popularity.desc" {
+			t.Errorf("expected sort_by=popularity.desc, got %q", got)
+		}
+		if got := query.Get("page"); got != "2" {
+			t.Errorf("expected page=2, got %q", got)
+		}
+		if resp.Page != 2 || len(resp.Results) != 1 || resp.Results[0].Title != "John Wick" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("defaults to the first page", func(t *testing.T) {
+		if _, err := client.DiscoverByGenre(context.Background(), 28, 0, false); err != nil {
+			t.Fatalf("DiscoverByGenre failed: %v", err)
+		}
+
+		if got := query.Get("page"); got != "1" {
+			t.Errorf("expected page=1, got %q", got)
+		}
+	})
+
+	t.Run("passes the adult content preference", func(t *testing.T) {
+		if _, err := client.DiscoverByGenre(context.Background(), 28, 1, false); err != nil {
+			t.Fatalf("DiscoverByGenre failed: %v", err)
+		}
+		if got := query.Get("include_adult"); got != "false" {
+			t.Errorf("expected include_adult=false by default, got %q", got)
+		}
+
+		if _, err := client.DiscoverByGenre(context.Background(), 28, 1, true); err != nil {
+			t.Fatalf("DiscoverByGenre failed: %v", err)
+		}
+		if got := query.Get("include_adult"); got != "true" {
+			t.Errorf("expected include_adult=true when opted in, got %q", got)
+		}
+	})
+}
+
+func TestDiscoverByGenre_UpstreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status_message":"Invalid API key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := client.DiscoverByGenre(context.Background(), 28, 1, false)
+
+	var tmdbErr *TMDBError
+	if !errors.As(err, &tmdbErr) || tmdbErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a 401 TMDBError, got %v", err)
+	}
+}
+
+func TestSearchMovieWithOptions_ForwardsYear(t *testing.T) {
+	var gotYear string
+	var hasYear bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotYear = r.URL.Query().Get("primary_release_year")
+		_, hasYear = r.URL.Query()["primary_release_year"]
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":1,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.SearchMovieWithOptions("Alien", SearchOptions{Year: 1979}); err != nil {
+		t.Fatalf("SearchMovieWithOptions failed: %v", err)
+	}
+	if gotYear != "1979" {
+		t.Errorf("expected primary_release_year 1979, got %q", gotYear)
+	}
+
+	if _, err := client.SearchMovie("Alien"); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+	if hasYear {
+		t.Error("expected no primary_release_year without a year option")
+	}
+}
+
+func TestSearchMovieWithOptions_IncludeAdult(t *testing.T) {
+	var gotAdult []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAdult = append(gotAdult, r.URL.Query().Get("include_adult"))
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":1,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.SearchMovie("Alien"); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+	if _, err := client.SearchMovieWithOptions("Alien", SearchOptions{IncludeAdult: true}); err != nil {
+		t.Fatalf("SearchMovieWithOptions failed: %v", err)
+	}
+
+	// The opted-in search must not be answered from the default search's cache entry
+	if len(gotAdult) != 2 {
+		t.Fatalf("expected 2 upstream requests, got %d", len(gotAdult))
+	}
+	if gotAdult[0] != "false" {
+		t.Errorf("expected include_adult=false by default, got %q", gotAdult[0])
+	}
+	if gotAdult[1] != "true" {
+		t.Errorf("expected include_adult=true when opted in, got %q", gotAdult[1])
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		size    string
+		path    string
+		want    string
+	}{
+		{"joins base, size and path", "https://image.tmdb.org/t/p/", "w500", "/poster.jpg", "https://image.tmdb.org/t/p/w500/poster.jpg"},
+		{"adds a missing separator", "https://image.tmdb.org/t/p", "original", "poster.jpg", "https://image.tmdb.org/t/p/original/poster.jpg"},
+		{"defaults the size", "https://image.tmdb.org/t/p/", "", "/poster.jpg", "https://image.tmdb.org/t/p/" + DefaultImageSize + "/poster.jpg"},
+		{"empty path yields no URL", "https://image.tmdb.org/t/p/", "w500", "", ""},
+		{"empty base yields no URL", "", "w500", "/poster.jpg", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ImageURL(tt.baseURL, tt.size, tt.path)
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			if got == "" {
+				return
+			}
+			if parsed, err := url.Parse(got); err != nil || parsed.Scheme != "https" || parsed.Host != "image.tmdb.org" {
+				t.Errorf("expected a valid image URL, got %q (%v)", got, err)
+			}
+		})
+	}
+}
+
+func TestImageBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/configuration" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"images":{"base_url":"http://img.example/t/p/","secure_base_url":"https://img.example/t/p/"}}`))
+	}))
+	defer server.Close()
+
+	t.Run("uses the configured override", func(t *testing.T) {
+		client := NewClient("test-key", WithBaseURL(server.URL), WithImageBaseURL("https://cdn.example/images/"))
+		if got := client.ImageBaseURL(context.Background()); got != "https://cdn.example/images/" {
+			t.Errorf("expected the override, got %q", got)
+		}
+	})
+
+	t.Run("uses TMDB's secure base", func(t *testing.T) {
+		client := NewClient("test-key", WithBaseURL(server.URL))
+		if got := client.ImageBaseURL(context.Background()); got != "https://img.example/t/p/" {
+			t.Errorf("expected the configuration's secure base, got %q", got)
+		}
+	})
+
+	t.Run("falls back to the default when configuration fails", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		client := NewClient("test-key", WithBaseURL(failing.URL))
+		if got := client.ImageBaseURL(context.Background()); got != DefaultImageBaseURL {
+			t.Errorf("expected %q, got %q", DefaultImageBaseURL, got)
+		}
+	})
+}
+
+func TestSearchMulti_MixedMedia(t *testing.T) {
+	mockResponse := `{
+		"page": 1,
+		"results": [
+			{"id": 603, "media_type": "movie", "title": "The Matrix", "release_date": "1999-03-30", "vote_average": 8.2, "genre_ids": [28, 878]},
+			{"id": 6384, "media_type": "person", "name": "Keanu Reeves", "known_for_department": "Acting"},
+			{"id": 1396, "media_type": "tv", "name": "Breaking Bad", "original_name": "Breaking Bad", "first_air_date": "2008-01-20", "origin_country": ["US"], "genre_ids": [18]}
+		],
+		"total_pages": 1,
+		"total_results": 3
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/multi" {
+			t.Errorf("expected path /search/multi, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("query") != "matrix" {
+			t.Errorf("expected query matrix, got %q", r.URL.Query().Get("query"))
+		}
+		w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	resp, err := client.SearchMulti(context.Background(), "matrix", false)
+	if err != nil {
+		t.Fatalf("SearchMulti failed: %v", err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("expected 2 results with the person dropped, got %+v", resp.Results)
+	}
+
+	movie := resp.Results[0]
+	if movie.MediaType != MediaTypeMovie || movie.Movie().Title != "The Matrix" || movie.Movie().ReleaseDate != "1999-03-30" {
+		t.Errorf("expected The Matrix as a movie, got %+v", movie)
+	}
+
+	show := resp.Results[1]
+	if show.MediaType != MediaTypeTV || show.TVShow().Name != "Breaking Bad" || show.TVShow().FirstAirDate != "2008-01-20" {
+		t.Errorf("expected Breaking Bad as a TV show, got %+v", show)
+	}
+	if len(show.OriginCountry) != 1 || show.OriginCountry[0] != "US" {
+		t.Errorf("expected origin country US, got %v", show.OriginCountry)
+	}
+
+	if resp.TotalResults != 3 {
+		t.Errorf("expected TMDB's total of 3 to be passed through, got %d", resp.TotalResults)
+	}
+}
+
+func TestSearchMulti_IncludeAdult(t *testing.T) {
+	var gotAdult []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAdult = append(gotAdult, r.URL.Query().Get("include_adult"))
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":1,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	for _, includeAdult := range []bool{false, true} {
+		if _, err := client.SearchMulti(context.Background(), "matrix", includeAdult); err != nil {
+			t.Fatalf("SearchMulti failed: %v", err)
+		}
+	}
+
+	if len(gotAdult) != 2 || gotAdult[0] != "false" || gotAdult[1] != "true" {
+		t.Errorf("expected include_adult to follow the preference, got %v", gotAdult)
+	}
+}
+
+func TestSearchMulti_EmptyQuery(t *testing.T) {
+	client := NewClient("test-key")
+
+	if _, err := client.SearchMulti(context.Background(), "", false); err == nil {
+		t.Error("expected error for empty query, got nil")
+	}
+}
+
+func TestWithMetrics_CountsCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page":1,"results":[],"total_pages":1,"total_results":0}`))
+	}))
+	defer server.Close()
+
+	registry := metrics.NewRegistry()
+	client := NewClient("test-key", WithBaseURL(server.URL), WithMetrics(registry))
+
+	if _, err := client.SearchMovie("Alien"); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+	if _, err := client.GetSimilarMovies(context.Background(), 348); err != nil {
+		t.Fatalf("GetSimilarMovies failed: %v", err)
+	}
+
+	if got := registry.Snapshot().UpstreamCalls[metrics.UpstreamTMDB]; got != 2 {
+		t.Errorf("expected 2 TMDB calls, got %d", got)
+	}
+}
+
+func TestNewClientWithTimeout_SlowServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout("test-key", 20*time.Millisecond, WithBaseURL(server.URL))
+
+	_, err := client.SearchMovie("slow")
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+}